@@ -31,7 +31,7 @@ var (
 
 // ContractMetaData contains all meta data concerning the Contract contract.
 var ContractMetaData = &bind.MetaData{
-	ABI: "[{\"type\":\"fallback\",\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"proxy\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"}]",
+	ABI: "[{\"type\":\"fallback\",\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"owner\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"proxy\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"upgradeProxy\",\"inputs\":[{\"name\":\"newLogic\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"data\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"}]",
 	Bin: "0x608060405234801561001057600080fd5b5061012a806100206000396000f3fe6080604052348015600f57600080fd5b506004361060285760003560e01c8063ec556889146030575b602e605e565b005b6000546042906001600160a01b031681565b6040516001600160a01b03909116815260200160405180910390f35b6000546001600160a01b031660b95760405162461bcd60e51b815260206004820152601d60248201527f4172636850726f787941646d696e3a2070726f7879206e6f7420736574000000604482015260640160405180910390fd5b60005460cc906001600160a01b031660cf565b50565b60603660008037600080366000855afa3d6000803e80801560ef573d6000f35b3d6000fdfea26469706673582212200380162fa480fb6a713fb4fa3df3f0e18f8ddc79f89abc002eaa5baa5997f32764736f6c63430008180033",
 }
 
@@ -202,6 +202,37 @@ func (_Contract *ContractTransactorRaw) Transact(opts *bind.TransactOpts, method
 	return _Contract.Contract.contract.Transact(opts, method, params...)
 }
 
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_Contract *ContractCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_Contract *ContractSession) Owner() (common.Address, error) {
+	return _Contract.Contract.Owner(&_Contract.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_Contract *ContractCallerSession) Owner() (common.Address, error) {
+	return _Contract.Contract.Owner(&_Contract.CallOpts)
+}
+
 // Proxy is a free data retrieval call binding the contract method 0xec556889.
 //
 // Solidity: function proxy() view returns(address)
@@ -233,6 +264,27 @@ func (_Contract *ContractCallerSession) Proxy() (common.Address, error) {
 	return _Contract.Contract.Proxy(&_Contract.CallOpts)
 }
 
+// UpgradeProxy is a paid mutator transaction binding the contract method 0x1f8ab9ff.
+//
+// Solidity: function upgradeProxy(address newLogic, bytes data) returns()
+func (_Contract *ContractTransactor) UpgradeProxy(opts *bind.TransactOpts, newLogic common.Address, data []byte) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "upgradeProxy", newLogic, data)
+}
+
+// UpgradeProxy is a paid mutator transaction binding the contract method 0x1f8ab9ff.
+//
+// Solidity: function upgradeProxy(address newLogic, bytes data) returns()
+func (_Contract *ContractSession) UpgradeProxy(newLogic common.Address, data []byte) (*types.Transaction, error) {
+	return _Contract.Contract.UpgradeProxy(&_Contract.TransactOpts, newLogic, data)
+}
+
+// UpgradeProxy is a paid mutator transaction binding the contract method 0x1f8ab9ff.
+//
+// Solidity: function upgradeProxy(address newLogic, bytes data) returns()
+func (_Contract *ContractTransactorSession) UpgradeProxy(newLogic common.Address, data []byte) (*types.Transaction, error) {
+	return _Contract.Contract.UpgradeProxy(&_Contract.TransactOpts, newLogic, data)
+}
+
 // Fallback is a paid mutator transaction binding the contract fallback function.
 //
 // Solidity: fallback() returns()
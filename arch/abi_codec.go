@@ -0,0 +1,380 @@
+package arch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+// ABICodec is implemented by the action and table row types generated by gogen to ABI-encode and
+// decode themselves without reflection. The encoding is the same abi.Arguments.Pack produces for the
+// struct as the tuple argument of its action method or the tuple return value of its table method.
+// Keyed actions are encoded as the key arguments of their action method followed by the tuple argument.
+type ABICodec interface {
+	EncodeABI() []byte
+	DecodeABI(data []byte) error
+}
+
+var errABIDataTooShort = errors.New("abi: data too short")
+
+// ABIEncoder ABI-encodes the fields of a tuple in order, optionally preceded by static keys.
+type ABIEncoder struct {
+	keys    []byte
+	head    []byte
+	tail    []byte
+	nKeys   int
+	nFields int
+	dynamic bool
+}
+
+// NewABIEncoder creates a new ABIEncoder for a tuple with the given number of fields.
+// A tuple is dynamic if any of its fields is of a dynamic type, i.e., bytes or string.
+func NewABIEncoder(nFields int, dynamic bool) *ABIEncoder {
+	return &ABIEncoder{
+		head:    make([]byte, 0, 32*nFields),
+		nFields: nFields,
+		dynamic: dynamic,
+	}
+}
+
+// NewKeyedABIEncoder creates a new ABIEncoder for the given number of keys followed by a tuple with the
+// given number of fields. The first nKeys values written are the keys, which must be of static types.
+func NewKeyedABIEncoder(nKeys, nFields int, dynamic bool) *ABIEncoder {
+	e := NewABIEncoder(nFields, dynamic)
+	e.keys = make([]byte, 0, 32*nKeys)
+	e.nKeys = nKeys
+	return e
+}
+
+// appendWord appends a 32-byte word to the keys until all keys are written, and to the head of the
+// tuple afterwards.
+func (e *ABIEncoder) appendWord(word []byte) {
+	if len(e.keys) < 32*e.nKeys {
+		e.keys = append(e.keys, word...)
+		return
+	}
+	e.head = append(e.head, word...)
+}
+
+func (e *ABIEncoder) writeWord(word []byte) {
+	var padded [32]byte
+	copy(padded[32-len(word):], word)
+	e.appendWord(padded[:])
+}
+
+// WriteInt writes a signed integer of up to 64 bits.
+func (e *ABIEncoder) WriteInt(value int64) {
+	var word [32]byte
+	if value < 0 {
+		for ii := range word[:24] {
+			word[ii] = 0xff
+		}
+	}
+	binary.BigEndian.PutUint64(word[24:], uint64(value))
+	e.appendWord(word[:])
+}
+
+// WriteUint writes an unsigned integer of up to 64 bits.
+func (e *ABIEncoder) WriteUint(value uint64) {
+	var word [8]byte
+	binary.BigEndian.PutUint64(word[:], value)
+	e.writeWord(word[:])
+}
+
+// WriteUint256 writes a uint256. A nil value is written as zero.
+func (e *ABIEncoder) WriteUint256(value *uint256.Int) {
+	if value == nil {
+		e.writeWord(nil)
+		return
+	}
+	word := value.Bytes32()
+	e.appendWord(word[:])
+}
+
+// WriteBool writes a bool.
+func (e *ABIEncoder) WriteBool(value bool) {
+	if value {
+		e.writeWord([]byte{1})
+	} else {
+		e.writeWord(nil)
+	}
+}
+
+// WriteAddress writes an address.
+func (e *ABIEncoder) WriteAddress(value common.Address) {
+	e.writeWord(value.Bytes())
+}
+
+// WriteHash writes a bytes32.
+func (e *ABIEncoder) WriteHash(value common.Hash) {
+	e.appendWord(value.Bytes())
+}
+
+// WriteFixedBytes writes a bytesN with N < 32, right-padded with zeros.
+func (e *ABIEncoder) WriteFixedBytes(value []byte) {
+	e.appendWord(common.RightPadBytes(value, 32))
+}
+
+// WriteBytes writes a bytes.
+func (e *ABIEncoder) WriteBytes(value []byte) {
+	// The head holds the offset of the value from the start of the tuple
+	e.WriteUint(uint64(32*e.nFields + len(e.tail)))
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(value)))
+	e.tail = append(e.tail, common.LeftPadBytes(length[:], 32)...)
+	e.tail = append(e.tail, common.RightPadBytes(value, (len(value)+31)/32*32)...)
+}
+
+// WriteString writes a string.
+func (e *ABIEncoder) WriteString(value string) {
+	e.WriteBytes([]byte(value))
+}
+
+// Encoded returns the encoded keys and tuple. Dynamic tuples are preceded by their offset, as they are
+// when packed as the last argument or the only return value of a method.
+func (e *ABIEncoder) Encoded() []byte {
+	encoded := make([]byte, 0, len(e.keys)+32+len(e.head)+len(e.tail))
+	encoded = append(encoded, e.keys...)
+	if e.dynamic {
+		var offset [32]byte
+		binary.BigEndian.PutUint64(offset[24:], uint64(32*(e.nKeys+1)))
+		encoded = append(encoded, offset[:]...)
+	}
+	encoded = append(encoded, e.head...)
+	return append(encoded, e.tail...)
+}
+
+// ABIDecoder decodes the fields of an ABI-encoded tuple in order, optionally preceded by static keys.
+// Decoding errors are sticky: once a field fails to decode, the following fields decode to their zero
+// value and Err returns the first error.
+type ABIDecoder struct {
+	keys     []byte // Keys encoding
+	keyIndex int    // Index of the next key
+	data     []byte // Tuple encoding
+	index    int    // Index of the next field
+	err      error
+}
+
+// NewABIDecoder creates a new ABIDecoder for a tuple with the given number of fields, encoded as the
+// only argument or return value of a method.
+func NewABIDecoder(data []byte, nFields int, dynamic bool) *ABIDecoder {
+	return NewKeyedABIDecoder(data, 0, nFields, dynamic)
+}
+
+// NewKeyedABIDecoder creates a new ABIDecoder for the given number of static keys followed by a tuple
+// with the given number of fields, encoded as the arguments of a method. The first nKeys values read
+// are the keys.
+func NewKeyedABIDecoder(data []byte, nKeys, nFields int, dynamic bool) *ABIDecoder {
+	d := &ABIDecoder{data: data}
+	if nKeys+nFields > 0 && len(data) == 0 {
+		d.err = errors.New("abi: attempting to unmarshal an empty string while arguments are expected")
+		return d
+	}
+	if len(data) < 32*nKeys {
+		d.fail(errABIDataTooShort)
+		return d
+	}
+	d.keys = data[:32*nKeys]
+	if dynamic {
+		offset, ok := d.readOffset(32 * nKeys)
+		if !ok {
+			return d
+		}
+		d.data = data[offset:]
+	} else {
+		d.data = data[32*nKeys:]
+	}
+	if len(d.data) < 32*nFields {
+		d.fail(errABIDataTooShort)
+	}
+	return d
+}
+
+// Err returns the first decoding error, if any.
+func (d *ABIDecoder) Err() error {
+	return d.err
+}
+
+func (d *ABIDecoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// readOffset reads the word at the given position of the data as an offset into the data.
+func (d *ABIDecoder) readOffset(pos int) (int, bool) {
+	if pos+32 > len(d.data) {
+		d.fail(errABIDataTooShort)
+		return 0, false
+	}
+	word := d.data[pos : pos+32]
+	for _, b := range word[:24] {
+		if b != 0 {
+			d.fail(errors.New("abi: offset too large"))
+			return 0, false
+		}
+	}
+	offset := binary.BigEndian.Uint64(word[24:])
+	if offset > uint64(len(d.data)) {
+		d.fail(fmt.Errorf("abi: offset %d would go over data boundary (len=%d)", offset, len(d.data)))
+		return 0, false
+	}
+	return int(offset), true
+}
+
+// nextWord returns the next key, or the head word of the next field once all keys are read, or nil
+// after an error.
+func (d *ABIDecoder) nextWord() []byte {
+	if d.err != nil {
+		return nil
+	}
+	if pos := 32 * d.keyIndex; pos < len(d.keys) {
+		d.keyIndex++
+		return d.keys[pos : pos+32]
+	}
+	pos := 32 * d.index
+	d.index++
+	if pos+32 > len(d.data) {
+		d.fail(errABIDataTooShort)
+		return nil
+	}
+	return d.data[pos : pos+32]
+}
+
+// ReadInt reads a signed integer of the given number of bits, up to 64.
+func (d *ABIDecoder) ReadInt(bits int) int64 {
+	word := d.nextWord()
+	if word == nil {
+		return 0
+	}
+	value := int64(binary.BigEndian.Uint64(word[24:]))
+	// The value must be sign-extended and fit in the given number of bits
+	var ext byte
+	if value < 0 {
+		ext = 0xff
+	}
+	for _, b := range word[:24] {
+		if b != ext {
+			d.fail(fmt.Errorf("abi: cannot unmarshal %x in to int%d", word, bits))
+			return 0
+		}
+	}
+	if bits < 64 && (value < -1<<(bits-1) || value > 1<<(bits-1)-1) {
+		d.fail(fmt.Errorf("abi: cannot unmarshal %x in to int%d", word, bits))
+		return 0
+	}
+	return value
+}
+
+// ReadUint reads an unsigned integer of the given number of bits, up to 64.
+func (d *ABIDecoder) ReadUint(bits int) uint64 {
+	word := d.nextWord()
+	if word == nil {
+		return 0
+	}
+	for _, b := range word[:24] {
+		if b != 0 {
+			d.fail(fmt.Errorf("abi: cannot unmarshal %x in to uint%d", word, bits))
+			return 0
+		}
+	}
+	value := binary.BigEndian.Uint64(word[24:])
+	if bits < 64 && value > uint64(math.MaxUint64)>>(64-bits) {
+		d.fail(fmt.Errorf("abi: cannot unmarshal %x in to uint%d", word, bits))
+		return 0
+	}
+	return value
+}
+
+// ReadUint256 reads a uint256.
+func (d *ABIDecoder) ReadUint256() *uint256.Int {
+	word := d.nextWord()
+	if word == nil {
+		return new(uint256.Int)
+	}
+	return new(uint256.Int).SetBytes32(word)
+}
+
+// ReadBool reads a bool.
+func (d *ABIDecoder) ReadBool() bool {
+	word := d.nextWord()
+	if word == nil {
+		return false
+	}
+	for _, b := range word[:31] {
+		if b != 0 {
+			d.fail(errors.New("abi: improperly encoded boolean value"))
+			return false
+		}
+	}
+	switch word[31] {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		d.fail(errors.New("abi: improperly encoded boolean value"))
+		return false
+	}
+}
+
+// ReadAddress reads an address.
+func (d *ABIDecoder) ReadAddress() common.Address {
+	word := d.nextWord()
+	if word == nil {
+		return common.Address{}
+	}
+	return common.BytesToAddress(word)
+}
+
+// ReadHash reads a bytes32.
+func (d *ABIDecoder) ReadHash() common.Hash {
+	word := d.nextWord()
+	if word == nil {
+		return common.Hash{}
+	}
+	return common.BytesToHash(word)
+}
+
+// ReadFixedBytes reads a bytesN with N < 32.
+func (d *ABIDecoder) ReadFixedBytes(size int) []byte {
+	word := d.nextWord()
+	if word == nil {
+		return make([]byte, size)
+	}
+	return common.CopyBytes(word[:size])
+}
+
+// ReadBytes reads a bytes.
+func (d *ABIDecoder) ReadBytes() []byte {
+	if d.err != nil {
+		return []byte{}
+	}
+	pos := 32 * d.index
+	if d.nextWord() == nil {
+		return []byte{}
+	}
+	offset, ok := d.readOffset(pos)
+	if !ok {
+		return []byte{}
+	}
+	length, ok := d.readOffset(offset)
+	if !ok {
+		return []byte{}
+	}
+	start := offset + 32
+	if start+length > len(d.data) {
+		d.fail(fmt.Errorf("abi: length %d would go over data boundary (len=%d)", length, len(d.data)))
+		return []byte{}
+	}
+	return common.CopyBytes(d.data[start : start+length])
+}
+
+// ReadString reads a string.
+func (d *ABIDecoder) ReadString() string {
+	return string(d.ReadBytes())
+}
@@ -0,0 +1,272 @@
+package arch
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Struct and codec written like the gogen generated code
+
+type testCodecStruct struct {
+	A int8
+	B int64
+	C uint16
+	D uint64
+	E bool
+	F common.Address
+	G string
+	H []byte
+}
+
+func (v *testCodecStruct) EncodeABI() []byte {
+	enc := NewABIEncoder(8, true)
+	enc.WriteInt(int64(v.A))
+	enc.WriteInt(int64(v.B))
+	enc.WriteUint(uint64(v.C))
+	enc.WriteUint(uint64(v.D))
+	enc.WriteBool(v.E)
+	enc.WriteAddress(v.F)
+	enc.WriteString(v.G)
+	enc.WriteBytes(v.H)
+	return enc.Encoded()
+}
+
+func (v *testCodecStruct) DecodeABI(data []byte) error {
+	dec := NewABIDecoder(data, 8, true)
+	v.A = int8(dec.ReadInt(8))
+	v.B = int64(dec.ReadInt(64))
+	v.C = uint16(dec.ReadUint(16))
+	v.D = uint64(dec.ReadUint(64))
+	v.E = dec.ReadBool()
+	v.F = dec.ReadAddress()
+	v.G = dec.ReadString()
+	v.H = dec.ReadBytes()
+	return dec.Err()
+}
+
+type testStaticCodecStruct struct {
+	A int32
+	B uint8
+}
+
+func (v *testStaticCodecStruct) EncodeABI() []byte {
+	enc := NewABIEncoder(2, false)
+	enc.WriteInt(int64(v.A))
+	enc.WriteUint(uint64(v.B))
+	return enc.Encoded()
+}
+
+func (v *testStaticCodecStruct) DecodeABI(data []byte) error {
+	dec := NewABIDecoder(data, 2, false)
+	v.A = int32(dec.ReadInt(32))
+	v.B = uint8(dec.ReadUint(8))
+	return dec.Err()
+}
+
+const testCodecABIJson = `[
+	{"type":"function","name":"dynamic","inputs":[{"name":"v","type":"tuple","components":[
+		{"name":"a","type":"int8"},{"name":"b","type":"int64"},{"name":"c","type":"uint16"},
+		{"name":"d","type":"uint64"},{"name":"e","type":"bool"},{"name":"f","type":"address"},
+		{"name":"g","type":"string"},{"name":"h","type":"bytes"}
+	]}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"static","inputs":[{"name":"v","type":"tuple","components":[
+		{"name":"a","type":"int32"},{"name":"b","type":"uint8"}
+	]}],"outputs":[],"stateMutability":"nonpayable"}
+]`
+
+func TestABICodec(t *testing.T) {
+	ABI, err := abi.JSON(strings.NewReader(testCodecABIJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testData := []struct {
+		method string
+		value  ABICodec
+	}{
+		{"dynamic", &testCodecStruct{H: []byte{}}},
+		{"dynamic", &testCodecStruct{
+			A: math.MinInt8, B: math.MinInt64, C: math.MaxUint16, D: math.MaxUint64,
+			E: true, F: common.Address{0xaa},
+			G: "hello", H: bytes.Repeat([]byte{0xcc}, 33),
+		}},
+		{"dynamic", &testCodecStruct{
+			A: math.MaxInt8, B: math.MaxInt64, C: 1, D: 2,
+			G: strings.Repeat("a", 64), H: []byte{1},
+		}},
+		{"static", &testStaticCodecStruct{}},
+		{"static", &testStaticCodecStruct{A: -1, B: math.MaxUint8}},
+		{"static", &testStaticCodecStruct{A: math.MinInt32, B: 1}},
+	}
+	for _, tt := range testData {
+		inputs := ABI.Methods[tt.method].Inputs
+
+		// Encoding matches the reflective path
+		expData, err := inputs.Pack(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := tt.value.EncodeABI()
+		if !bytes.Equal(data, expData) {
+			t.Errorf("%+v: expected encoding %x, got %x", tt.value, expData, data)
+		}
+
+		// Decoding matches the reflective path
+		args, err := inputs.Unpack(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expValue := reflect.New(reflect.TypeOf(tt.value).Elem()).Interface()
+		if err := ConvertStruct(expValue, args[0]); err != nil {
+			t.Fatal(err)
+		}
+		value := reflect.New(reflect.TypeOf(tt.value).Elem()).Interface().(ABICodec)
+		if err := value.DecodeABI(data); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(value, expValue) {
+			t.Errorf("expected decoded %+v, got %+v", expValue, value)
+		}
+		if !reflect.DeepEqual(value, tt.value) {
+			t.Errorf("expected decoded %+v, got %+v", tt.value, value)
+		}
+	}
+}
+
+func TestABICodecInvalidData(t *testing.T) {
+	var (
+		valid    = (&testStaticCodecStruct{A: 1, B: 2}).EncodeABI()
+		dynValid = (&testCodecStruct{G: "hello"}).EncodeABI()
+	)
+	outOfRange := common.CopyBytes(valid)
+	outOfRange[62] = 1 // B = 258
+	notSignExtended := common.CopyBytes(valid)
+	notSignExtended[0] = 0xff
+	badOffset := common.CopyBytes(dynValid)
+	badOffset[31] = 0xff
+
+	testData := []struct {
+		name  string
+		data  []byte
+		value ABICodec
+	}{
+		{"empty", nil, &testStaticCodecStruct{}},
+		{"truncated", valid[:40], &testStaticCodecStruct{}},
+		{"uint out of range", outOfRange, &testStaticCodecStruct{}},
+		{"int not sign-extended", notSignExtended, &testStaticCodecStruct{}},
+		{"truncated dynamic", dynValid[:len(dynValid)-32], &testCodecStruct{}},
+		{"bad tuple offset", badOffset, &testCodecStruct{}},
+	}
+	for _, tt := range testData {
+		if err := tt.value.DecodeABI(tt.data); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+type testKeyedCodecStruct struct {
+	Id     uint8
+	Player common.Hash
+	X      int32
+	Name   string
+}
+
+func (v *testKeyedCodecStruct) EncodeABI() []byte {
+	enc := NewKeyedABIEncoder(2, 2, true)
+	enc.WriteUint(uint64(v.Id))
+	enc.WriteHash(v.Player)
+	enc.WriteInt(int64(v.X))
+	enc.WriteString(v.Name)
+	return enc.Encoded()
+}
+
+func (v *testKeyedCodecStruct) DecodeABI(data []byte) error {
+	dec := NewKeyedABIDecoder(data, 2, 2, true)
+	v.Id = uint8(dec.ReadUint(8))
+	v.Player = dec.ReadHash()
+	v.X = int32(dec.ReadInt(32))
+	v.Name = dec.ReadString()
+	return dec.Err()
+}
+
+type testKeyedStaticCodecStruct struct {
+	Id uint8
+	X  int32
+}
+
+func (v *testKeyedStaticCodecStruct) EncodeABI() []byte {
+	enc := NewKeyedABIEncoder(1, 1, false)
+	enc.WriteUint(uint64(v.Id))
+	enc.WriteInt(int64(v.X))
+	return enc.Encoded()
+}
+
+func (v *testKeyedStaticCodecStruct) DecodeABI(data []byte) error {
+	dec := NewKeyedABIDecoder(data, 1, 1, false)
+	v.Id = uint8(dec.ReadUint(8))
+	v.X = int32(dec.ReadInt(32))
+	return dec.Err()
+}
+
+const testKeyedCodecABIJson = `[
+	{"type":"function","name":"dynamic","inputs":[
+		{"name":"id","type":"uint8"},{"name":"player","type":"bytes32"},
+		{"name":"v","type":"tuple","components":[{"name":"x","type":"int32"},{"name":"name","type":"string"}]}
+	],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"static","inputs":[
+		{"name":"id","type":"uint8"},
+		{"name":"v","type":"tuple","components":[{"name":"x","type":"int32"}]}
+	],"outputs":[],"stateMutability":"nonpayable"}
+]`
+
+func TestKeyedABICodec(t *testing.T) {
+	ABI, err := abi.JSON(strings.NewReader(testKeyedCodecABIJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testData := []struct {
+		method string
+		value  ABICodec
+		args   []interface{}
+	}{
+		{
+			"dynamic",
+			&testKeyedCodecStruct{Id: 1, Player: common.Hash{0xaa}, X: -1, Name: "hello"},
+			[]interface{}{uint8(1), [32]byte{0xaa}, struct {
+				X    int32
+				Name string
+			}{-1, "hello"}},
+		},
+		{
+			"static",
+			&testKeyedStaticCodecStruct{Id: math.MaxUint8, X: math.MinInt32},
+			[]interface{}{uint8(math.MaxUint8), struct{ X int32 }{math.MinInt32}},
+		},
+	}
+	for _, tt := range testData {
+		// Keys are encoded as the leading arguments of the method
+		expData, err := ABI.Methods[tt.method].Inputs.Pack(tt.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := tt.value.EncodeABI()
+		if !bytes.Equal(data, expData) {
+			t.Errorf("%+v: expected encoding %x, got %x", tt.value, expData, data)
+		}
+		value := reflect.New(reflect.TypeOf(tt.value).Elem()).Interface().(ABICodec)
+		if err := value.DecodeABI(data); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(value, tt.value) {
+			t.Errorf("expected decoded %+v, got %+v", tt.value, value)
+		}
+		if err := value.DecodeABI(data[:40]); err == nil {
+			t.Errorf("%+v: expected error decoding truncated data", tt.value)
+		}
+	}
+}
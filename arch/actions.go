@@ -1,6 +1,7 @@
 package arch
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -11,7 +12,10 @@ type CanonicalTickAction struct{}
 // Holds all the actions included to a specific core in a specific block
 type ActionBatch struct {
 	BlockNumber uint64
+	BlockHash   common.Hash // Zero if unknown
+	ReorgDepth  uint64      // Number of previously sent batches orphaned by a chain reorganization
 	Actions     []Action
+	Contexts    []ExecutionContext // Execution context of each action, nil if unknown
 }
 
 // Len returns the number of actions in the batch.
@@ -19,11 +23,24 @@ func (a ActionBatch) Len() int {
 	return len(a.Actions)
 }
 
+// IsReorg returns whether the batch must be preceded by unwinding previously applied batches.
+func (a ActionBatch) IsReorg() bool {
+	return a.ReorgDepth > 0
+}
+
 // NewActionBatch creates a new ActionBatch instance.
 func NewActionBatch(blockNumber uint64, actions []Action) ActionBatch {
 	return ActionBatch{BlockNumber: blockNumber, Actions: actions}
 }
 
+// Context returns the execution context of the action at the given index, or a zero context if unknown.
+func (a ActionBatch) Context(index int) ExecutionContext {
+	if index < len(a.Contexts) {
+		return a.Contexts[index]
+	}
+	return ExecutionContext{}
+}
+
 type ActionBatchWithLogs struct {
 	ActionBatch
 	Logs []types.Log
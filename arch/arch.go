@@ -1,9 +1,11 @@
 package arch
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -20,6 +22,7 @@ var (
 	// ErrInvalidActionId = errors.New("invalid action ID")
 	// ErrInvalidTableId         = errors.New("invalid table ID")
 	ErrCalldataIsNotTableRead = errors.New("calldata is not a table read operation")
+	ErrNoTableGetters         = errors.New("table schemas have no table getters")
 )
 
 type RawIdType = [4]byte
@@ -107,6 +110,16 @@ func (a archSchemas) ABI() *abi.ABI {
 	return a.abi
 }
 
+// names returns the names of all schemas, sorted.
+func (a archSchemas) names() []string {
+	names := make([]string, 0, len(a.schemas))
+	for _, schema := range a.schemas {
+		names = append(names, schema.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 type ValidActionId struct {
 	validId
 }
@@ -117,6 +130,33 @@ type ActionSchema struct {
 
 type ActionSchemas struct {
 	archSchemas
+	errors      map[RawIdType]actionErrorSchema
+	versions    []actionSchemasVersion // Sorted by activation block, nil if the schemas are not versioned
+	converter   ActionConverter        // Converts actions of this version to the next one, if any
+	dispatcher  ActionDispatcher
+	dispatchIds map[string]RawIdType  // Action name -> ID of the actions dispatched by the dispatcher
+	permissions map[string]Permission // Action name -> permission, actions without a permission are open
+}
+
+// ActionDispatcher matches and executes actions of generated types without reflection.
+// Implementations are generated by gogen as a switch on the action types of a game, calling the
+// action methods of the generated ActionHandler interface.
+type ActionDispatcher interface {
+	// ActionName returns the schema name of the action, or false if the action type is not dispatched.
+	ActionName(action Action) (string, bool)
+	// DispatchAction executes the action on the target, returning false if the action type is not
+	// dispatched or the target does not implement the action methods.
+	DispatchAction(action Action, target Core) (bool, error)
+}
+
+// ActionConverter converts an action of a version of the action schemas to the type of the same action
+// in the next version, e.g., setting the fields added in the next version to their defaults.
+// Actions of types it does not convert must be returned unchanged.
+type ActionConverter func(action Action) (Action, error)
+
+type actionSchemasVersion struct {
+	activationBlock uint64
+	schemas         ActionSchemas
 }
 
 // NewActionSchemas creates a new ActionSchemas instance.
@@ -139,11 +179,11 @@ func NewActionSchemas(
 		return ActionSchemas{}, err
 	}
 	for _, schema := range s.schemas {
-		if len(schema.Method.Inputs) > 1 {
-			return ActionSchemas{}, fmt.Errorf("action method %s has more than one argument", schema.Name)
+		if err := checkActionMethod(schema); err != nil {
+			return ActionSchemas{}, err
 		}
 	}
-	return ActionSchemas{archSchemas: s}, nil
+	return ActionSchemas{archSchemas: s, errors: make(map[RawIdType]actionErrorSchema)}, nil
 }
 
 // NewActionSchemasFromRaw creates a new ActionSchemas instance from raw JSON strings.
@@ -162,7 +202,78 @@ func NewActionSchemasFromRaw(
 	if err != nil {
 		return ActionSchemas{}, err
 	}
-	return NewActionSchemas(&ABI, schemas, types)
+	// Load the action permissions
+	permissions, err := UnmarshalPermissions([]byte(schemasJson))
+	if err != nil {
+		return ActionSchemas{}, err
+	}
+	actionSchemas, err := NewActionSchemas(&ABI, schemas, types)
+	if err != nil {
+		return ActionSchemas{}, err
+	}
+	actionSchemas.SetPermissions(permissions)
+	return actionSchemas, nil
+}
+
+// NewVersionedActionSchemas creates an ActionSchemas instance from several versions of the action
+// schemas keyed by the block number they were activated at.
+// The returned schemas are the latest version. Action logs are decoded with the version active at the
+// block the log was emitted at, i.e., the version with the highest activation block not after it, so
+// logs emitted before the schemas changed can still be decoded. Actions of older versions are converted
+// to the latest version before they are executed with the converters of the versions, if set.
+func NewVersionedActionSchemas(versions map[uint64]ActionSchemas) (ActionSchemas, error) {
+	if len(versions) == 0 {
+		return ActionSchemas{}, errors.New("no action schema versions")
+	}
+	sorted := make([]actionSchemasVersion, 0, len(versions))
+	for activationBlock, schemas := range versions {
+		if schemas.versions != nil {
+			return ActionSchemas{}, fmt.Errorf("action schemas activated at block %d are already versioned", activationBlock)
+		}
+		sorted = append(sorted, actionSchemasVersion{activationBlock: activationBlock, schemas: schemas})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].activationBlock < sorted[j].activationBlock
+	})
+	latest := sorted[len(sorted)-1].schemas
+	latest.versions = sorted
+	return latest, nil
+}
+
+// AtBlock returns the version of the action schemas active at the given block number.
+// Schemas that are not versioned are active at every block. Blocks before the activation of the first
+// version use the first version.
+func (a ActionSchemas) AtBlock(blockNumber uint64) ActionSchemas {
+	if a.versions == nil {
+		return a
+	}
+	// Index of the first version activated after the block
+	idx := sort.Search(len(a.versions), func(i int) bool {
+		return a.versions[i].activationBlock > blockNumber
+	})
+	if idx == 0 {
+		return a.versions[0].schemas
+	}
+	return a.versions[idx-1].schemas
+}
+
+// SetActionConverter sets the converter used to convert actions of this version of the schemas to the
+// next version when executed with versioned schemas, so actions decoded from logs emitted before the
+// schemas changed can be executed by the action methods of the latest version.
+// It must be set before the schemas are passed to NewVersionedActionSchemas.
+func (a *ActionSchemas) SetActionConverter(converter ActionConverter) {
+	a.converter = converter
+}
+
+// SetActionDispatcher sets the dispatcher used to match and execute actions instead of reflection.
+// Actions the dispatcher does not handle, e.g., of an older version of the schemas, fall back to
+// reflection.
+func (a *ActionSchemas) SetActionDispatcher(dispatcher ActionDispatcher) {
+	a.dispatcher = dispatcher
+	a.dispatchIds = make(map[string]RawIdType, len(a.schemas))
+	for id, schema := range a.schemas {
+		a.dispatchIds[schema.Name] = id
+	}
 }
 
 // NewActionId wraps a valid ID in a ValidActionId.
@@ -179,6 +290,22 @@ func (a ActionSchemas) ActionIdFromName(name string) (ValidActionId, bool) {
 
 // ActionIdFromAction returns the action ID of the given action.
 func (a ActionSchemas) ActionIdFromAction(action Action) (ValidActionId, bool) {
+	if a.dispatcher != nil {
+		name, ok := a.dispatcher.ActionName(action)
+		switch action.(type) {
+		case *CanonicalTickAction:
+			name, ok = params.TickActionName, true
+		case *GrantRoleAction:
+			name, ok = params.GrantRoleActionName, true
+		case *RevokeRoleAction:
+			name, ok = params.RevokeRoleActionName, true
+		}
+		if ok {
+			if id, ok := a.dispatchIds[name]; ok {
+				return ValidActionId{validId{id: id, valid: true}}, true
+			}
+		}
+	}
 	actionType := reflect.TypeOf(action)
 	if !isStructPtr(actionType) {
 		return ValidActionId{}, false
@@ -192,6 +319,11 @@ func (a ActionSchemas) ActionIdFromAction(action Action) (ValidActionId, bool) {
 	return ValidActionId{}, false
 }
 
+// ActionNames returns the names of all actions, sorted.
+func (a ActionSchemas) ActionNames() []string {
+	return a.names()
+}
+
 // GetActionSchema returns the schema of the action with the given ID.
 func (a ActionSchemas) GetActionSchema(actionId ValidActionId) ActionSchema {
 	return ActionSchema{a.archSchemas.getSchema(actionId.validId)}
@@ -204,7 +336,7 @@ func (a *ActionSchemas) EncodeAction(action Action) (ValidActionId, []byte, erro
 		return ValidActionId{}, nil, fmt.Errorf("action of type %T does not match any canonical action type", action)
 	}
 	schema := a.GetActionSchema(actionId)
-	data, err := packActionMethodInput(schema.Method, action)
+	data, err := packActionMethodInput(schema.archSchema, action)
 	if err != nil {
 		return ValidActionId{}, nil, err
 	}
@@ -214,16 +346,20 @@ func (a *ActionSchemas) EncodeAction(action Action) (ValidActionId, []byte, erro
 // DecodeAction decodes the given calldata into an action.
 func (a *ActionSchemas) DecodeAction(actionId ValidActionId, data []byte) (Action, error) {
 	schema := a.GetActionSchema(actionId)
+	action := reflect.New(schema.Type).Interface()
+	if codec, ok := action.(ABICodec); ok && len(schema.Method.Inputs) > 0 {
+		// Decode generated action types without reflection
+		if err := codec.DecodeABI(data); err != nil {
+			return nil, err
+		}
+		return action, nil
+	}
 	args, err := schema.Method.Inputs.Unpack(data)
 	if err != nil {
 		return nil, err
 	}
-	// Create a canonically typed action from the unpacked data
-	// i.e., anonymous struct{...} -> archmod.ActionData_<action name>{...}
-	// All methods are autogenerated to have a single argument, so we can safely assume len(args) == 1
-	action := reflect.New(schema.Type).Interface()
 	if len(args) > 0 {
-		if err := ConvertStruct(action, args[0]); err != nil {
+		if err := unpackActionMethodInput(schema.archSchema, action, args); err != nil {
 			return nil, err
 		}
 	}
@@ -238,7 +374,7 @@ func (a *ActionSchemas) ActionToCalldata(action Action) ([]byte, error) {
 		return nil, fmt.Errorf("action of type %T does not match any canonical action type", action)
 	}
 	schema := a.GetActionSchema(actionId)
-	data, err := packActionMethodInput(schema.Method, action)
+	data, err := packActionMethodInput(schema.archSchema, action)
 	if err != nil {
 		return nil, err
 	}
@@ -280,20 +416,84 @@ func (a *ActionSchemas) LogToAction(log types.Log) (Action, error) {
 	if len(log.Topics) != 1 || log.Topics[0] != params.ActionExecutedEventID {
 		return nil, errors.New("log topics do not match ActionExecuted event")
 	}
-	return a.CalldataToAction(log.Data)
+	schemas := a.AtBlock(log.BlockNumber)
+	return schemas.CalldataToAction(log.Data)
+}
+
+// actionSchema returns the schema of the given action in any version of the schemas, starting with
+// the latest.
+func (a *ActionSchemas) actionSchema(action Action) (ActionSchema, bool) {
+	if actionId, ok := a.ActionIdFromAction(action); ok {
+		return a.GetActionSchema(actionId), true
+	}
+	for ii := len(a.versions) - 1; ii >= 0; ii-- {
+		version := a.versions[ii].schemas
+		if actionId, ok := version.ActionIdFromAction(action); ok {
+			return version.GetActionSchema(actionId), true
+		}
+	}
+	return ActionSchema{}, false
+}
+
+// upgradeAction converts an action of an older version of the schemas to the latest version, one
+// version at a time, stopping at the first version without a converter.
+func (a *ActionSchemas) upgradeAction(action Action) (Action, error) {
+	if a.versions == nil {
+		return action, nil
+	}
+	if _, ok := a.ActionIdFromAction(action); ok {
+		return action, nil
+	}
+	for _, version := range a.versions[:len(a.versions)-1] {
+		if _, ok := version.schemas.ActionIdFromAction(action); !ok {
+			continue
+		}
+		if version.schemas.converter == nil {
+			break
+		}
+		var err error
+		if action, err = version.schemas.converter(action); err != nil {
+			return nil, fmt.Errorf("could not convert action from version activated at block %d: %w", version.activationBlock, err)
+		}
+	}
+	return action, nil
 }
 
 // ExecuteAction executes the given action on the given target.
+// Actions of older versions of versioned schemas are converted to the latest version first.
 func (a *ActionSchemas) ExecuteAction(action Action, target Core) error {
-	if _, ok := action.(*CanonicalTickAction); ok {
+	switch action := action.(type) {
+	case *CanonicalTickAction:
 		RunBlockTicks(target)
 		return nil
+	case *GrantRoleAction:
+		if !a.hasRoleActions() {
+			return ErrInvalidAction
+		}
+		setRole(target.KV(), action.Role, action.Account, true)
+		return nil
+	case *RevokeRoleAction:
+		if !a.hasRoleActions() {
+			return ErrInvalidAction
+		}
+		setRole(target.KV(), action.Role, action.Account, false)
+		return nil
 	}
-	actionId, ok := a.ActionIdFromAction(action)
+	action, err := a.upgradeAction(action)
+	if err != nil {
+		return err
+	}
+	if a.dispatcher != nil {
+		if handled, err := a.dispatcher.DispatchAction(action, target); handled {
+			return err
+		}
+	}
+	// Actions of an older version of the schemas that could not be converted are executed by the method
+	// of the action with the same name, if it takes the action type of that version
+	schema, ok := a.actionSchema(action)
 	if !ok {
 		return ErrInvalidAction
 	}
-	schema := a.GetActionSchema(actionId)
 	actionName := schema.Name
 	methodName := actionName
 	targetVal := reflect.ValueOf(target)
@@ -304,6 +504,9 @@ func (a *ActionSchemas) ExecuteAction(action Action, target Core) error {
 	if !method.IsValid() {
 		return fmt.Errorf("method %s not found", methodName)
 	}
+	if method.Type().NumIn() != 1 || !reflect.TypeOf(action).AssignableTo(method.Type().In(0)) {
+		return fmt.Errorf("method %s does not take actions of type %T", methodName, action)
+	}
 	args := []reflect.Value{reflect.ValueOf(action)}
 	result := method.Call(args)
 	if len(result) == 0 {
@@ -316,15 +519,145 @@ func (a *ActionSchemas) ExecuteAction(action Action, target Core) error {
 	return nil
 }
 
-func packActionMethodInput(method *abi.Method, arg interface{}) ([]byte, error) {
-	switch len(method.Inputs) {
-	case 0:
-		return method.Inputs.Pack()
-	case 1:
-		return method.Inputs.Pack(arg)
-	default:
-		panic("unreachable")
+// checkActionMethod returns an error if the method of an action does not take the keys of the action
+// followed by a tuple of its values, if any. Keys must be of static types.
+func checkActionMethod(schema archSchema) error {
+	for _, key := range schema.Keys {
+		if key.Type.Type == datamod.BytesType {
+			return fmt.Errorf("action %s has key %s of dynamic type %s", schema.Name, key.Name, key.Type.Name)
+		}
+	}
+	if len(schema.Method.ID) == 0 {
+		// The method is not in the ABI
+		return nil
+	}
+	nArgs := len(schema.Keys)
+	if len(schema.Values) > 0 {
+		nArgs++
+	}
+	if len(schema.Method.Inputs) != nArgs {
+		return fmt.Errorf("action method %s takes %d arguments, expected %d", schema.Method.Name, len(schema.Method.Inputs), nArgs)
 	}
+	return nil
+}
+
+// packActionMethodInput packs the action as the arguments of its action method, i.e., its keys followed
+// by the tuple of its values.
+func packActionMethodInput(schema archSchema, action interface{}) ([]byte, error) {
+	if len(schema.Method.Inputs) == 0 {
+		return schema.Method.Inputs.Pack()
+	}
+	if codec, ok := action.(ABICodec); ok {
+		// Encode generated action types without reflection
+		return codec.EncodeABI(), nil
+	}
+	args := make([]interface{}, 0, len(schema.Method.Inputs))
+	actionVal := reflect.Indirect(reflect.ValueOf(action))
+	for ii, key := range schema.Keys {
+		keyVal := actionVal.FieldByName(key.Title)
+		if !keyVal.IsValid() {
+			return nil, fmt.Errorf("action of type %T has no key %s", action, key.Title)
+		}
+		arg, err := toABIValue(keyVal, schema.Method.Inputs[ii].Type)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", key.Title, err)
+		}
+		args = append(args, arg)
+	}
+	if len(schema.Values) > 0 {
+		// The tuple argument is packed from the fields of the action matching its components
+		args = append(args, action)
+	}
+	return schema.Method.Inputs.Pack(args...)
+}
+
+// isSameKindConvertible returns whether a value of type src can be converted to type dst without changing
+// its representation, i.e., the types differ only in name.
+func isSameKindConvertible(src, dst reflect.Type) bool {
+	return src != dst && src.Kind() == dst.Kind() && src.ConvertibleTo(dst)
+}
+
+// isBytes returns whether typ is a slice or array of bytes.
+func isBytes(typ reflect.Type) bool {
+	return (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) && typ.Elem().Kind() == reflect.Uint8
+}
+
+// toABIValue converts a value of a generated type to the Go type the ABI packs for typ, e.g.,
+// common.Hash -> [32]byte, or []byte -> [4]byte for a bytes4, which is generated as a slice.
+// Values of other types are returned as is.
+func toABIValue(val reflect.Value, typ abi.Type) (interface{}, error) {
+	dst := typ.GetType()
+	switch {
+	case isSameKindConvertible(val.Type(), dst):
+		val = val.Convert(dst)
+	case typ.T == abi.FixedBytesTy && val.Kind() == reflect.Slice && isBytes(val.Type()):
+		if val.Len() > typ.Size {
+			return nil, fmt.Errorf("%d bytes do not fit in a bytes%d", val.Len(), typ.Size)
+		}
+		// Right-padded with zeros, like ABIEncoder.WriteFixedBytes
+		arr := reflect.New(dst).Elem()
+		reflect.Copy(arr, val)
+		val = arr
+	}
+	return val.Interface(), nil
+}
+
+// fromABIValue converts an unpacked ABI value to the given generated type, e.g., [32]byte ->
+// common.Hash, or [4]byte -> []byte for a bytes4.
+// Returns false if the value cannot be converted.
+func fromABIValue(val reflect.Value, dst reflect.Type) (reflect.Value, bool) {
+	switch {
+	case val.Type() == dst:
+		return val, true
+	case isSameKindConvertible(val.Type(), dst):
+		return val.Convert(dst), true
+	case val.Kind() == reflect.Array && dst.Kind() == reflect.Slice && isBytes(val.Type()) && isBytes(dst):
+		// Copy through an addressable array, as unpacked arrays may not be
+		arr := reflect.New(val.Type()).Elem()
+		arr.Set(val)
+		slice := reflect.MakeSlice(dst, val.Len(), val.Len())
+		reflect.Copy(slice, arr)
+		return slice, true
+	}
+	return reflect.Value{}, false
+}
+
+// unpackActionMethodInput sets the fields of the action to the unpacked arguments of its action method.
+func unpackActionMethodInput(schema archSchema, action interface{}, args []interface{}) error {
+	actionVal := reflect.ValueOf(action).Elem()
+	setField := func(name string, value interface{}) error {
+		field := actionVal.FieldByName(name)
+		if !field.IsValid() || !field.CanSet() {
+			return fmt.Errorf("field %s not found", name)
+		}
+		// e.g., [32]byte -> common.Hash
+		val, ok := fromABIValue(reflect.ValueOf(value), field.Type())
+		if !ok {
+			return fmt.Errorf("field %s has different type", name)
+		}
+		field.Set(val)
+		return nil
+	}
+	for ii, key := range schema.Keys {
+		if err := setField(key.Title, args[ii]); err != nil {
+			return err
+		}
+	}
+	if len(schema.Values) > 0 {
+		// Create a canonically typed action from the unpacked data
+		// i.e., anonymous struct{...} -> archmod.ActionData_<action name>{...}
+		tuple := reflect.ValueOf(args[len(schema.Keys)])
+		for _, value := range schema.Values {
+			tupleField := tuple.FieldByName(value.Title)
+			if !tupleField.IsValid() {
+				return fmt.Errorf("field %s not found", value.Title)
+			}
+			if err := setField(value.Title, tupleField.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 type ValidTableId struct {
@@ -411,6 +744,8 @@ type TableSchemas struct {
 }
 
 // NewTableSchemas creates a new TableSchemas instance.
+// If getters is nil, tables cannot be read from a datastore and the schemas can only be used to read
+// tables from the core contract, e.g., with rpc.TableGetter.
 func NewTableSchemas(
 	abi *abi.ABI,
 	schemas []datamod.TableSchema,
@@ -428,6 +763,10 @@ func NewTableSchemas(
 	}
 	tableGetters := make(map[RawIdType]tableGetter, len(getters))
 	for id, schema := range s.schemas {
+		if getters == nil {
+			// Tables can only be read from the core contract
+			continue
+		}
 		getterFn, ok := getters[schema.Name]
 		if !ok {
 			return TableSchemas{}, fmt.Errorf("no table getter found for schema %s", schema.Name)
@@ -472,6 +811,11 @@ func (t TableSchemas) TableIdFromName(name string) (ValidTableId, bool) {
 	return ValidTableId{validId}, ok
 }
 
+// TableNames returns the names of all tables, sorted.
+func (t TableSchemas) TableNames() []string {
+	return t.names()
+}
+
 // GetTableSchema returns the schema of the table with the given ID.
 func (t TableSchemas) GetTableSchema(tableId ValidTableId) TableSchema {
 	return TableSchema{t.archSchemas.getSchema(tableId.validId)}
@@ -479,7 +823,10 @@ func (t TableSchemas) GetTableSchema(tableId ValidTableId) TableSchema {
 
 // read reads a row from the datastore.
 func (t TableSchemas) Read(datastore lib.Datastore, tableId ValidTableId, keys ...interface{}) (interface{}, error) {
-	getter := t.tableGetters[tableId.Raw()]
+	getter, ok := t.tableGetters[tableId.Raw()]
+	if !ok {
+		return nil, ErrNoTableGetters
+	}
 	dsRow, err := getter.get(datastore, keys...)
 	if err != nil {
 		return nil, err
@@ -504,8 +851,20 @@ func (t *TableSchemas) TargetTableId(calldata []byte) (ValidTableId, bool) {
 	return tableId, ok
 }
 
+// IsMultiRead returns whether the given calldata encodes a batch of table reads, i.e., a call to the
+// multi-read method of the tables contract. Tables contracts generated without it do not support batches.
+func (t *TableSchemas) IsMultiRead(calldata []byte) bool {
+	method, ok := t.ABI().Methods[params.MultiTableReadMethodName]
+	return ok && len(calldata) >= 4 && bytes.Equal(calldata[:4], method.ID)
+}
+
 // ReadPacked reads a row from the datastore and packs it into an ABI-encoded byte slice.
+// If the calldata encodes a batch of table reads, every row is read and the packed rows are returned
+// ABI-encoded as a bytes array, in the same order.
 func (t *TableSchemas) ReadPacked(datastore lib.Datastore, calldata []byte) ([]byte, error) {
+	if t.IsMultiRead(calldata) {
+		return t.readMultiplePacked(datastore, calldata)
+	}
 	tableId, ok := t.TargetTableId(calldata)
 	if !ok {
 		return nil, ErrCalldataIsNotTableRead
@@ -519,10 +878,39 @@ func (t *TableSchemas) ReadPacked(datastore lib.Datastore, calldata []byte) ([]b
 	if err != nil {
 		return nil, err
 	}
+	if codec, ok := row.(ABICodec); ok {
+		// Encode generated row types without reflection
+		return codec.EncodeABI(), nil
+	}
 	return schema.Method.Outputs.Pack(row)
 }
 
+// readMultiplePacked reads the rows targeted by a batch of table reads.
+func (t *TableSchemas) readMultiplePacked(datastore lib.Datastore, calldata []byte) ([]byte, error) {
+	method := t.ABI().Methods[params.MultiTableReadMethodName]
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, err
+	}
+	calls, ok := args[0].([][]byte)
+	if !ok {
+		return nil, errors.New("invalid multi-read arguments")
+	}
+	results := make([][]byte, len(calls))
+	for ii, call := range calls {
+		if t.IsMultiRead(call) {
+			// Nested batches are not allowed
+			return nil, fmt.Errorf("read %d: %w", ii, ErrCalldataIsNotTableRead)
+		}
+		if results[ii], err = t.ReadPacked(datastore, call); err != nil {
+			return nil, fmt.Errorf("read %d: %w", ii, err)
+		}
+	}
+	return method.Outputs.Pack(results)
+}
+
 type ArchSchemas struct {
 	Actions ActionSchemas
 	Tables  TableSchemas
+	Events  EventSchemas
 }
@@ -1 +1,435 @@
 package arch
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type testActionData_AddV1 struct {
+	Summand int16 `json:"summand"`
+}
+
+type testActionData_AddV2 struct {
+	Summand int32 `json:"summand"`
+}
+
+const testAddV1ABIJson = `[{"type":"function","name":"add","inputs":[{"name":"action","type":"tuple","components":[{"name":"summand","type":"int16"}]}],"outputs":[],"stateMutability":"nonpayable"}]`
+
+const testAddV2ABIJson = `[{"type":"function","name":"add","inputs":[{"name":"action","type":"tuple","components":[{"name":"summand","type":"int32"}]}],"outputs":[],"stateMutability":"nonpayable"}]`
+
+type testVersionedCore struct {
+	BaseCore
+	sum int64
+}
+
+func (c *testVersionedCore) Add(action *testActionData_AddV2) error {
+	c.sum += int64(action.Summand)
+	return nil
+}
+
+func TestVersionedActionSchemas(t *testing.T) {
+	v1, err := NewActionSchemasFromRaw(
+		testAddV1ABIJson,
+		`{"add":{"schema":{"summand":"int16"}}}`,
+		map[string]reflect.Type{"Add": reflect.TypeOf(testActionData_AddV1{})},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := NewActionSchemasFromRaw(
+		testAddV2ABIJson,
+		`{"add":{"schema":{"summand":"int32"}}}`,
+		map[string]reflect.Type{"Add": reflect.TypeOf(testActionData_AddV2{})},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas, err := NewVersionedActionSchemas(map[uint64]ActionSchemas{0: v1, 10: v2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v1Log, err := v1.ActionToLog(&testActionData_AddV1{Summand: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2Log, err := v2.ActionToLog(&testActionData_AddV2{Summand: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Logs are decoded with the version active at the block they were emitted at
+	v1Log.BlockNumber = 9
+	action, err := schemas.LogToAction(v1Log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(action, &testActionData_AddV1{Summand: 1}) {
+		t.Errorf("expected %v, got %v", &testActionData_AddV1{Summand: 1}, action)
+	}
+	v2Log.BlockNumber = 10
+	action, err = schemas.LogToAction(v2Log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(action, &testActionData_AddV2{Summand: 2}) {
+		t.Errorf("expected %v, got %v", &testActionData_AddV2{Summand: 2}, action)
+	}
+	v1Log.BlockNumber = 10
+	if _, err := schemas.LogToAction(v1Log); err == nil {
+		t.Error("expected error decoding a log of an inactive version")
+	}
+
+	// New actions are encoded with the latest version
+	if _, err := schemas.ActionToCalldata(&testActionData_AddV2{}); err != nil {
+		t.Error(err)
+	}
+	if _, err := schemas.ActionToCalldata(&testActionData_AddV1{}); err == nil {
+		t.Error("expected error encoding an action of an old version")
+	}
+
+	// Actions are executed by the core method of the action if it takes their type
+	core := &testVersionedCore{}
+	if err := schemas.ExecuteAction(&testActionData_AddV2{Summand: 3}, core); err != nil {
+		t.Fatal(err)
+	}
+	if core.sum != 3 {
+		t.Errorf("expected sum %v, got %v", 3, core.sum)
+	}
+	if err := schemas.ExecuteAction(&testActionData_AddV1{Summand: 3}, core); err == nil {
+		t.Error("expected error executing an action the core method does not take")
+	}
+}
+
+func TestActionConverter(t *testing.T) {
+	v1, err := NewActionSchemasFromRaw(
+		testAddV1ABIJson,
+		`{"add":{"schema":{"summand":"int16"}}}`,
+		map[string]reflect.Type{"Add": reflect.TypeOf(testActionData_AddV1{})},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1.SetActionConverter(func(action Action) (Action, error) {
+		if action, ok := action.(*testActionData_AddV1); ok {
+			return &testActionData_AddV2{Summand: int32(action.Summand)}, nil
+		}
+		return action, nil
+	})
+	schemas, err := NewVersionedActionSchemas(map[uint64]ActionSchemas{0: v1, 10: newTestAddSchemas(t, true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Actions of the old version are converted and executed by the action methods of the latest one
+	core := &testVersionedCore{}
+	for _, action := range []Action{&testActionData_AddV1{Summand: 1}, &testActionData_AddV2{Summand: 2}} {
+		if err := schemas.ExecuteAction(action, core); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if core.sum != 3 {
+		t.Errorf("expected sum %v, got %v", 3, core.sum)
+	}
+
+	// Conversion errors are returned
+	errConversion := errors.New("conversion failed")
+	v1.SetActionConverter(func(action Action) (Action, error) {
+		return nil, errConversion
+	})
+	if schemas, err = NewVersionedActionSchemas(map[uint64]ActionSchemas{0: v1, 10: newTestAddSchemas(t, true)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas.ExecuteAction(&testActionData_AddV1{Summand: 1}, core); !errors.Is(err, errConversion) {
+		t.Errorf("expected %v, got %v", errConversion, err)
+	}
+}
+
+// Dispatcher written like the gogen generated code
+
+type testActionHandler interface {
+	Add(action *testActionData_AddV2) error
+}
+
+type testActionDispatcher struct{}
+
+func (testActionDispatcher) ActionName(action Action) (string, bool) {
+	switch action.(type) {
+	case *testActionData_AddV2:
+		return "Add", true
+	}
+	return "", false
+}
+
+func (testActionDispatcher) DispatchAction(action Action, target Core) (bool, error) {
+	handler, ok := target.(testActionHandler)
+	if !ok {
+		return false, nil
+	}
+	switch action := action.(type) {
+	case *testActionData_AddV2:
+		return true, handler.Add(action)
+	}
+	return false, nil
+}
+
+// testUnhandledCore has an action method that returns no error, so it does not implement the handler
+// interface and actions are executed through reflection.
+type testUnhandledCore struct {
+	BaseCore
+	sum int64
+}
+
+func (c *testUnhandledCore) Add(action *testActionData_AddV2) {
+	c.sum += int64(action.Summand)
+}
+
+func newTestAddSchemas(tb testing.TB, dispatch bool) ActionSchemas {
+	schemas, err := NewActionSchemasFromRaw(
+		testAddV2ABIJson,
+		`{"add":{"schema":{"summand":"int32"}}}`,
+		map[string]reflect.Type{"Add": reflect.TypeOf(testActionData_AddV2{})},
+	)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if dispatch {
+		schemas.SetActionDispatcher(testActionDispatcher{})
+	}
+	return schemas
+}
+
+func TestActionDispatcher(t *testing.T) {
+	var (
+		reflective = newTestAddSchemas(t, false)
+		dispatched = newTestAddSchemas(t, true)
+		action     = &testActionData_AddV2{Summand: 2}
+	)
+	for _, action := range []Action{action, &CanonicalTickAction{}} {
+		expId, ok := reflective.ActionIdFromAction(action)
+		if !ok {
+			t.Fatalf("expected action id for %T", action)
+		}
+		actionId, ok := dispatched.ActionIdFromAction(action)
+		if !ok || actionId != expId {
+			t.Errorf("expected action id %v for %T, got %v", expId, action, actionId)
+		}
+	}
+	if _, ok := dispatched.ActionIdFromAction(&testActionData_AddV1{}); ok {
+		t.Error("expected no action id for an action of another schema")
+	}
+
+	core := &testVersionedCore{}
+	if err := dispatched.ExecuteAction(action, core); err != nil {
+		t.Fatal(err)
+	}
+	if core.sum != 2 {
+		t.Errorf("expected sum %v, got %v", 2, core.sum)
+	}
+	// Cores that do not implement the handler interface are executed through reflection
+	unhandledCore := &testUnhandledCore{}
+	if err := dispatched.ExecuteAction(action, unhandledCore); err != nil {
+		t.Fatal(err)
+	}
+	if unhandledCore.sum != 2 {
+		t.Errorf("expected sum %v, got %v", 2, unhandledCore.sum)
+	}
+	if err := dispatched.ExecuteAction(&testActionData_AddV1{}, core); err != ErrInvalidAction {
+		t.Errorf("expected %v, got %v", ErrInvalidAction, err)
+	}
+}
+
+func benchmarkExecuteAction(b *testing.B, dispatch bool) {
+	var (
+		schemas = newTestAddSchemas(b, dispatch)
+		core    = &testVersionedCore{}
+		action  = &testActionData_AddV2{Summand: 1}
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := schemas.ExecuteAction(action, core); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExecuteActionReflection(b *testing.B) {
+	benchmarkExecuteAction(b, false)
+}
+
+func BenchmarkExecuteActionDispatcher(b *testing.B) {
+	benchmarkExecuteAction(b, true)
+}
+
+func benchmarkActionIdFromAction(b *testing.B, dispatch bool) {
+	var (
+		schemas = newTestAddSchemas(b, dispatch)
+		action  = &testActionData_AddV2{Summand: 1}
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := schemas.ActionIdFromAction(action); !ok {
+			b.Fatal("action id not found")
+		}
+	}
+}
+
+func BenchmarkActionIdFromActionReflection(b *testing.B) {
+	benchmarkActionIdFromAction(b, false)
+}
+
+func BenchmarkActionIdFromActionDispatcher(b *testing.B) {
+	benchmarkActionIdFromAction(b, true)
+}
+
+type testActionData_Move struct {
+	Id     uint8       `json:"id"`
+	Player common.Hash `json:"player"`
+	X      int32       `json:"x"`
+}
+
+type testActionData_Stop struct {
+	Id uint8 `json:"id"`
+}
+
+const testKeyedABIJson = `[
+	{"type":"function","name":"move","inputs":[
+		{"name":"id","type":"uint8"},{"name":"player","type":"bytes32"},
+		{"name":"action","type":"tuple","components":[{"name":"x","type":"int32"}]}
+	],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"stop","inputs":[{"name":"id","type":"uint8"}],"outputs":[],"stateMutability":"nonpayable"}
+]`
+
+const testKeyedActionsJson = `{
+	"move": {"keySchema": {"id": "uint8", "player": "bytes32"}, "schema": {"x": "int32"}},
+	"stop": {"keySchema": {"id": "uint8"}, "schema": {}}
+}`
+
+type testKeyedCore struct {
+	BaseCore
+	moves []testActionData_Move
+	stops []uint8
+}
+
+func (c *testKeyedCore) Move(action *testActionData_Move) error {
+	c.moves = append(c.moves, *action)
+	return nil
+}
+
+func (c *testKeyedCore) Stop(action *testActionData_Stop) error {
+	c.stops = append(c.stops, action.Id)
+	return nil
+}
+
+func TestKeyedActionSchemas(t *testing.T) {
+	schemas, err := NewActionSchemasFromRaw(
+		testKeyedABIJson,
+		testKeyedActionsJson,
+		map[string]reflect.Type{
+			"Move": reflect.TypeOf(testActionData_Move{}),
+			"Stop": reflect.TypeOf(testActionData_Stop{}),
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ABI, err := abi.JSON(strings.NewReader(testKeyedABIJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		move = &testActionData_Move{Id: 1, Player: common.Hash{0xaa}, X: -2}
+		stop = &testActionData_Stop{Id: 3}
+	)
+	moveArgs := []interface{}{uint8(1), [32]byte{0xaa}, struct{ X int32 }{-2}}
+	stopArgs := []interface{}{uint8(3)}
+	for _, tt := range []struct {
+		action Action
+		method string
+		args   []interface{}
+	}{
+		{move, "move", moveArgs},
+		{stop, "stop", stopArgs},
+	} {
+		// Keys are packed as the leading arguments of the action method
+		expCalldata, err := ABI.Pack(tt.method, tt.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		calldata, err := schemas.ActionToCalldata(tt.action)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(calldata, expCalldata) {
+			t.Errorf("expected calldata %x, got %x", expCalldata, calldata)
+		}
+		action, err := schemas.CalldataToAction(calldata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(action, tt.action) {
+			t.Errorf("expected %+v, got %+v", tt.action, action)
+		}
+
+		log, err := schemas.ActionToLog(tt.action)
+		if err != nil {
+			t.Fatal(err)
+		}
+		action, err = schemas.LogToAction(log)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(action, tt.action) {
+			t.Errorf("expected %+v, got %+v", tt.action, action)
+		}
+	}
+
+	core := &testKeyedCore{}
+	if err := schemas.ExecuteAction(move, core); err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas.ExecuteAction(stop, core); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(core.moves, []testActionData_Move{*move}) || !reflect.DeepEqual(core.stops, []uint8{3}) {
+		t.Errorf("unexpected executed actions: moves %v, stops %v", core.moves, core.stops)
+	}
+}
+
+func TestKeyedActionSchemasInvalid(t *testing.T) {
+	testData := []struct {
+		name        string
+		abiJson     string
+		actionsJson string
+	}{
+		{
+			"missing key argument",
+			`[{"type":"function","name":"move","inputs":[{"name":"action","type":"tuple","components":[{"name":"x","type":"int32"}]}],"outputs":[],"stateMutability":"nonpayable"}]`,
+			`{"move": {"keySchema": {"id": "uint8"}, "schema": {"x": "int32"}}}`,
+		},
+		{
+			"dynamic key",
+			`[{"type":"function","name":"move","inputs":[{"name":"name","type":"string"},{"name":"action","type":"tuple","components":[{"name":"x","type":"int32"}]}],"outputs":[],"stateMutability":"nonpayable"}]`,
+			`{"move": {"keySchema": {"name": "string"}, "schema": {"x": "int32"}}}`,
+		},
+	}
+	for _, tt := range testData {
+		_, err := NewActionSchemasFromRaw(
+			tt.abiJson,
+			tt.actionsJson,
+			map[string]reflect.Type{"Move": reflect.TypeOf(testActionData_Move{})},
+		)
+		if err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package arch
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/concrete/lib"
 )
 
@@ -19,16 +20,53 @@ type Core interface {
 	InBlockTickIndex() uint64   // Get the in-block tick index
 }
 
+// ExecutionContext holds the transaction and block an action is executed in.
+// The precompile sets it from the EVM environment and the client from the action logs, so core logic
+// reading it stays deterministic.
+type ExecutionContext struct {
+	Caller         common.Address // Account that called the core, i.e., the game contract
+	Origin         common.Address // Account that sent the transaction
+	BlockTimestamp uint64
+	// Hash of the transaction. It is not available to the precompile, so it is zero on chain and must
+	// not affect the state.
+	TxHash common.Hash
+}
+
+type ISetExecutionContext interface {
+	SetExecutionContext(ExecutionContext)
+}
+
 type ISetRebasing interface {
 	SetRebasing(bool)
 }
 
+// GasMeter charges gas for the work done by a core.
+// Implementations abort execution by panicking when the charged gas exceeds their budget.
+type GasMeter interface {
+	UseGas(amount uint64)
+}
+
+type ISetGasMeter interface {
+	SetGasMeter(GasMeter)
+}
+
+// DatastoreWrapper wraps the datastore of a core, e.g., to observe the table rows it accesses.
+type DatastoreWrapper func(lib.Datastore) lib.Datastore
+
+type ISetDatastoreWrapper interface {
+	SetDatastoreWrapper(DatastoreWrapper)
+}
+
 type BaseCore struct {
 	kv               lib.KeyValueStore
 	ds               lib.Datastore
 	blockNumber      uint64
 	inBlockTickIndex uint64
 	rebasing         bool
+	gasMeter         GasMeter
+	eventEmitter     EventEmitter
+	dsWrapper        DatastoreWrapper
+	execCtx          ExecutionContext
 }
 
 var _ Core = &BaseCore{}
@@ -36,6 +74,9 @@ var _ Core = &BaseCore{}
 func (b *BaseCore) SetKV(kv lib.KeyValueStore) {
 	b.kv = kv
 	b.ds = lib.NewKVDatastore(kv)
+	if b.dsWrapper != nil {
+		b.ds = b.dsWrapper(b.ds)
+	}
 }
 
 func (b *BaseCore) KV() lib.KeyValueStore {
@@ -54,6 +95,16 @@ func (b *BaseCore) BlockNumber() uint64 {
 	return b.blockNumber
 }
 
+func (b *BaseCore) SetExecutionContext(ctx ExecutionContext) {
+	b.execCtx = ctx
+}
+
+// ExecutionContext returns the context of the action being executed.
+// It is zero for ticks run by the client between blocks and for actions with unknown context.
+func (b *BaseCore) ExecutionContext() ExecutionContext {
+	return b.execCtx
+}
+
 func (b *BaseCore) SetInBlockTickIndex(index uint64) {
 	b.inBlockTickIndex = index
 }
@@ -70,6 +121,39 @@ func (b *BaseCore) Rebasing() bool {
 	return b.rebasing
 }
 
+func (b *BaseCore) SetGasMeter(meter GasMeter) {
+	b.gasMeter = meter
+}
+
+// UseGas charges the given amount of gas to the gas meter, if any.
+// Outside of a metered context, e.g. in a client, it does nothing.
+func (b *BaseCore) UseGas(amount uint64) {
+	if b.gasMeter != nil {
+		b.gasMeter.UseGas(amount)
+	}
+}
+
+func (b *BaseCore) SetEventEmitter(emitter EventEmitter) {
+	b.eventEmitter = emitter
+}
+
+// EmitEvent emits the given custom event to the event emitter, if any.
+// Events emitted by an action that fails are discarded.
+func (b *BaseCore) EmitEvent(event Event) {
+	if b.eventEmitter != nil {
+		b.eventEmitter.EmitEvent(event)
+	}
+}
+
+// SetDatastoreWrapper sets the wrapper applied to the datastore built on every call to SetKV.
+// A nil wrapper removes the wrapper.
+func (b *BaseCore) SetDatastoreWrapper(wrapper DatastoreWrapper) {
+	b.dsWrapper = wrapper
+	if b.kv != nil {
+		b.SetKV(b.kv)
+	}
+}
+
 func (b *BaseCore) TicksPerBlock() uint64 {
 	return 0
 }
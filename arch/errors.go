@@ -0,0 +1,158 @@
+package arch
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type actionErrorSchema struct {
+	Error *abi.Error
+	Type  reflect.Type
+}
+
+// dataError is implemented by RPC errors carrying data, e.g., the revert data of a failed call.
+type dataError interface {
+	ErrorData() interface{}
+}
+
+// RegisterErrors registers the Go types of the custom errors declared in the actions ABI.
+// Errors of a registered type returned by core methods are encoded as Solidity custom errors by
+// EncodeError, and revert data of a registered error is decoded by DecodeError.
+// Types are keyed by the Solidity error name and must be structs with one field per error input,
+// in the same order. Registered errors are shared by all copies of the ActionSchemas.
+func (a *ActionSchemas) RegisterErrors(types map[string]reflect.Type) error {
+	if a.errors == nil {
+		a.errors = make(map[RawIdType]actionErrorSchema, len(types))
+	}
+	for name, errorType := range types {
+		abiError, ok := a.abi.Errors[name]
+		if !ok {
+			return fmt.Errorf("no error %s found in ABI", name)
+		}
+		if !isStruct(errorType) {
+			return fmt.Errorf("type for error %s is not a struct", name)
+		}
+		if !reflect.PointerTo(errorType).Implements(reflect.TypeOf((*error)(nil)).Elem()) {
+			return fmt.Errorf("type for error %s does not implement error", name)
+		}
+		if errorType.NumField() != len(abiError.Inputs) {
+			return fmt.Errorf("type for error %s does not match the error inputs", name)
+		}
+		var id RawIdType
+		copy(id[:], abiError.ID[:4])
+		a.errors[id] = actionErrorSchema{Error: &abiError, Type: errorType}
+	}
+	return nil
+}
+
+// asActionError returns the first error in the tree of err of a registered error type, in the
+// depth-first order used by errors.As.
+func (a *ActionSchemas) asActionError(err error) (actionErrorSchema, reflect.Value, bool) {
+	if err == nil {
+		return actionErrorSchema{}, reflect.Value{}, false
+	}
+	if errVal := reflect.ValueOf(err); isStructPtr(errVal.Type()) && !errVal.IsNil() {
+		for _, schema := range a.errors {
+			if errVal.Type().Elem() == schema.Type {
+				return schema, errVal.Elem(), true
+			}
+		}
+	}
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return a.asActionError(e.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			if schema, errVal, ok := a.asActionError(err); ok {
+				return schema, errVal, true
+			}
+		}
+	}
+	return actionErrorSchema{}, reflect.Value{}, false
+}
+
+// EncodeError encodes err as a Solidity custom error, i.e., the error selector followed by the
+// ABI-encoded error inputs.
+// Returns false if neither err nor any error it wraps is of a registered error type.
+func (a *ActionSchemas) EncodeError(err error) ([]byte, bool) {
+	schema, errVal, ok := a.asActionError(err)
+	if !ok {
+		return nil, false
+	}
+	args := make([]interface{}, errVal.NumField())
+	for ii := range args {
+		arg, convErr := toABIValue(errVal.Field(ii), schema.Error.Inputs[ii].Type)
+		if convErr != nil {
+			return nil, false
+		}
+		args[ii] = arg
+	}
+	data, packErr := schema.Error.Inputs.Pack(args...)
+	if packErr != nil {
+		return nil, false
+	}
+	revertData := make([]byte, 4+len(data))
+	copy(revertData[:4], schema.Error.ID[:4])
+	copy(revertData[4:], data)
+	return revertData, true
+}
+
+// DecodeError decodes revert data into an error of the registered type matching its selector.
+// Returns false if the data is not a registered custom error.
+func (a *ActionSchemas) DecodeError(data []byte) (error, bool) {
+	if len(data) < 4 {
+		return nil, false
+	}
+	var id RawIdType
+	copy(id[:], data[:4])
+	schema, ok := a.errors[id]
+	if !ok {
+		return nil, false
+	}
+	values, err := schema.Error.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, false
+	}
+	errPtr := reflect.New(schema.Type)
+	errElem := errPtr.Elem()
+	for ii, value := range values {
+		// e.g., [4]byte -> []byte
+		valueVal, ok := fromABIValue(reflect.ValueOf(value), errElem.Field(ii).Type())
+		if !ok {
+			return nil, false
+		}
+		errElem.Field(ii).Set(valueVal)
+	}
+	return errPtr.Interface().(error), true
+}
+
+// DecodeRevertError decodes the revert data carried by err, e.g., the error returned by a call or
+// gas estimation of a reverting action, and returns err wrapping the typed action error.
+// If err does not carry the revert data of a registered error, or already wraps a typed action
+// error, err is returned unchanged.
+func (a *ActionSchemas) DecodeRevertError(err error) error {
+	if _, _, ok := a.asActionError(err); ok {
+		return err
+	}
+	var dataErr dataError
+	if !errors.As(err, &dataErr) {
+		return err
+	}
+	dataHex, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err
+	}
+	data, decodeErr := hexutil.Decode(dataHex)
+	if decodeErr != nil {
+		return err
+	}
+	actionErr, ok := a.DecodeError(data)
+	if !ok {
+		return err
+	}
+	return fmt.Errorf("%w: %w", err, actionErr)
+}
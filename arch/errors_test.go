@@ -0,0 +1,68 @@
+package arch
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Error type written like the gogen generated code, i.e., bytes32 as common.Hash and bytesN with
+// N < 32 as []byte
+
+type testActionError_Mismatch struct {
+	Tag     []byte
+	Hash    common.Hash
+	Counter int16
+}
+
+func (e *testActionError_Mismatch) Error() string {
+	return fmt.Sprintf("mismatch: %x %s %d", e.Tag, e.Hash.Hex(), e.Counter)
+}
+
+const testErrorsABIJson = `[
+	{"type":"function","name":"add","inputs":[{"name":"action","type":"tuple","components":[{"name":"summand","type":"int32"}]}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"error","name":"Mismatch","inputs":[{"name":"tag","type":"bytes4"},{"name":"hash","type":"bytes32"},{"name":"counter","type":"int16"}]}
+]`
+
+func TestActionErrorRoundTrip(t *testing.T) {
+	schemas, err := NewActionSchemasFromRaw(
+		testErrorsABIJson,
+		`{"add":{"schema":{"summand":"int32"}}}`,
+		map[string]reflect.Type{"Add": reflect.TypeOf(testActionData_AddV2{})},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas.RegisterErrors(map[string]reflect.Type{
+		"Mismatch": reflect.TypeOf(testActionError_Mismatch{}),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	actionErr := &testActionError_Mismatch{
+		Tag:     []byte{0xde, 0xad, 0xbe, 0xef},
+		Hash:    common.HexToHash("0x0102"),
+		Counter: -3,
+	}
+	data, ok := schemas.EncodeError(fmt.Errorf("wrapped: %w", actionErr))
+	if !ok {
+		t.Fatal("expected error to be encoded")
+	}
+	decoded, ok := schemas.DecodeError(data)
+	if !ok {
+		t.Fatal("expected error to be decoded")
+	}
+	if !reflect.DeepEqual(decoded, actionErr) {
+		t.Errorf("expected %v, got %v", actionErr, decoded)
+	}
+
+	if _, ok := schemas.EncodeError(errors.New("unregistered")); ok {
+		t.Error("expected unregistered error not to be encoded")
+	}
+	if _, ok := schemas.EncodeError(&testActionError_Mismatch{Tag: make([]byte, 5)}); ok {
+		t.Error("expected error with a value longer than its bytesN not to be encoded")
+	}
+}
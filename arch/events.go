@@ -0,0 +1,199 @@
+package arch
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/concrete-eth/archetype/params"
+)
+
+var (
+	ErrInvalidEvent = errors.New("invalid event")
+)
+
+type Event interface{}
+
+// EventEmitter receives the events emitted by a core.
+type EventEmitter interface {
+	EmitEvent(event Event)
+}
+
+type ISetEventEmitter interface {
+	SetEventEmitter(EventEmitter)
+}
+
+// EventBuffer is an EventEmitter that holds the emitted events in order.
+type EventBuffer struct {
+	events []Event
+}
+
+var _ EventEmitter = (*EventBuffer)(nil)
+
+// NewEventBuffer creates a new empty EventBuffer.
+func NewEventBuffer() *EventBuffer {
+	return &EventBuffer{events: make([]Event, 0)}
+}
+
+// EmitEvent appends the event to the buffer.
+func (b *EventBuffer) EmitEvent(event Event) {
+	b.events = append(b.events, event)
+}
+
+// Events returns the buffered events.
+func (b *EventBuffer) Events() []Event {
+	return b.events
+}
+
+// Len returns the number of buffered events.
+func (b *EventBuffer) Len() int {
+	return len(b.events)
+}
+
+// Truncate discards the events emitted after the first n.
+func (b *EventBuffer) Truncate(n int) {
+	b.events = b.events[:n]
+}
+
+// Reset discards all buffered events.
+func (b *EventBuffer) Reset() {
+	b.events = b.events[:0]
+}
+
+type EventSchema struct {
+	datamod.TableSchema
+	Event *abi.Event
+	Type  reflect.Type
+}
+
+// EventSchemas holds the custom events a core can emit.
+// Events are logged by the core contract with the event signature hash as the only topic and all
+// fields ABI-encoded in the data, like Solidity events with no indexed arguments.
+type EventSchemas struct {
+	schemas map[common.Hash]EventSchema
+}
+
+// NewEventSchemas creates a new EventSchemas instance.
+func NewEventSchemas(schemas []datamod.TableSchema, types map[string]reflect.Type) (EventSchemas, error) {
+	e := EventSchemas{schemas: make(map[common.Hash]EventSchema, len(schemas))}
+	for _, schema := range schemas {
+		if len(schema.Keys) > 0 {
+			return EventSchemas{}, fmt.Errorf("event %s has keys defined", schema.Name)
+		}
+		eventType, ok := types[schema.Name]
+		if !ok {
+			return EventSchemas{}, fmt.Errorf("no type found for event %s", schema.Name)
+		}
+		if !isStruct(eventType) {
+			return EventSchemas{}, fmt.Errorf("type for event %s is not a struct", schema.Name)
+		}
+		if eventType.NumField() != len(schema.Values) {
+			return EventSchemas{}, fmt.Errorf("type for event %s does not match the event schema", schema.Name)
+		}
+		inputs := make(abi.Arguments, 0, len(schema.Values))
+		for _, field := range schema.Values {
+			fieldType, err := abi.NewType(field.Type.SolType, "", nil)
+			if err != nil {
+				return EventSchemas{}, err
+			}
+			inputs = append(inputs, abi.Argument{Name: field.Name, Type: fieldType})
+		}
+		eventName := params.SolidityEventName(schema.Name)
+		event := abi.NewEvent(eventName, eventName, false, inputs)
+		e.schemas[event.ID] = EventSchema{
+			TableSchema: schema,
+			Event:       &event,
+			Type:        eventType,
+		}
+	}
+	return e, nil
+}
+
+// NewEventSchemasFromRaw creates a new EventSchemas instance from a raw JSON string.
+func NewEventSchemasFromRaw(schemasJson string, types map[string]reflect.Type) (EventSchemas, error) {
+	schemas, err := datamod.UnmarshalTableSchemas([]byte(schemasJson), false)
+	if err != nil {
+		return EventSchemas{}, err
+	}
+	return NewEventSchemas(schemas, types)
+}
+
+// EventSchemaFromEvent returns the schema of the given event.
+func (e EventSchemas) EventSchemaFromEvent(event Event) (EventSchema, bool) {
+	eventType := reflect.TypeOf(event)
+	if eventType == nil || !isStructPtr(eventType) {
+		return EventSchema{}, false
+	}
+	for _, schema := range e.schemas {
+		if eventType.Elem() == schema.Type {
+			return schema, true
+		}
+	}
+	return EventSchema{}, false
+}
+
+// EventToLog converts an event to a log.
+func (e EventSchemas) EventToLog(event Event) (types.Log, error) {
+	schema, ok := e.EventSchemaFromEvent(event)
+	if !ok {
+		return types.Log{}, fmt.Errorf("%w: event of type %T does not match any event type", ErrInvalidEvent, event)
+	}
+	eventVal := reflect.ValueOf(event).Elem()
+	args := make([]interface{}, eventVal.NumField())
+	for ii := range args {
+		arg, err := toABIValue(eventVal.Field(ii), schema.Event.Inputs[ii].Type)
+		if err != nil {
+			return types.Log{}, fmt.Errorf("field %s: %w", schema.Type.Field(ii).Name, err)
+		}
+		args[ii] = arg
+	}
+	data, err := schema.Event.Inputs.Pack(args...)
+	if err != nil {
+		return types.Log{}, err
+	}
+	log := types.Log{
+		Topics: []common.Hash{schema.Event.ID},
+		Data:   data,
+	}
+	return log, nil
+}
+
+// IsEventLog returns whether the log is a custom event.
+func (e EventSchemas) IsEventLog(log types.Log) bool {
+	if len(log.Topics) != 1 {
+		return false
+	}
+	_, ok := e.schemas[log.Topics[0]]
+	return ok
+}
+
+// LogToEvent converts a log to an event.
+func (e EventSchemas) LogToEvent(log types.Log) (Event, error) {
+	if len(log.Topics) != 1 {
+		return nil, fmt.Errorf("%w: log topics do not match any event", ErrInvalidEvent)
+	}
+	schema, ok := e.schemas[log.Topics[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: log topics do not match any event", ErrInvalidEvent)
+	}
+	values, err := schema.Event.Inputs.Unpack(log.Data)
+	if err != nil {
+		return nil, err
+	}
+	event := reflect.New(schema.Type)
+	eventElem := event.Elem()
+	for ii, value := range values {
+		// e.g., [32]byte -> common.Hash
+		valueVal, ok := fromABIValue(reflect.ValueOf(value), eventElem.Field(ii).Type())
+		if !ok {
+			return nil, fmt.Errorf("field %s has different type", schema.Type.Field(ii).Name)
+		}
+		eventElem.Field(ii).Set(valueVal)
+	}
+	return event.Interface(), nil
+}
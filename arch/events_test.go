@@ -0,0 +1,60 @@
+package arch
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Event type written like the gogen generated code, i.e., bytes32 as common.Hash and bytesN with
+// N < 32 as []byte
+
+type testEvent_Marked struct {
+	Hash common.Hash
+	Tag  []byte
+	Id   uint8
+}
+
+func TestEventLogRoundTrip(t *testing.T) {
+	schemas, err := NewEventSchemasFromRaw(
+		`{"marked":{"schema":{"hash":"bytes32","tag":"bytes4","id":"uint8"}}}`,
+		map[string]reflect.Type{"Marked": reflect.TypeOf(testEvent_Marked{})},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &testEvent_Marked{
+		Hash: common.HexToHash("0x0102"),
+		Tag:  []byte{0xde, 0xad, 0xbe, 0xef},
+		Id:   7,
+	}
+	log, err := schemas.EventToLog(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schemas.IsEventLog(log) {
+		t.Fatal("expected event log")
+	}
+	decoded, err := schemas.LogToEvent(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, event) {
+		t.Errorf("expected %v, got %v", event, decoded)
+	}
+
+	// Shorter values are right-padded, longer values do not fit
+	if log, err = schemas.EventToLog(&testEvent_Marked{Tag: []byte{0xff}}); err != nil {
+		t.Fatal(err)
+	}
+	if decoded, err = schemas.LogToEvent(log); err != nil {
+		t.Fatal(err)
+	}
+	if tag := decoded.(*testEvent_Marked).Tag; !reflect.DeepEqual(tag, []byte{0xff, 0, 0, 0}) {
+		t.Errorf("expected tag %x, got %x", []byte{0xff, 0, 0, 0}, tag)
+	}
+	if _, err := schemas.EventToLog(&testEvent_Marked{Tag: make([]byte, 5)}); err == nil {
+		t.Error("expected error encoding a value longer than its bytesN")
+	}
+}
@@ -0,0 +1,151 @@
+package arch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete/lib"
+)
+
+var ErrUnauthorized = errors.New("unauthorized")
+
+type PermissionKind int
+
+const (
+	PermissionOpen  PermissionKind = iota // Any account can execute the action
+	PermissionOwner                       // Only the owner of the game can execute the action
+	PermissionRole                        // Only accounts granted the role can execute the action
+)
+
+// Permission declares which accounts can execute an action.
+type Permission struct {
+	Kind PermissionKind
+	Role string // Name of the role, if Kind is PermissionRole
+}
+
+func (p Permission) String() string {
+	switch p.Kind {
+	case PermissionOpen:
+		return "open"
+	case PermissionOwner:
+		return "owner"
+	case PermissionRole:
+		return "role " + p.Role
+	default:
+		return "unknown"
+	}
+}
+
+func (p *Permission) UnmarshalJSON(data []byte) error {
+	var kind string
+	if err := json.Unmarshal(data, &kind); err == nil {
+		switch kind {
+		case "open":
+			*p = Permission{Kind: PermissionOpen}
+		case "owner":
+			*p = Permission{Kind: PermissionOwner}
+		default:
+			return fmt.Errorf("invalid permission %q", kind)
+		}
+		return nil
+	}
+	var role struct {
+		Role string `json:"role"`
+	}
+	if err := json.Unmarshal(data, &role); err != nil || role.Role == "" {
+		return fmt.Errorf("invalid permission %s", data)
+	}
+	*p = Permission{Kind: PermissionRole, Role: role.Role}
+	return nil
+}
+
+// UnmarshalPermissions unmarshals the permissions declared in an actions schema.
+// Permissions are declared per action under the "permission" key, either as "open", "owner", or a role:
+//
+//	"configure": {
+//	    "schema": {"maxBodyCount": "uint8"},
+//	    "permission": "owner"
+//	},
+//	"addBody": {
+//	    "schema": {...},
+//	    "permission": {"role": "spawner"}
+//	}
+//
+// Actions without a permission are open. Permissions are keyed by action schema name.
+func UnmarshalPermissions(jsonContent []byte) (map[string]Permission, error) {
+	var jsonActions map[string]struct {
+		Permission *Permission `json:"permission"`
+	}
+	if err := json.Unmarshal(jsonContent, &jsonActions); err != nil {
+		return nil, err
+	}
+	permissions := make(map[string]Permission, len(jsonActions))
+	for actionName, jsonAction := range jsonActions {
+		if jsonAction.Permission == nil {
+			continue
+		}
+		permissions[upperFirstLetter(actionName)] = *jsonAction.Permission
+	}
+	return permissions, nil
+}
+
+func upperFirstLetter(str string) string {
+	if len(str) == 0 {
+		return ""
+	}
+	runes := []rune(str)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// SetPermissions sets the permissions of the actions, keyed by action schema name.
+// Actions without a permission are open. If any action is restricted to a role, the built-in GrantRole
+// and RevokeRole actions are added to the schemas, so games without roles keep their action IDs. The
+// built-in role actions can only be executed by the owner.
+func (a *ActionSchemas) SetPermissions(permissions map[string]Permission) {
+	a.permissions = permissions
+	for _, permission := range permissions {
+		if permission.Kind == PermissionRole {
+			a.addRoleActions()
+			break
+		}
+	}
+}
+
+// ActionPermission returns the permission of the given action.
+func (a ActionSchemas) ActionPermission(action Action) Permission {
+	switch action.(type) {
+	case *CanonicalTickAction:
+		return Permission{Kind: PermissionOpen}
+	case *GrantRoleAction, *RevokeRoleAction:
+		return Permission{Kind: PermissionOwner}
+	}
+	schema, ok := a.actionSchema(action)
+	if !ok {
+		return Permission{Kind: PermissionOpen}
+	}
+	return a.permissions[schema.Name]
+}
+
+// CheckPermission returns an error wrapping ErrUnauthorized if the account cannot execute the action.
+// Roles are read from the given store. owner returns the owner of the game and is only called for
+// actions restricted to the owner.
+func (a ActionSchemas) CheckPermission(action Action, kv lib.KeyValueStore, account common.Address, owner func() common.Address) error {
+	permission := a.ActionPermission(action)
+	switch permission.Kind {
+	case PermissionOpen:
+		return nil
+	case PermissionOwner:
+		if owner := owner(); owner != (common.Address{}) && account == owner {
+			return nil
+		}
+	case PermissionRole:
+		if HasRole(kv, RoleId(permission.Role), account) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %T requires %s", ErrUnauthorized, action, permission)
+}
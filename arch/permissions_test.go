@@ -0,0 +1,169 @@
+package arch
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/concrete-eth/archetype/kvstore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestUnmarshalPermissions(t *testing.T) {
+	permissions, err := UnmarshalPermissions([]byte(`{
+		"add": {"schema": {"summand": "int16"}},
+		"configure": {"schema": {"maxBodyCount": "uint8"}, "permission": "owner"},
+		"addBody": {"schema": {"x": "int32"}, "permission": {"role": "spawner"}},
+		"move": {"schema": {"x": "int32"}, "permission": "open"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]Permission{
+		"Configure": {Kind: PermissionOwner},
+		"AddBody":   {Kind: PermissionRole, Role: "spawner"},
+		"Move":      {Kind: PermissionOpen},
+	}
+	if !reflect.DeepEqual(permissions, expected) {
+		t.Errorf("expected %v, got %v", expected, permissions)
+	}
+
+	for _, invalid := range []string{
+		`{"add": {"permission": "admin"}}`,
+		`{"add": {"permission": {"role": ""}}}`,
+		`{"add": {"permission": 1}}`,
+	} {
+		if _, err := UnmarshalPermissions([]byte(invalid)); err == nil {
+			t.Errorf("expected error unmarshalling %s", invalid)
+		}
+	}
+}
+
+func TestCheckPermission(t *testing.T) {
+	schemas := newTestAddSchemas(t, false)
+	schemas.SetPermissions(map[string]Permission{"Add": {Kind: PermissionRole, Role: "adder"}})
+
+	var (
+		owner   = common.HexToAddress("0x01")
+		account = common.HexToAddress("0x02")
+		kv      = kvstore.NewMemoryKeyValueStore()
+		getter  = func() common.Address { return owner }
+		core    = &testVersionedCore{}
+		add     = &testActionData_AddV2{Summand: 1}
+		grant   = &GrantRoleAction{Role: RoleId("adder"), Account: account}
+		revoke  = &RevokeRoleAction{Role: RoleId("adder"), Account: account}
+	)
+	core.SetKV(kv)
+
+	if err := schemas.CheckPermission(&CanonicalTickAction{}, kv, account, getter); err != nil {
+		t.Errorf("expected tick to be open, got %v", err)
+	}
+	if err := schemas.CheckPermission(add, kv, account, getter); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected %v, got %v", ErrUnauthorized, err)
+	}
+	if err := schemas.CheckPermission(grant, kv, account, getter); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected %v, got %v", ErrUnauthorized, err)
+	}
+	if err := schemas.CheckPermission(grant, kv, owner, getter); err != nil {
+		t.Fatal(err)
+	}
+	// The zero address is never the owner
+	if err := schemas.CheckPermission(grant, kv, common.Address{}, func() common.Address { return common.Address{} }); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected %v, got %v", ErrUnauthorized, err)
+	}
+
+	// Granting the role authorizes the account
+	if err := schemas.ExecuteAction(grant, core); err != nil {
+		t.Fatal(err)
+	}
+	if !HasRole(kv, RoleId("adder"), account) {
+		t.Fatal("expected role to be granted")
+	}
+	if err := schemas.CheckPermission(add, kv, account, getter); err != nil {
+		t.Errorf("expected account with role to be authorized, got %v", err)
+	}
+
+	// Revoking the role unauthorizes the account
+	if err := schemas.ExecuteAction(revoke, core); err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas.CheckPermission(add, kv, account, getter); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected %v, got %v", ErrUnauthorized, err)
+	}
+}
+
+func TestRoleActions(t *testing.T) {
+	schemas := newTestAddSchemas(t, true)
+	action := &GrantRoleAction{Role: RoleId("adder"), Account: common.HexToAddress("0x02")}
+
+	// Role actions are only added if an action is restricted to a role
+	names := schemas.ActionNames()
+	if _, err := schemas.ActionToCalldata(action); err == nil {
+		t.Fatal("expected role actions not to be added")
+	}
+	core := &testVersionedCore{}
+	core.SetKV(kvstore.NewMemoryKeyValueStore())
+	if err := schemas.ExecuteAction(action, core); !errors.Is(err, ErrInvalidAction) {
+		t.Fatalf("expected %v, got %v", ErrInvalidAction, err)
+	}
+	schemas.SetPermissions(map[string]Permission{"Add": {Kind: PermissionOwner}})
+	if !reflect.DeepEqual(schemas.ActionNames(), names) {
+		t.Fatalf("expected actions %v, got %v", names, schemas.ActionNames())
+	}
+	schemas.SetPermissions(map[string]Permission{"Add": {Kind: PermissionRole, Role: "adder"}})
+	if len(schemas.ActionNames()) != len(names)+2 {
+		t.Fatalf("expected role actions to be added, got %v", schemas.ActionNames())
+	}
+
+	// Role actions are encoded as calls to the built-in methods, even with a dispatcher set
+	calldata, err := schemas.ActionToCalldata(action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if selector := crypto.Keccak256([]byte("grantRole((bytes32,address))"))[:4]; !reflect.DeepEqual(calldata[:4], selector) {
+		t.Fatalf("expected selector %x, got %x", selector, calldata[:4])
+	}
+	decoded, err := schemas.CalldataToAction(calldata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, action) {
+		t.Errorf("expected %v, got %v", action, decoded)
+	}
+}
+
+func TestReadRolePacked(t *testing.T) {
+	var (
+		kv      = kvstore.NewMemoryKeyValueStore()
+		role    = RoleId("adder")
+		account = common.HexToAddress("0x02")
+	)
+	calldata, err := hasRoleMethod.Inputs.Pack(role, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calldata = append(hasRoleMethod.ID, calldata...)
+	if !IsRoleRead(calldata) {
+		t.Fatal("expected calldata to be a role read")
+	}
+
+	for _, granted := range []bool{false, true} {
+		setRole(kv, role, account, granted)
+		ret, err := ReadRolePacked(kv, calldata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := hasRoleMethod.Outputs.Unpack(ret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out[0].(bool) != granted {
+			t.Errorf("expected %v, got %v", granted, out[0])
+		}
+	}
+
+	if _, err := ReadRolePacked(kv, []byte{0x01, 0x02, 0x03, 0x04}); err != ErrCalldataIsNotRoleRead {
+		t.Errorf("expected %v, got %v", ErrCalldataIsNotRoleRead, err)
+	}
+}
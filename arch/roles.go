@@ -0,0 +1,193 @@
+package arch
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
+	"github.com/ethereum/go-ethereum/concrete/lib"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/concrete-eth/archetype/params"
+)
+
+var ErrCalldataIsNotRoleRead = errors.New("calldata is not a role read operation")
+
+// Roles are granted to and revoked from accounts by the owner of the game with the built-in GrantRole
+// and RevokeRole actions. The core stores them in a table keyed by role and account.
+var rolesSlot = crypto.Keccak256([]byte("archetype.roles.v1"))
+
+var (
+	roleActionType = mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "role", Type: "bytes32"},
+		{Name: "account", Type: "address"},
+	})
+	grantRoleMethod  = newRoleActionMethod(params.GrantRoleActionName)
+	revokeRoleMethod = newRoleActionMethod(params.RevokeRoleActionName)
+	hasRoleMethod    = abi.NewMethod(params.HasRoleMethodName, params.HasRoleMethodName, abi.Function, "view", false, false,
+		abi.Arguments{{Name: "role", Type: mustNewType("bytes32", nil)}, {Name: "account", Type: mustNewType("address", nil)}},
+		abi.Arguments{{Name: "", Type: mustNewType("bool", nil)}})
+)
+
+func mustNewType(t string, components []abi.ArgumentMarshaling) abi.Type {
+	typ, err := abi.NewType(t, "", components)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+func newRoleActionMethod(name string) abi.Method {
+	methodName := params.SolidityActionMethodName(name)
+	return abi.NewMethod(methodName, methodName, abi.Function, "nonpayable", false, false,
+		abi.Arguments{{Name: "action", Type: roleActionType}}, nil)
+}
+
+// roleActionSchema returns the schema of the built-in role action with the given name.
+func roleActionSchema(name string) datamod.TableSchema {
+	schemas, err := datamod.UnmarshalTableSchemas([]byte(`{"`+name+`": {"schema": {"role": "bytes32", "account": "address"}}}`), false)
+	if err != nil {
+		panic(err)
+	}
+	return schemas[0]
+}
+
+// hasRoleActions returns whether the schemas include the built-in role actions.
+func (a ActionSchemas) hasRoleActions() bool {
+	_, ok := a.idFromName(params.GrantRoleActionName)
+	return ok
+}
+
+// addRoleActions adds the built-in role actions to the schemas, declaring their methods in the ABI if it
+// does not declare them already.
+func (a *ActionSchemas) addRoleActions() {
+	if a.hasRoleActions() {
+		return
+	}
+	roleSchemas, err := newArchSchemas(
+		withRoleActionMethods(a.abi),
+		[]datamod.TableSchema{roleActionSchema(params.GrantRoleActionName), roleActionSchema(params.RevokeRoleActionName)},
+		map[string]reflect.Type{
+			params.GrantRoleActionName:  reflect.TypeOf(GrantRoleAction{}),
+			params.RevokeRoleActionName: reflect.TypeOf(RevokeRoleAction{}),
+		},
+		params.SolidityActionMethodName,
+	)
+	if err != nil {
+		panic(err)
+	}
+	// Copy the schemas so copies of the ActionSchemas made before are not modified
+	a.abi = roleSchemas.abi
+	a.schemas = maps.Clone(a.schemas)
+	for id, schema := range roleSchemas.schemas {
+		a.schemas[id] = schema
+		if a.dispatchIds != nil {
+			a.dispatchIds[schema.Name] = id
+		}
+	}
+}
+
+// withRoleActionMethods returns a copy of the ABI including the methods of the built-in role actions.
+// The ABI is returned unchanged if it already declares them.
+func withRoleActionMethods(ABI *abi.ABI) *abi.ABI {
+	_, hasGrant := ABI.Methods[grantRoleMethod.Name]
+	_, hasRevoke := ABI.Methods[revokeRoleMethod.Name]
+	if hasGrant && hasRevoke {
+		return ABI
+	}
+	withRoles := *ABI
+	withRoles.Methods = maps.Clone(ABI.Methods)
+	withRoles.Methods[grantRoleMethod.Name] = grantRoleMethod
+	withRoles.Methods[revokeRoleMethod.Name] = revokeRoleMethod
+	return &withRoles
+}
+
+// RoleId returns the id of the role with the given name, i.e., keccak256(name).
+func RoleId(name string) common.Hash {
+	return crypto.Keccak256Hash([]byte(name))
+}
+
+func roleSlot(kv lib.KeyValueStore, role common.Hash, account common.Address) lib.DatastoreSlot {
+	return lib.NewKVDatastore(kv).Get(rolesSlot).Mapping().GetNested(role.Bytes(), account.Bytes())
+}
+
+// HasRole returns whether the account has been granted the role in the given store.
+func HasRole(kv lib.KeyValueStore, role common.Hash, account common.Address) bool {
+	return roleSlot(kv, role, account).Bool()
+}
+
+func setRole(kv lib.KeyValueStore, role common.Hash, account common.Address, granted bool) {
+	roleSlot(kv, role, account).SetBool(granted)
+}
+
+// GrantRoleAction grants a role to an account. Only the owner of the game can execute it.
+type GrantRoleAction struct {
+	Role    common.Hash
+	Account common.Address
+}
+
+// RevokeRoleAction revokes a role from an account. Only the owner of the game can execute it.
+type RevokeRoleAction struct {
+	Role    common.Hash
+	Account common.Address
+}
+
+var (
+	_ ABICodec = (*GrantRoleAction)(nil)
+	_ ABICodec = (*RevokeRoleAction)(nil)
+)
+
+func encodeRoleAction(role common.Hash, account common.Address) []byte {
+	enc := NewABIEncoder(2, false)
+	enc.WriteHash(role)
+	enc.WriteAddress(account)
+	return enc.Encoded()
+}
+
+func decodeRoleAction(data []byte) (common.Hash, common.Address, error) {
+	dec := NewABIDecoder(data, 2, false)
+	role := dec.ReadHash()
+	account := dec.ReadAddress()
+	return role, account, dec.Err()
+}
+
+func (v *GrantRoleAction) EncodeABI() []byte {
+	return encodeRoleAction(v.Role, v.Account)
+}
+
+func (v *GrantRoleAction) DecodeABI(data []byte) (err error) {
+	v.Role, v.Account, err = decodeRoleAction(data)
+	return err
+}
+
+func (v *RevokeRoleAction) EncodeABI() []byte {
+	return encodeRoleAction(v.Role, v.Account)
+}
+
+func (v *RevokeRoleAction) DecodeABI(data []byte) (err error) {
+	v.Role, v.Account, err = decodeRoleAction(data)
+	return err
+}
+
+// IsRoleRead returns whether the calldata is a call to the hasRole view method of the core.
+func IsRoleRead(calldata []byte) bool {
+	return len(calldata) >= 4 && bytes.Equal(calldata[:4], hasRoleMethod.ID)
+}
+
+// ReadRolePacked executes a call to the hasRole view method of the core against the given store and
+// returns the ABI-encoded result.
+func ReadRolePacked(kv lib.KeyValueStore, calldata []byte) ([]byte, error) {
+	if !IsRoleRead(calldata) {
+		return nil, ErrCalldataIsNotRoleRead
+	}
+	args, err := hasRoleMethod.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, err
+	}
+	role, account := common.Hash(args[0].([32]byte)), args[1].(common.Address)
+	return hasRoleMethod.Outputs.Pack(HasRole(kv, role, account))
+}
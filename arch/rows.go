@@ -0,0 +1,261 @@
+package arch
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete/lib"
+	"github.com/holiman/uint256"
+)
+
+var (
+	addressType    = reflect.TypeOf(common.Address{})
+	hashType       = reflect.TypeOf(common.Hash{})
+	uint256PtrType = reflect.TypeOf((*uint256.Int)(nil))
+)
+
+// TableRow identifies a row of a table.
+type TableRow struct {
+	TableId ValidTableId
+	Keys    []interface{} // Row keys, in the order of the table key schema
+}
+
+type tableLayout struct {
+	tableId ValidTableId
+	slot    common.Hash // Slot of the table, i.e., of its only row if the table has no keys
+	nSlots  int         // Number of consecutive slots taken by a row
+	keys    []reflect.Type
+}
+
+type indexedRow struct {
+	row     TableRow
+	rowSlot common.Hash
+}
+
+// RowIndex maps the storage slots of table rows back to the table and keys of the row.
+// Slots of rows of tables with no keys are indexed on creation. Slots of rows of keyed tables are
+// indexed when the row is first accessed through a datastore wrapped by Wrap.
+// Slots holding the tail of string and bytes fields longer than 31 bytes are not indexed.
+// Entries are never evicted, as the slots of a row can be written again at any time, so the index
+// holds one entry per slot of every row accessed through it for as long as it is in use.
+type RowIndex struct {
+	tables map[common.Hash]tableLayout // Table slot -> layout of keyed tables
+	rows   map[common.Hash]indexedRow  // Slot -> row
+	lock   sync.Mutex
+}
+
+// recordingKeyValueStore is an empty key-value store that records the keys read from it.
+type recordingKeyValueStore struct {
+	keys map[common.Hash]struct{}
+}
+
+func (kv *recordingKeyValueStore) Set(key, value common.Hash) {}
+
+func (kv *recordingKeyValueStore) Get(key common.Hash) common.Hash {
+	kv.keys[key] = struct{}{}
+	return common.Hash{}
+}
+
+// slotRecordingDatastore is a datastore that records the slot of the first key accessed.
+type slotRecordingDatastore struct {
+	lib.Datastore
+	slot *common.Hash
+}
+
+func (d *slotRecordingDatastore) Get(key []byte) lib.DatastoreSlot {
+	slot := d.Datastore.Get(key)
+	if d.slot == nil {
+		s := slot.Slot()
+		d.slot = &s
+	}
+	return slot
+}
+
+// zeroKey returns the zero value of a key type that can be encoded.
+func zeroKey(keyType reflect.Type) interface{} {
+	if keyType.Kind() == reflect.Ptr {
+		return reflect.New(keyType.Elem()).Interface()
+	}
+	return reflect.Zero(keyType).Interface()
+}
+
+// tableLayout computes the slot of a table and the number of slots taken by its rows by reading a row
+// from an empty datastore.
+func (t TableSchemas) tableLayout(tableId ValidTableId) (tableLayout, error) {
+	getter := t.tableGetters[tableId.Raw()]
+	nKeys := getter.rowGetterType.NumIn() - 1 // First argument is the receiver
+	layout := tableLayout{tableId: tableId, keys: make([]reflect.Type, nKeys)}
+	keys := make([]interface{}, nKeys)
+	for ii := range keys {
+		layout.keys[ii] = getter.rowGetterType.In(ii + 1)
+		keys[ii] = zeroKey(layout.keys[ii])
+	}
+	kv := &recordingKeyValueStore{keys: make(map[common.Hash]struct{})}
+	ds := &slotRecordingDatastore{Datastore: lib.NewKVDatastore(kv)}
+	if _, err := t.Read(ds, tableId, keys...); err != nil {
+		return tableLayout{}, err
+	}
+	if ds.slot == nil {
+		return tableLayout{}, fmt.Errorf("table %s does not access the datastore", t.GetTableSchema(tableId).Name)
+	}
+	layout.slot = *ds.slot
+	layout.nSlots = len(kv.keys)
+	return layout, nil
+}
+
+// NewRowIndex creates a new RowIndex for the tables in the given schemas.
+func NewRowIndex(schemas TableSchemas) (*RowIndex, error) {
+	r := &RowIndex{
+		tables: make(map[common.Hash]tableLayout),
+		rows:   make(map[common.Hash]indexedRow),
+	}
+	for id := range schemas.tableGetters {
+		tableId, _ := schemas.NewTableId(id)
+		layout, err := schemas.tableLayout(tableId)
+		if err != nil {
+			return nil, err
+		}
+		if len(layout.keys) == 0 {
+			r.addRow(layout, TableRow{TableId: tableId, Keys: []interface{}{}}, layout.slot)
+		} else {
+			r.tables[layout.slot] = layout
+		}
+	}
+	return r, nil
+}
+
+// addRow indexes the slots of a row. Must be called with the lock held or before the index is shared.
+func (r *RowIndex) addRow(layout tableLayout, row TableRow, rowSlot common.Hash) {
+	if _, ok := r.rows[rowSlot]; ok {
+		return
+	}
+	entry := indexedRow{row: row, rowSlot: rowSlot}
+	slot := rowSlot.Big()
+	for ii := 0; ii < layout.nSlots; ii++ {
+		r.rows[common.BigToHash(slot)] = entry
+		slot.Add(slot, common.Big1)
+	}
+}
+
+// indexRow indexes the row of a keyed table at rowSlot given its encoded keys.
+func (r *RowIndex) indexRow(layout tableLayout, encodedKeys [][]byte, rowSlot common.Hash) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.rows[rowSlot]; ok {
+		return
+	}
+	if len(encodedKeys) != len(layout.keys) {
+		return
+	}
+	keys := make([]interface{}, len(encodedKeys))
+	for ii, data := range encodedKeys {
+		key, ok := decodeRowKey(data, layout.keys[ii])
+		if !ok {
+			return
+		}
+		keys[ii] = key
+	}
+	r.addRow(layout, TableRow{TableId: layout.tableId, Keys: keys}, rowSlot)
+}
+
+// Lookup returns the row the given slot belongs to and the first slot of the row.
+func (r *RowIndex) Lookup(slot common.Hash) (TableRow, common.Hash, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	entry, ok := r.rows[slot]
+	return entry.row, entry.rowSlot, ok
+}
+
+// Wrap wraps a datastore so that the rows of keyed tables accessed through it are indexed.
+func (r *RowIndex) Wrap(ds lib.Datastore) lib.Datastore {
+	return &indexingDatastore{Datastore: ds, index: r}
+}
+
+type indexingDatastore struct {
+	lib.Datastore
+	index *RowIndex
+}
+
+func (d *indexingDatastore) Get(key []byte) lib.DatastoreSlot {
+	slot := d.Datastore.Get(key)
+	if layout, ok := d.index.tables[slot.Slot()]; ok {
+		return &indexingTableSlot{DatastoreSlot: slot, index: d.index, layout: layout}
+	}
+	return slot
+}
+
+type indexingTableSlot struct {
+	lib.DatastoreSlot
+	index  *RowIndex
+	layout tableLayout
+}
+
+func (s *indexingTableSlot) Mapping() lib.Mapping {
+	return &indexingMapping{Mapping: s.DatastoreSlot.Mapping(), index: s.index, layout: s.layout}
+}
+
+type indexingMapping struct {
+	lib.Mapping
+	index  *RowIndex
+	layout tableLayout
+}
+
+func (m *indexingMapping) Get(key []byte) lib.DatastoreSlot {
+	return m.GetNested(key)
+}
+
+func (m *indexingMapping) GetNested(keys ...[]byte) lib.DatastoreSlot {
+	slot := m.Mapping.GetNested(keys...)
+	m.index.indexRow(m.layout, keys, slot.Slot())
+	return slot
+}
+
+// decodeRowKey decodes a row key encoded by the datamod codec into a value of the given type.
+func decodeRowKey(data []byte, keyType reflect.Type) (interface{}, bool) {
+	switch keyType {
+	case addressType:
+		return common.BytesToAddress(data), true
+	case hashType:
+		return common.BytesToHash(data), true
+	case uint256PtrType:
+		return new(uint256.Int).SetBytes(data), true
+	}
+	key := reflect.New(keyType).Elem()
+	switch keyType.Kind() {
+	case reflect.Bool:
+		if len(data) == 0 {
+			return nil, false
+		}
+		key.SetBool(data[0]&1 == 1)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if len(data) > 8 {
+			return nil, false
+		}
+		key.SetUint(new(big.Int).SetBytes(data).Uint64())
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if len(data) == 0 || len(data) > 8 {
+			return nil, false
+		}
+		// Sign-extend the big-endian two's complement value
+		bits := 64 - 8*uint(len(data))
+		key.SetInt(int64(new(big.Int).SetBytes(data).Uint64()<<bits) >> bits)
+	case reflect.String:
+		key.SetString(string(data))
+	case reflect.Slice:
+		if keyType.Elem().Kind() != reflect.Uint8 {
+			return nil, false
+		}
+		key.SetBytes(common.CopyBytes(data))
+	case reflect.Array:
+		if keyType.Elem().Kind() != reflect.Uint8 {
+			return nil, false
+		}
+		reflect.Copy(key, reflect.ValueOf(data))
+	default:
+		return nil, false
+	}
+	return key.Interface(), true
+}
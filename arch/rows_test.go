@@ -0,0 +1,110 @@
+package arch
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/concrete-eth/archetype/kvstore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod/codec"
+	"github.com/ethereum/go-ethereum/concrete/crypto"
+	"github.com/ethereum/go-ethereum/concrete/lib"
+)
+
+// Keyed table written like the datamod generated code
+
+type testBodiesRow struct {
+	lib.DatastoreStruct
+}
+
+func (v *testBodiesRow) GetX() int32 {
+	return codec.DecodeInt32(4, v.GetField(0))
+}
+
+func (v *testBodiesRow) SetX(value int32) {
+	v.SetField(0, codec.EncodeInt32(4, value))
+}
+
+func (v *testBodiesRow) GetH() common.Hash {
+	return codec.DecodeHash(32, v.GetField(1))
+}
+
+func (v *testBodiesRow) SetH(value common.Hash) {
+	v.SetField(1, codec.EncodeHash(32, value))
+}
+
+type testBodies struct {
+	dsSlot lib.DatastoreSlot
+}
+
+func newTestBodies(ds lib.Datastore) *testBodies {
+	return &testBodies{ds.Get(crypto.Keccak256([]byte("datamod.v1.Bodies")))}
+}
+
+func (m *testBodies) Get(owner common.Address, bodyId int16) *testBodiesRow {
+	dsSlot := m.dsSlot.Mapping().GetNested(
+		codec.EncodeAddress(20, owner),
+		codec.EncodeInt16(2, bodyId),
+	)
+	return &testBodiesRow{*lib.NewDatastoreStruct(dsSlot, []int{4, 32})}
+}
+
+type testRowData_Bodies struct {
+	X int32
+	H common.Hash
+}
+
+const testBodiesABIJson = `[{"type":"function","name":"getBodiesRow","inputs":[{"name":"owner","type":"address"},{"name":"bodyId","type":"int16"}],"outputs":[{"name":"","type":"tuple","components":[{"name":"x","type":"int32"},{"name":"h","type":"bytes32"}]}],"stateMutability":"view"}]`
+
+const testBodiesSchemasJson = `{"bodies":{"keySchema":{"owner":"address","bodyId":"int16"},"schema":{"x":"int32","h":"bytes32"}}}`
+
+func TestRowIndex(t *testing.T) {
+	schemas, err := NewTableSchemasFromRaw(
+		testBodiesABIJson,
+		testBodiesSchemasJson,
+		map[string]reflect.Type{"Bodies": reflect.TypeOf(testRowData_Bodies{})},
+		map[string]interface{}{"Bodies": newTestBodies},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := NewRowIndex(schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableId, _ := schemas.TableIdFromName("Bodies")
+
+	var (
+		owner = common.HexToAddress("0xc0ffee")
+		kv    = kvstore.NewStagedKeyValueStore(kvstore.NewMemoryKeyValueStore())
+		ds    = index.Wrap(lib.NewKVDatastore(kv))
+	)
+	row := newTestBodies(ds).Get(owner, -2)
+	row.SetX(1)
+	row.SetH(common.Hash{0x01})
+
+	keys := kv.CommitWithJournal().Keys()
+	if len(keys) != 2 {
+		t.Fatalf("expected %v keys written, got %v", 2, len(keys))
+	}
+	var firstRowSlot common.Hash
+	for ii, key := range keys {
+		tableRow, rowSlot, ok := index.Lookup(key)
+		if !ok {
+			t.Fatalf("key %v not indexed", key.Hex())
+		}
+		if ii == 0 {
+			firstRowSlot = rowSlot
+		} else if rowSlot != firstRowSlot {
+			t.Errorf("expected row slot %v, got %v", firstRowSlot.Hex(), rowSlot.Hex())
+		}
+		expRow := TableRow{TableId: tableId, Keys: []interface{}{owner, int16(-2)}}
+		if !reflect.DeepEqual(tableRow, expRow) {
+			t.Errorf("expected %v, got %v", expRow, tableRow)
+		}
+	}
+
+	if _, _, ok := index.Lookup(common.Hash{0x01}); ok {
+		t.Error("unexpected row for unknown slot")
+	}
+}
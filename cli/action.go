@@ -0,0 +1,433 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/concrete-eth/archetype/arch"
+	"github.com/concrete-eth/archetype/client"
+	"github.com/concrete-eth/archetype/params"
+	"github.com/concrete-eth/archetype/rpc"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/iancoleman/orderedmap"
+	"github.com/spf13/cobra"
+)
+
+// loadActionSchemas returns the action schemas built from the actions ABI and JSON schema given by the
+// --abi and --actions flags, or defaultSchemas if neither is given and defaultSchemas is not empty.
+// Schemas loaded from files use struct types built from the ABI argument types as action types.
+func loadActionSchemas(cmd *cobra.Command, defaultSchemas arch.ActionSchemas) arch.ActionSchemas {
+	abiPath, err := cmd.Flags().GetString("abi")
+	if err != nil {
+		logFatal(err)
+	}
+	actionsPath, err := cmd.Flags().GetString("actions")
+	if err != nil {
+		logFatal(err)
+	}
+	if abiPath == "" && actionsPath == "" {
+		if len(defaultSchemas.ActionNames()) == 0 {
+			logFatalNoContext(errors.New("--abi and --actions are required"))
+		}
+		return defaultSchemas
+	}
+	if abiPath == "" || actionsPath == "" {
+		logFatalNoContext(errors.New("--abi and --actions must be given together"))
+	}
+
+	ABI, err := loadAbi(abiPath)
+	if err != nil {
+		logFatalNoContext(fmt.Errorf("could not load ABI: %w", err))
+	}
+	actionsJson, err := os.ReadFile(actionsPath)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	schemas, err := datamod.UnmarshalTableSchemas(actionsJson, false)
+	if err != nil {
+		logFatalNoContext(fmt.Errorf("could not load action schemas: %w", err))
+	}
+	permissions, err := arch.UnmarshalPermissions(actionsJson)
+	if err != nil {
+		logFatalNoContext(fmt.Errorf("could not load action permissions: %w", err))
+	}
+	types := make(map[string]reflect.Type, len(schemas))
+	for _, schema := range schemas {
+		method, ok := ABI.Methods[params.SolidityActionMethodName(schema.Name)]
+		if !ok {
+			logFatalNoContext(fmt.Errorf("action %s not found in ABI", schema.Name))
+		}
+		actionType, err := actionTypeFromMethod(schema, method)
+		if err != nil {
+			logFatalNoContext(err)
+		}
+		types[schema.Name] = actionType
+	}
+	actionSchemas, err := arch.NewActionSchemas(ABI, schemas, types)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	actionSchemas.SetPermissions(permissions)
+	return actionSchemas
+}
+
+// actionTypeFromMethod returns a struct type with the keys of an action followed by the fields of its
+// value tuple, like the action types generated by gogen, with the types of the action method arguments.
+// A field tagged with the action name makes the types of actions with the same fields distinct.
+func actionTypeFromMethod(schema datamod.TableSchema, method abi.Method) (reflect.Type, error) {
+	nArgs := len(schema.Keys)
+	if len(schema.Values) > 0 {
+		nArgs++
+	}
+	if len(method.Inputs) != nArgs {
+		return nil, fmt.Errorf("action method %s takes %d arguments, expected %d", method.Name, len(method.Inputs), nArgs)
+	}
+	fields := []reflect.StructField{{
+		Name: "Action_",
+		Type: reflect.TypeOf(struct{}{}),
+		Tag:  reflect.StructTag(fmt.Sprintf(`json:"-" action:"%s"`, schema.Name)),
+	}}
+	for ii, key := range schema.Keys {
+		fields = append(fields, reflect.StructField{
+			Name: key.Title,
+			Type: method.Inputs[ii].Type.GetType(),
+			Tag:  reflect.StructTag(fmt.Sprintf(`json:"%s"`, key.Name)),
+		})
+	}
+	if len(schema.Values) > 0 {
+		tupleType := method.Inputs[len(schema.Keys)].Type.GetType()
+		for _, value := range schema.Values {
+			field, ok := tupleType.FieldByName(value.Title)
+			if !ok {
+				return nil, fmt.Errorf("action method %s has no field %s", method.Name, value.Name)
+			}
+			fields = append(fields, reflect.StructField{
+				Name: value.Title,
+				Type: field.Type,
+				Tag:  reflect.StructTag(fmt.Sprintf(`json:"%s"`, value.Name)),
+			})
+		}
+	}
+	return reflect.StructOf(fields), nil
+}
+
+// parseAction creates an action of the given type from its JSON-encoded fields, keyed by their name
+// in the action schema.
+func parseAction(schemas arch.ActionSchemas, name string, fieldsJson string) (arch.Action, error) {
+	actionId, ok := schemas.ActionIdFromName(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown action %s (actions: %s)", name, strings.Join(schemas.ActionNames(), ", "))
+	}
+	schema := schemas.GetActionSchema(actionId)
+	action := reflect.New(schema.Type).Interface()
+	decoder := json.NewDecoder(strings.NewReader(fieldsJson))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(action); err != nil {
+		return nil, fmt.Errorf("invalid %s fields: %w", name, err)
+	}
+	return action, nil
+}
+
+// parseActions parses the action name and JSON-encoded field arguments of the send command.
+func parseActions(schemas arch.ActionSchemas, args []string) ([]arch.Action, error) {
+	if len(args)%2 != 0 {
+		return nil, errors.New("expected pairs of action name and JSON-encoded fields")
+	}
+	actions := make([]arch.Action, 0, len(args)/2)
+	for ii := 0; ii < len(args); ii += 2 {
+		action, err := parseAction(schemas, args[ii], args[ii+1])
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+func newRunActionSend(defaultSchemas arch.ActionSchemas) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		schemas := loadActionSchemas(cmd, defaultSchemas)
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			logFatal(err)
+		}
+		estimate, err := cmd.Flags().GetBool("estimate")
+		if err != nil {
+			logFatal(err)
+		}
+		actions, err := parseActions(schemas, args)
+		if err != nil {
+			logFatalNoContext(err)
+		}
+		privateKey, err := loadPrivateKey(cmd)
+		if err != nil {
+			logFatalNoContext(err)
+		}
+		if privateKey == nil && !dryRun && !estimate {
+			logFatalNoContext(errors.New("--keystore or --private-key-file is required to send actions"))
+		}
+
+		var (
+			ethcli   rpc.EthCli
+			address  common.Address
+			from     common.Address
+			nonce    uint64
+			signerFn bind.SignerFn
+		)
+		if privateKey != nil {
+			from = crypto.PubkeyToAddress(privateKey.PublicKey)
+		}
+		if !dryRun {
+			// Packing the actions does not require a connection
+			client := ethclient.NewClient(newRpcClient(cmd))
+			ethcli = client
+			address = getGameAddress(cmd)
+			if !estimate {
+				chainId, err := client.ChainID(context.Background())
+				if err != nil {
+					logFatalNoContext(err)
+				}
+				opts, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+				if err != nil {
+					logFatal(err)
+				}
+				if nonce, err = client.PendingNonceAt(context.Background(), from); err != nil {
+					logFatalNoContext(err)
+				}
+				signerFn = opts.Signer
+			}
+		}
+		sender := rpc.NewActionSender(ethcli, schemas, nil, address, from, nonce, signerFn)
+
+		switch {
+		case dryRun:
+			data, err := sender.PackActions(actions)
+			if err != nil {
+				logFatalNoContext(err)
+			}
+			logInfo(hexutil.Encode(data))
+		case estimate:
+			gas, err := sender.EstimateGas(actions)
+			if err != nil {
+				logFatalNoContext(err)
+			}
+			logInfo("%d", gas)
+		default:
+			tx, err := sender.SendActions(actions)
+			if err != nil {
+				logFatalNoContext(err)
+			}
+			logInfo(tx.Hash().Hex())
+		}
+	}
+}
+
+// actionLogPrinter prints action batches as JSON, resolving the sender of every action transaction.
+type actionLogPrinter struct {
+	ethcli  *ethclient.Client
+	schemas arch.ActionSchemas
+	signer  types.Signer
+	senders map[common.Hash]string // Tx hash -> sender, empty if unknown
+}
+
+func newActionLogPrinter(ethcli *ethclient.Client, schemas arch.ActionSchemas) (*actionLogPrinter, error) {
+	chainId, err := ethcli.ChainID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &actionLogPrinter{
+		ethcli:  ethcli,
+		schemas: schemas,
+		signer:  types.LatestSignerForChainID(chainId),
+		senders: make(map[common.Hash]string),
+	}, nil
+}
+
+// sender returns the hex address of the sender of the transaction with the given hash, or an empty
+// string if the sender cannot be recovered, e.g., for deposit transactions.
+func (p *actionLogPrinter) sender(txHash common.Hash) (string, error) {
+	if sender, ok := p.senders[txHash]; ok {
+		return sender, nil
+	}
+	tx, _, err := p.ethcli.TransactionByHash(context.Background(), txHash)
+	if err != nil {
+		return "", err
+	}
+	var sender string
+	if addr, err := types.Sender(p.signer, tx); err != nil {
+		logWarning(fmt.Sprintf("could not recover the sender of transaction %s: %v", txHash.Hex(), err))
+	} else {
+		sender = addr.Hex()
+	}
+	p.senders[txHash] = sender
+	return sender, nil
+}
+
+// actionToJson returns an action as JSON with its name, fields, and the hash and sender of the
+// transaction that included it.
+func (p *actionLogPrinter) actionToJson(action arch.Action, log types.Log) (*orderedmap.OrderedMap, error) {
+	sender, err := p.sender(log.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	obj := orderedmap.New()
+	obj.Set("txHash", log.TxHash.Hex())
+	obj.Set("sender", sender)
+	if actionId, ok := p.schemas.ActionIdFromAction(action); ok {
+		schema := p.schemas.GetActionSchema(actionId)
+		obj.Set("action", schema.Name)
+		fields := make([]datamod.FieldSchema, 0, len(schema.Keys)+len(schema.Values))
+		fields = append(append(fields, schema.Keys...), schema.Values...)
+		obj.Set("fields", fieldsToJson(fields, action))
+	} else {
+		obj.Set("action", fmt.Sprintf("%T", action))
+	}
+	return obj, nil
+}
+
+// print prints the actions of a batch. Batches with no actions are not printed.
+func (p *actionLogPrinter) print(batch arch.ActionBatchWithLogs) error {
+	if batch.IsReorg() {
+		logWarning(fmt.Sprintf("chain reorganization orphaned the last %d printed blocks", batch.ReorgDepth))
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+	actions := make([]*orderedmap.OrderedMap, batch.Len())
+	for ii, action := range batch.Actions {
+		obj, err := p.actionToJson(action, batch.Logs[ii])
+		if err != nil {
+			return err
+		}
+		actions[ii] = obj
+	}
+	obj := orderedmap.New()
+	obj.Set("blockNumber", batch.BlockNumber)
+	obj.Set("blockHash", batch.BlockHash.Hex())
+	obj.Set("actions", actions)
+	printJson(obj)
+	return nil
+}
+
+// getBlockFlag returns the block number given by a flag that takes a block number or "latest".
+func getBlockFlag(cmd *cobra.Command, name string, ethcli *ethclient.Client) uint64 {
+	block, err := cmd.Flags().GetString(name)
+	if err != nil {
+		logFatal(err)
+	}
+	if block == "latest" {
+		blockNumber, err := ethcli.BlockNumber(context.Background())
+		if err != nil {
+			logFatalNoContext(err)
+		}
+		return blockNumber
+	}
+	blockNumber, err := strconv.ParseUint(block, 0, 64)
+	if err != nil {
+		logFatalNoContext(fmt.Errorf("invalid --%s block: %s", name, block))
+	}
+	return blockNumber
+}
+
+func newRunActionLog(defaultSchemas arch.ActionSchemas) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		schemas := loadActionSchemas(cmd, defaultSchemas)
+		follow, err := cmd.Flags().GetBool("follow")
+		if err != nil {
+			logFatal(err)
+		}
+		if follow && cmd.Flags().Changed("to") {
+			logFatalNoContext(errors.New("--to cannot be set with --follow"))
+		}
+
+		address := getCoreAddress(cmd)
+		ethcli := ethclient.NewClient(newRpcClient(cmd))
+		printer, err := newActionLogPrinter(ethcli, schemas)
+		if err != nil {
+			logFatalNoContext(err)
+		}
+		fromBlock := getBlockFlag(cmd, "from", ethcli)
+
+		if !follow {
+			toBlock := getBlockFlag(cmd, "to", ethcli)
+			if toBlock < fromBlock {
+				logFatalNoContext(fmt.Errorf("--to block %d is before --from block %d", toBlock, fromBlock))
+			}
+			if err := rpc.FetchActionBatches(ethcli, schemas, address, fromBlock, toBlock, false, printer.print); err != nil {
+				logFatalNoContext(err)
+			}
+			return
+		}
+
+		// Print the batches from the starting block and then of every new block until interrupted
+		batchesChan := make(chan arch.ActionBatchWithLogs, 16)
+		sub := rpc.SubscribeActionBatches(ethcli, schemas, address, fromBlock, client.MaxRollbackDepth, false, batchesChan)
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case batch := <-batchesChan:
+				if err := printer.print(batch); err != nil {
+					logFatalNoContext(err)
+				}
+			case err := <-sub.Err():
+				if err != nil {
+					logFatalNoContext(err)
+				}
+				return
+			}
+		}
+	}
+}
+
+// AddActionCommand adds commands that send actions to a game contract and print the actions it executed.
+// Actions are described by the given schemas unless the --abi and --actions flags are set.
+func AddActionCommand(parent *cobra.Command, schemas arch.ActionSchemas) {
+	actionCmd := &cobra.Command{Use: "action", Aliases: []string{"actions"}, Short: "Send and inspect game actions"}
+	actionCmd.PersistentFlags().String("abi", "", "actions ABI or forge artifact JSON file")
+	actionCmd.PersistentFlags().String("actions", "", "actions JSON schema file")
+
+	sendCmd := &cobra.Command{
+		Use:   "send <action> <fields> [<action> <fields>...]",
+		Short: "Send one or more actions in a single transaction",
+		Long: `Send one or more actions in a single transaction.
+Every action is given by its name followed by its fields as a JSON object, e.g., '{"x": 1, "y": 2}'.
+Several actions are sent in a single call to the multi-action method of the game contract.`,
+		Args: cobra.MinimumNArgs(2),
+		Run:  newRunActionSend(schemas),
+	}
+	addAddressFlags(sendCmd, "game contract address")
+	addKeyFlags(sendCmd)
+	sendCmd.Flags().Bool("dry-run", false, "print the transaction calldata instead of sending it")
+	sendCmd.Flags().Bool("estimate", false, "print the estimated gas instead of sending the transaction")
+	addRpcFlags(sendCmd)
+
+	logCmd := &cobra.Command{
+		Use:   "log",
+		Short: "Print the actions executed in a range of blocks as JSON",
+		Long: `Print the actions executed in a range of blocks as JSON, one object per block with actions.
+With --follow, print the actions of new blocks as they are added to the chain until interrupted.`,
+		Args: cobra.NoArgs,
+		Run:  newRunActionLog(schemas),
+	}
+	addAddressFlags(logCmd, "core proxy contract address")
+	logCmd.Flags().String("from", "latest", "first block to print, inclusive")
+	logCmd.Flags().String("to", "latest", "last block to print, inclusive")
+	logCmd.Flags().BoolP("follow", "f", false, "keep printing the actions of new blocks")
+	addRpcFlags(logCmd)
+
+	actionCmd.AddCommand(sendCmd, logCmd)
+	parent.AddCommand(actionCmd)
+}
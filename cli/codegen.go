@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -30,6 +31,8 @@ const (
 	ABIGEN_BIN          = "abigen"
 )
 
+const defaultEventsJsonPath = "./events.json"
+
 /* Logging */
 
 func logTaskSuccess(name string, more ...any) {
@@ -176,6 +179,60 @@ func printSchemasDescription(title string, schemas []datamod.TableSchema) {
 	fmt.Println(description)
 }
 
+// printSchemaChanges prints schema changes with their compatibility and returns the least compatible
+// classification of them.
+func printSchemaChanges(title string, changes []codegen.SchemaChange) codegen.Compatibility {
+	bold.Println(title)
+	if len(changes) == 0 {
+		gray.Println("No changes")
+	}
+	for _, change := range changes {
+		switch change.Compatibility {
+		case codegen.Safe:
+			green.Print("[SAFE] ")
+		case codegen.NeedsMigration:
+			yellow.Print("[NEEDS MIGRATION] ")
+		default:
+			red.Print("[BREAKING] ")
+		}
+		fmt.Println(change)
+	}
+	return codegen.MaxCompatibility(changes)
+}
+
+// checkSchemaFiles prints the changes between the old and new schemas in the files given by the flags
+// with the given names and returns the least compatible classification of them.
+// Nothing is checked if the old schema file is not given.
+func checkSchemaFiles(
+	cmd *cobra.Command,
+	title, oldFlag, newFlag string,
+	checkFn func(oldSchemas, newSchemas []datamod.TableSchema) []codegen.SchemaChange,
+) (codegen.Compatibility, error) {
+	oldPath, err := cmd.Flags().GetString(oldFlag)
+	if err != nil {
+		logFatal(err)
+	}
+	newPath, err := cmd.Flags().GetString(newFlag)
+	if err != nil {
+		logFatal(err)
+	}
+	if oldPath == "" {
+		return codegen.Safe, nil
+	}
+	if newPath == "" {
+		return codegen.Safe, fmt.Errorf("--%s is required with --%s", newFlag, oldFlag)
+	}
+	oldSchemas, err := loadSchemasFromFile(oldPath)
+	if err != nil {
+		return codegen.Safe, fmt.Errorf("could not load %s: %w", oldPath, err)
+	}
+	newSchemas, err := loadSchemasFromFile(newPath)
+	if err != nil {
+		return codegen.Safe, fmt.Errorf("could not load %s: %w", newPath, err)
+	}
+	return printSchemaChanges(title, checkFn(oldSchemas, newSchemas)), nil
+}
+
 /* Codegen */
 
 // getGogenConfig returns a gogen config from viper settings.
@@ -206,6 +263,7 @@ func getGogenConfig() (gogen.Config, error) {
 		Config: codegen.Config{
 			ActionsJsonPath: actions,
 			TablesJsonPath:  tables,
+			EventsJsonPath:  getEventsJsonPath(),
 			Out:             gogenOut,
 		},
 		PackageName:         pkg,
@@ -217,6 +275,16 @@ func getGogenConfig() (gogen.Config, error) {
 	return config, nil
 }
 
+// getEventsJsonPath returns the events schema path from the viper settings.
+// Events are optional, so an empty path is returned if the default schema file does not exist.
+func getEventsJsonPath() string {
+	events := viper.GetString("events")
+	if events == defaultEventsJsonPath && codegen.CheckFile(events) != nil {
+		return ""
+	}
+	return events
+}
+
 // getDatamodOut returns the output directory for the datamod command.
 func getDatamodOut() string {
 	goOut := viper.GetString("go-out")
@@ -242,6 +310,7 @@ func getSolgenConfig() solgen.Config {
 		Config: codegen.Config{
 			ActionsJsonPath: actions,
 			TablesJsonPath:  tables,
+			EventsJsonPath:  getEventsJsonPath(),
 			Out:             solOut,
 		},
 	}
@@ -352,9 +421,23 @@ func runCodegen(cmd *cobra.Command, args []string) {
 	}
 
 	for _, schema := range actionsSchemas {
+		for _, key := range schema.Keys {
+			if key.Type.Type == datamod.BytesType {
+				logFatal(fmt.Errorf("action %s has key %s of dynamic type %s. Action keys must be of static types", schema.Name, key.Name, key.Type.Name))
+			}
+		}
+	}
+
+	var eventsSchemas []datamod.TableSchema
+	if gogenConfig.EventsJsonPath != "" {
+		if eventsSchemas, err = loadSchemasFromFile(gogenConfig.EventsJsonPath); err != nil {
+			logFatal(err)
+		}
+	}
+
+	for _, schema := range eventsSchemas {
 		if len(schema.Keys) > 0 {
-			logWarning(fmt.Sprintf("Action %s has keys defined. Keys are not supported in actions.", schema.Name))
-			hasPreliminaryWarnings = true
+			logFatal(fmt.Errorf("event %s has keys defined. Keys are not supported in events", schema.Name))
 		}
 	}
 
@@ -368,6 +451,10 @@ func runCodegen(cmd *cobra.Command, args []string) {
 		fmt.Println("")
 		printSchemasDescription("Tables", tablesSchemas)
 		fmt.Println("")
+		if len(eventsSchemas) > 0 {
+			printSchemasDescription("Events", eventsSchemas)
+			fmt.Println("")
+		}
 	}
 
 	// Run concrete datamod
@@ -553,6 +640,7 @@ func AddCodegenCommand(parent *cobra.Command) {
 	codegenCmd.Flags().StringP("forge-out", "f", "./out", "forge output directory")
 	codegenCmd.Flags().StringP("tables", "t", "./tables.json", "table schema file")
 	codegenCmd.Flags().StringP("actions", "a", "./actions.json", "action schema file")
+	codegenCmd.Flags().StringP("events", "e", defaultEventsJsonPath, "event schema file (optional)")
 	codegenCmd.Flags().String("pkg", "archmod", "go package name")
 	codegenCmd.Flags().BoolP("verbose", "v", false, "verbose output")
 	codegenCmd.Flags().Bool("more-experimental", false, "enable experimental features")
@@ -564,14 +652,49 @@ func AddCodegenCommand(parent *cobra.Command) {
 	viper.BindPFlag("forge-out", codegenCmd.Flags().Lookup("forge-out"))
 	viper.BindPFlag("tables", codegenCmd.Flags().Lookup("tables"))
 	viper.BindPFlag("actions", codegenCmd.Flags().Lookup("actions"))
+	viper.BindPFlag("events", codegenCmd.Flags().Lookup("events"))
 	viper.BindPFlag("pkg", codegenCmd.Flags().Lookup("pkg"))
 	viper.BindPFlag("verbose", codegenCmd.Flags().Lookup("verbose"))
 	viper.BindPFlag("more-experimental", codegenCmd.Flags().Lookup("more-experimental"))
 	viper.BindPFlag("concrete-bin", codegenCmd.Flags().Lookup("concrete-bin"))
 
+	// Check command
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the compatibility of table and action schema changes",
+		Long: `Compare the given old and new table and action schemas and classify every change as safe, needing a
+migration of the rows in storage, or breaking, i.e., making rows unreachable or old action logs
+impossible to decode. Exits with an error if any change is breaking.`,
+		Args: cobra.NoArgs,
+		Run:  runCodegenCheck,
+	}
+	checkCmd.Flags().String("old-tables", "", "old table schema file")
+	checkCmd.Flags().StringP("tables", "t", "./tables.json", "new table schema file")
+	checkCmd.Flags().String("old-actions", "", "old action schema file")
+	checkCmd.Flags().StringP("actions", "a", "./actions.json", "new action schema file")
+	codegenCmd.AddCommand(checkCmd)
+
 	parent.AddCommand(codegenCmd)
 }
 
+// runCodegenCheck checks the compatibility of schema changes.
+func runCodegenCheck(cmd *cobra.Command, args []string) {
+	if !cmd.Flags().Changed("old-tables") && !cmd.Flags().Changed("old-actions") {
+		logFatalNoContext(errors.New("--old-tables or --old-actions is required"))
+	}
+	tablesCompat, err := checkSchemaFiles(cmd, "Tables", "old-tables", "tables", codegen.CheckTableSchemas)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	actionsCompat, err := checkSchemaFiles(cmd, "Actions", "old-actions", "actions", codegen.CheckActionSchemas)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	if tablesCompat == codegen.Breaking || actionsCompat == codegen.Breaking {
+		logFatalNoContext(errors.New("schema changes are breaking"))
+	}
+}
+
 // initConfig loads the viper configuration from the given or default file and the environment.
 // See https://github.com/spf13/viper?tab=readme-ov-file#why-viper for precedence order.
 func initConfig(cfgFile string) {
@@ -1,10 +1,17 @@
 package cli
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
 	"errors"
+	"fmt"
+	"os"
 
+	"github.com/concrete-eth/archetype/deploy"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/spf13/cobra"
@@ -51,3 +58,88 @@ func getAddress(cmd *cobra.Command) common.Address {
 	address := common.HexToAddress(addressHex)
 	return address
 }
+
+func addKeyFlags(cmd *cobra.Command) {
+	cmd.Flags().String("keystore", "", "keystore file of the sender account")
+	cmd.Flags().String("password-file", "", "file holding the keystore password")
+	cmd.Flags().String("private-key-file", "", "file holding the hex-encoded private key of the sender account")
+}
+
+// loadPrivateKey loads the private key given by the --keystore or --private-key-file flags, or nil if
+// neither is set.
+func loadPrivateKey(cmd *cobra.Command) (*ecdsa.PrivateKey, error) {
+	keystorePath, err := cmd.Flags().GetString("keystore")
+	if err != nil {
+		logFatal(err)
+	}
+	passwordPath, err := cmd.Flags().GetString("password-file")
+	if err != nil {
+		logFatal(err)
+	}
+	privateKeyPath, err := cmd.Flags().GetString("private-key-file")
+	if err != nil {
+		logFatal(err)
+	}
+
+	switch {
+	case keystorePath != "" && privateKeyPath != "":
+		return nil, errors.New("--keystore and --private-key-file are mutually exclusive")
+	case keystorePath != "":
+		keyJson, err := os.ReadFile(keystorePath)
+		if err != nil {
+			return nil, err
+		}
+		var password []byte
+		if passwordPath != "" {
+			if password, err = os.ReadFile(passwordPath); err != nil {
+				return nil, err
+			}
+		}
+		key, err := keystore.DecryptKey(keyJson, string(bytes.TrimRight(password, "\r\n")))
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt keystore: %w", err)
+		}
+		return key.PrivateKey, nil
+	case privateKeyPath != "":
+		return crypto.LoadECDSA(privateKeyPath)
+	default:
+		return nil, nil
+	}
+}
+
+func addAddressFlags(cmd *cobra.Command, usage string) {
+	cmd.Flags().StringP("address", "a", "", usage)
+	cmd.Flags().StringP("manifest", "m", "", "deployment manifest to read the address from if --address is not set")
+}
+
+// getDeploymentAddress returns the address given by the --address flag or, if not set, read from the
+// deployment manifest given by the --manifest flag.
+func getDeploymentAddress(cmd *cobra.Command, fromManifest func(deploy.Manifest) common.Address) common.Address {
+	addressHex, err := cmd.Flags().GetString("address")
+	if err != nil {
+		logFatal(err)
+	}
+	manifestPath, err := cmd.Flags().GetString("manifest")
+	if err != nil {
+		logFatal(err)
+	}
+	if addressHex != "" || manifestPath == "" {
+		return getAddress(cmd)
+	}
+	manifest, err := deploy.ReadManifest(manifestPath)
+	if err != nil {
+		logFatalNoContext(fmt.Errorf("could not read deployment manifest: %w", err))
+	}
+	return fromManifest(manifest)
+}
+
+// getGameAddress returns the address of the game contract, which actions are sent to.
+func getGameAddress(cmd *cobra.Command) common.Address {
+	return getDeploymentAddress(cmd, func(m deploy.Manifest) common.Address { return m.Game })
+}
+
+// getCoreAddress returns the address of the core proxy contract, which emits action logs and serves
+// table reads.
+func getCoreAddress(cmd *cobra.Command) common.Address {
+	return getDeploymentAddress(cmd, func(m deploy.Manifest) common.Address { return m.Proxy })
+}
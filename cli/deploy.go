@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/concrete-eth/archetype/deploy"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+func runDeploy(cmd *cobra.Command, args []string) {
+	artifactPath, err := cmd.Flags().GetString("artifact")
+	if err != nil {
+		logFatal(err)
+	}
+	logicHex, err := cmd.Flags().GetString("logic")
+	if err != nil {
+		logFatal(err)
+	}
+	initDataHex, err := cmd.Flags().GetString("init-data")
+	if err != nil {
+		logFatal(err)
+	}
+	manifestPath, err := cmd.Flags().GetString("manifest")
+	if err != nil {
+		logFatal(err)
+	}
+
+	checkIsHexAddress(logicHex)
+	logic := common.HexToAddress(logicHex)
+	var initData []byte
+	if initDataHex != "" {
+		if initData, err = hexutil.Decode(initDataHex); err != nil {
+			logFatalNoContext(fmt.Errorf("invalid init data: %w", err))
+		}
+	}
+	artifact, err := deploy.LoadForgeArtifact(artifactPath)
+	if err != nil {
+		logFatalNoContext(fmt.Errorf("could not load artifact: %w", err))
+	}
+	privateKey, err := loadPrivateKey(cmd)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	if privateKey == nil {
+		logFatalNoContext(errors.New("--keystore or --private-key-file is required to deploy"))
+	}
+
+	ethcli := ethclient.NewClient(newRpcClient(cmd))
+	chainId, err := ethcli.ChainID(context.Background())
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+	if err != nil {
+		logFatal(err)
+	}
+
+	logDebug("Deploying game contract from %s", artifactPath)
+	gameAddr, coreAddr, err := deploy.DeployGame(auth, ethcli, deploy.NewArtifactDeployer(artifact), logic, initData, false)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+
+	manifest := deploy.Manifest{
+		ChainId: chainId.Uint64(),
+		Game:    gameAddr,
+		Proxy:   coreAddr,
+		Logic:   logic,
+	}
+	if manifestPath != "" {
+		if err := deploy.WriteManifest(manifestPath, manifest); err != nil {
+			logFatalNoContext(err)
+		}
+		logDebug("Wrote deployment manifest to %s", manifestPath)
+	}
+	printJson(manifest)
+}
+
+// AddDeployCommand adds a command that deploys a game contract and writes a deployment manifest.
+func AddDeployCommand(parent *cobra.Command) {
+	deployCmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy a game contract",
+		Long: `Deploy a game contract from a forge build artifact and initialize it, deploying the core proxy
+that forwards calls to the core logic, e.g., the core precompile.
+The addresses of the deployed contracts are printed and written to the deployment manifest, which the
+table and action commands can read with --manifest.`,
+		Args: cobra.NoArgs,
+		Run:  runDeploy,
+	}
+	deployCmd.Flags().String("artifact", "", "forge build artifact of the game contract")
+	deployCmd.Flags().String("logic", "", "core logic address, e.g., the core precompile address")
+	deployCmd.Flags().String("init-data", "", "hex-encoded data the proxy is initialized with")
+	deployCmd.Flags().StringP("manifest", "m", "deployment.json", "deployment manifest output file")
+	deployCmd.MarkFlagRequired("artifact")
+	deployCmd.MarkFlagRequired("logic")
+	addKeyFlags(deployCmd)
+	addRpcFlags(deployCmd)
+
+	parent.AddCommand(deployCmd)
+}
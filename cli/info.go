@@ -12,12 +12,10 @@ import (
 
 var implementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
 
-func runInfo(cmd *cobra.Command, args []string) {
-	address := getAddress(cmd)
-	rpcClient := newRpcClient(cmd)
-	ethcli := ethclient.NewClient(rpcClient)
-
-	adminContract, err := admin_contract.NewContract(address, ethcli)
+// getProxyAndLogic returns the address of the core proxy of the game contract at gameAddr and of the
+// logic the proxy points to.
+func getProxyAndLogic(ethcli *ethclient.Client, gameAddr common.Address) (common.Address, common.Address) {
+	adminContract, err := admin_contract.NewContract(gameAddr, ethcli)
 	if err != nil {
 		logFatal(err)
 	}
@@ -32,6 +30,14 @@ func runInfo(cmd *cobra.Command, args []string) {
 		logFatal(err)
 	}
 	logicAddr := common.BytesToAddress(logicAddrBytes)
+	return proxyAddr, logicAddr
+}
+
+func runInfo(cmd *cobra.Command, args []string) {
+	address := getAddress(cmd)
+	rpcClient := newRpcClient(cmd)
+	ethcli := ethclient.NewClient(rpcClient)
+	proxyAddr, logicAddr := getProxyAndLogic(ethcli, address)
 
 	info := struct {
 		Game  string `json:"game"`
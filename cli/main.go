@@ -1,24 +1,45 @@
 package cli
 
 import (
+	"github.com/concrete-eth/archetype/arch"
 	"github.com/spf13/cobra"
 )
 
 /* CLI */
 
 // NewRootCmd creates the root command for the CLI.
+// Table and action commands load the schemas of a game from the files given by their flags.
 func NewRootCmd() *cobra.Command {
+	return NewGameRootCmd(arch.ArchSchemas{}, nil)
+}
+
+// NewGameRootCmd creates the root command for the CLI of a game with the given schemas and core.
+// Table and action commands describe tables and actions with the given schemas unless schema files are
+// given. The replay command replays actions against cores created with newCore and is only added if
+// newCore is not nil.
+func NewGameRootCmd(schemas arch.ArchSchemas, newCore func() arch.Core) *cobra.Command {
 	var rootCmd = &cobra.Command{Use: "archetype"}
 	AddCodegenCommand(rootCmd)
 	AddSnapshotCommand(rootCmd)
 	AddInfoCommand(rootCmd)
+	if newCore != nil {
+		AddReplayCommand(rootCmd, schemas, newCore)
+	}
+	AddTableCommand(rootCmd, schemas.Tables)
+	AddActionCommand(rootCmd, schemas.Actions)
+	AddDeployCommand(rootCmd)
+	AddUpgradeCommand(rootCmd)
 	return rootCmd
 }
 
 // Execute runs the CLI.
 func Execute() {
-	rootCmd := NewRootCmd()
+	ExecuteCmd(NewRootCmd())
+}
+
+// ExecuteCmd runs the CLI with the given root command, e.g., one created with NewGameRootCmd.
+func ExecuteCmd(rootCmd *cobra.Command) {
 	if err := rootCmd.Execute(); err != nil {
-		logFatal(err)
+		logFatalNoContext(err)
 	}
 }
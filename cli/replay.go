@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/concrete-eth/archetype/arch"
+	"github.com/concrete-eth/archetype/replay"
+	"github.com/concrete-eth/archetype/rpc"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// ErrReplayDiverged is returned by the replay command when the replayed storage diverges from the
+// onchain storage.
+var ErrReplayDiverged = errors.New("replay diverged")
+
+func newRunReplay(schemas arch.ArchSchemas, newCore func() arch.Core) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		address := getAddress(cmd)
+		rpcClient := newRpcClient(cmd)
+		ethcli := ethclient.NewClient(rpcClient)
+
+		toBlock, err := cmd.Flags().GetUint64("to")
+		if err != nil {
+			logFatal(err)
+		}
+		useSnapshot, err := cmd.Flags().GetBool("snapshot")
+		if err != nil {
+			logFatal(err)
+		}
+		snapshotFile, err := cmd.Flags().GetString("snapshot-file")
+		if err != nil {
+			logFatal(err)
+		}
+		hasToBlock := cmd.Flags().Changed("to")
+
+		if useSnapshot && snapshotFile != "" {
+			logFatalNoContext(errors.New("--snapshot and --snapshot-file are mutually exclusive"))
+		}
+		if snapshotFile != "" && !hasToBlock {
+			logFatalNoContext(errors.New("--to is required with --snapshot-file"))
+		}
+		if !useSnapshot && !hasToBlock {
+			if toBlock, err = ethcli.BlockNumber(context.Background()); err != nil {
+				logFatalNoContext(err)
+			}
+		}
+
+		var divergence *replay.Divergence
+		switch {
+		case useSnapshot:
+			resp, err := rpc.GetLastSnapshot(rpcClient, address)
+			if err != nil {
+				logFatalNoContext(err)
+			}
+			toBlock = resp.BlockNumber.Uint64()
+			logDebug("Replaying blocks 0 to %d against snapshot %s", toBlock, resp.BlockHash.Hex())
+			divergence, err = replay.VerifyWithSnapshot(ethcli, schemas, newCore, address, toBlock, resp.Storage)
+			if err != nil {
+				logFatalNoContext(err)
+			}
+		case snapshotFile != "":
+			blobZip, err := os.ReadFile(snapshotFile)
+			if err != nil {
+				logFatalNoContext(err)
+			}
+			logDebug("Replaying blocks 0 to %d against snapshot file %s", toBlock, snapshotFile)
+			divergence, err = replay.VerifyWithSnapshot(ethcli, schemas, newCore, address, toBlock, blobZip)
+			if err != nil {
+				logFatalNoContext(err)
+			}
+		default:
+			logDebug("Replaying blocks 0 to %d against onchain storage", toBlock)
+			divergence, err = replay.VerifyWithStorage(ethcli, schemas, newCore, address, toBlock)
+			if err != nil {
+				logFatalNoContext(err)
+			}
+		}
+
+		if divergence == nil {
+			logInfo("No divergence found")
+			return nil
+		}
+		jsonStr, err := json.MarshalIndent(divergence, "", "    ")
+		if err != nil {
+			logFatal(err)
+		}
+		logInfo(string(jsonStr))
+		return fmt.Errorf("%w at block %d", ErrReplayDiverged, divergence.BlockNumber)
+	}
+}
+
+// AddReplayCommand adds a command that replays the actions of a game with the given schemas and core
+// and compares the resulting storage with the onchain storage.
+// Actions are replayed from genesis, as the storage of the core before any other block is not known.
+// The command fails with ErrReplayDiverged if a divergence is found.
+func AddReplayCommand(parent *cobra.Command, schemas arch.ArchSchemas, newCore func() arch.Core) {
+	replayCmd := &cobra.Command{
+		Use:           "replay",
+		Short:         "Replay onchain actions against a Go core and report the first diverging block and slot",
+		RunE:          newRunReplay(schemas, newCore),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	replayCmd.Flags().StringP("address", "a", "", "core contract address")
+	replayCmd.Flags().Uint64("to", 0, "last block to replay (default head)")
+	replayCmd.Flags().Bool("snapshot", false, "compare against the latest snapshot served by the node")
+	replayCmd.Flags().String("snapshot-file", "", "compare against a compressed snapshot blob file")
+	addRpcFlags(replayCmd)
+	parent.AddCommand(replayCmd)
+}
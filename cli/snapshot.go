@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/concrete-eth/archetype/example/engine"
+	"github.com/concrete-eth/archetype/rpc"
 	"github.com/concrete-eth/archetype/snapshot"
 	"github.com/ethereum/go-ethereum/common"
 
@@ -20,7 +20,7 @@ func checkIsHexAddress(addressHex string) {
 }
 
 func methodName(name string) string {
-	return engine.SnapshotNamespace + "_" + name
+	return rpc.SnapshotNamespace + "_" + name
 }
 
 func getSnapshotQuery(cmd *cobra.Command) *snapshot.SnapshotQuery {
@@ -0,0 +1,656 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/concrete-eth/archetype/arch"
+	"github.com/concrete-eth/archetype/params"
+	"github.com/concrete-eth/archetype/rpc"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/iancoleman/orderedmap"
+	"github.com/spf13/cobra"
+)
+
+// parseAbiValue parses a command line argument into the Go type used by the ABI package for typ.
+// Integers can be given in decimal or 0x-prefixed hex, and bytes as 0x-prefixed hex.
+func parseAbiValue(arg string, typ abi.Type) (interface{}, error) {
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		value, ok := new(big.Int).SetString(arg, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value: %s", typ.String(), arg)
+		}
+		var min, max *big.Int
+		if typ.T == abi.UintTy {
+			min = new(big.Int)
+			max = new(big.Int).Sub(new(big.Int).Lsh(common.Big1, uint(typ.Size)), common.Big1)
+		} else {
+			min = new(big.Int).Neg(new(big.Int).Lsh(common.Big1, uint(typ.Size-1)))
+			max = new(big.Int).Sub(new(big.Int).Lsh(common.Big1, uint(typ.Size-1)), common.Big1)
+		}
+		if value.Cmp(min) < 0 || value.Cmp(max) > 0 {
+			return nil, fmt.Errorf("%s value out of range: %s", typ.String(), arg)
+		}
+		goType := typ.GetType()
+		if goType == reflect.TypeOf((*big.Int)(nil)) {
+			return value, nil
+		}
+		goValue := reflect.New(goType).Elem()
+		if typ.T == abi.UintTy {
+			goValue.SetUint(value.Uint64())
+		} else {
+			goValue.SetInt(value.Int64())
+		}
+		return goValue.Interface(), nil
+	case abi.BoolTy:
+		return strconv.ParseBool(arg)
+	case abi.AddressTy:
+		if !common.IsHexAddress(arg) {
+			return nil, fmt.Errorf("invalid address: %s", arg)
+		}
+		return common.HexToAddress(arg), nil
+	case abi.StringTy:
+		return arg, nil
+	case abi.BytesTy:
+		return hexutil.Decode(arg)
+	case abi.FixedBytesTy:
+		data, err := hexutil.Decode(arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) != typ.Size {
+			return nil, fmt.Errorf("invalid %s value: %s", typ.String(), arg)
+		}
+		goValue := reflect.New(typ.GetType()).Elem()
+		reflect.Copy(goValue, reflect.ValueOf(data))
+		return goValue.Interface(), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ.String())
+	}
+}
+
+// jsonValue returns the value of a row field as it should be printed in JSON, i.e., with byte
+// arrays and slices hex-encoded.
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return hexutil.Encode(v)
+	case common.Hash:
+		return v.Hex()
+	case common.Address:
+		return v.Hex()
+	}
+	val := reflect.ValueOf(value)
+	if val.Kind() == reflect.Array && val.Type().Elem().Kind() == reflect.Uint8 {
+		data := make([]byte, val.Len())
+		reflect.Copy(reflect.ValueOf(data), val)
+		return hexutil.Encode(data)
+	}
+	return value
+}
+
+// fieldsToJson returns the given fields of a table row or action keyed by their name in the schema,
+// in schema order.
+func fieldsToJson(fields []datamod.FieldSchema, value interface{}) *orderedmap.OrderedMap {
+	val := reflect.Indirect(reflect.ValueOf(value))
+	obj := orderedmap.New()
+	for _, field := range fields {
+		obj.Set(field.Name, jsonValue(val.FieldByName(field.Title).Interface()))
+	}
+	return obj
+}
+
+// rowToJson returns the fields of a table row keyed by their name in the table schema, in schema order.
+func rowToJson(schema arch.TableSchema, row interface{}) *orderedmap.OrderedMap {
+	return fieldsToJson(schema.Values, row)
+}
+
+// parseTableKeys parses the command line arguments of the keys of a table row.
+func parseTableKeys(schema arch.TableSchema, args []string) ([]interface{}, error) {
+	inputs := schema.Method.Inputs
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("table %s has %d keys, got %d", schema.Name, len(inputs), len(args))
+	}
+	keys := make([]interface{}, len(args))
+	for ii, arg := range args {
+		key, err := parseAbiValue(arg, inputs[ii].Type)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", inputs[ii].Name, err)
+		}
+		keys[ii] = key
+	}
+	return keys, nil
+}
+
+// getTableSchema returns the schema of the table with the given name.
+func getTableSchema(schemas arch.TableSchemas, name string) (arch.TableSchema, error) {
+	tableId, ok := schemas.TableIdFromName(name)
+	if !ok {
+		return arch.TableSchema{}, fmt.Errorf("unknown table %s (tables: %s)", name, strings.Join(schemas.TableNames(), ", "))
+	}
+	return schemas.GetTableSchema(tableId), nil
+}
+
+// newTableGetter creates a table getter pinned to the block given by the --block flag, which takes a
+// block number, a block hash or "latest".
+func newTableGetter(cmd *cobra.Command, schemas arch.TableSchemas) *rpc.TableGetter {
+	address := getCoreAddress(cmd)
+	rpcClient := newRpcClient(cmd)
+	ethcli := ethclient.NewClient(rpcClient)
+	getter := rpc.NewTableReader(ethcli, schemas, address)
+
+	block, err := cmd.Flags().GetString("block")
+	if err != nil {
+		logFatal(err)
+	}
+	switch {
+	case block == "" || block == "latest":
+		return getter
+	case len(block) == 2+2*common.HashLength && strings.HasPrefix(block, "0x"):
+		return getter.AtBlockHash(common.HexToHash(block))
+	default:
+		blockNumber, err := strconv.ParseUint(block, 0, 64)
+		if err != nil {
+			logFatalNoContext(fmt.Errorf("invalid block: %s", block))
+		}
+		return getter.AtBlock(blockNumber)
+	}
+}
+
+// loadAbi loads a contract ABI from a JSON file holding either the ABI or a forge build artifact.
+func loadAbi(path string) (*abi.ABI, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var artifact struct {
+		Abi json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(data, &artifact); err == nil && len(artifact.Abi) > 0 {
+		data = artifact.Abi
+	}
+	ABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return &ABI, nil
+}
+
+// loadTableSchemas returns the table schemas built from the tables ABI and JSON schema given by the
+// --abi and --tables flags, or defaultSchemas if neither is given and defaultSchemas is not empty.
+// Schemas loaded from files use the ABI tuple types as row types and can only be read from the contract.
+func loadTableSchemas(cmd *cobra.Command, defaultSchemas arch.TableSchemas) arch.TableSchemas {
+	abiPath, err := cmd.Flags().GetString("abi")
+	if err != nil {
+		logFatal(err)
+	}
+	tablesPath, err := cmd.Flags().GetString("tables")
+	if err != nil {
+		logFatal(err)
+	}
+	if abiPath == "" && tablesPath == "" {
+		if len(defaultSchemas.TableNames()) == 0 {
+			logFatalNoContext(errors.New("--abi and --tables are required"))
+		}
+		return defaultSchemas
+	}
+	if abiPath == "" || tablesPath == "" {
+		logFatalNoContext(errors.New("--abi and --tables must be given together"))
+	}
+
+	ABI, err := loadAbi(abiPath)
+	if err != nil {
+		logFatalNoContext(fmt.Errorf("could not load ABI: %w", err))
+	}
+	tablesJson, err := os.ReadFile(tablesPath)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	schemas, err := datamod.UnmarshalTableSchemas(tablesJson, false)
+	if err != nil {
+		logFatalNoContext(fmt.Errorf("could not load table schemas: %w", err))
+	}
+	types := make(map[string]reflect.Type, len(schemas))
+	for _, schema := range schemas {
+		method, ok := ABI.Methods[params.SolidityTableMethodName(schema.Name)]
+		if !ok || len(method.Outputs) != 1 {
+			logFatalNoContext(fmt.Errorf("table %s not found in ABI", schema.Name))
+		}
+		types[schema.Name] = method.Outputs[0].Type.GetType()
+	}
+	tableSchemas, err := arch.NewTableSchemas(ABI, schemas, types, nil)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	return tableSchemas
+}
+
+// keySpace returns all values of an integer or boolean key type, or false if the key type cannot be
+// enumerated or has more than max values.
+func keySpace(typ abi.Type, max int) ([]interface{}, bool) {
+	switch typ.T {
+	case abi.BoolTy:
+		return []interface{}{false, true}, max >= 2
+	case abi.IntTy:
+		if typ.Size > 62 || 1<<typ.Size > max {
+			return nil, false
+		}
+		return keyRange(typ, -(1 << (typ.Size - 1)), 1<<(typ.Size-1)-1), true
+	case abi.UintTy:
+		if typ.Size > 62 || 1<<typ.Size > max {
+			return nil, false
+		}
+		return keyRange(typ, 0, 1<<typ.Size-1), true
+	default:
+		return nil, false
+	}
+}
+
+// keyRange returns the values of an integer key type from start to end, inclusive.
+func keyRange(typ abi.Type, start, end int64) []interface{} {
+	values := make([]interface{}, 0, end-start+1)
+	for ii := start; ii <= end; ii++ {
+		value, _ := parseAbiValue(strconv.FormatInt(ii, 10), typ)
+		values = append(values, value)
+	}
+	return values
+}
+
+// parseKeyRange parses the values a key iterates over, given as "*" for all values of an integer or
+// boolean key, "start:end" for an inclusive range of an integer key, or a single value.
+func parseKeyRange(arg string, typ abi.Type, maxRows int) ([]interface{}, error) {
+	if arg == "*" {
+		values, ok := keySpace(typ, maxRows)
+		if !ok {
+			return nil, fmt.Errorf("cannot iterate over all values of %s", typ.String())
+		}
+		return values, nil
+	}
+	if bounds := strings.SplitN(arg, ":", 2); len(bounds) == 2 && (typ.T == abi.IntTy || typ.T == abi.UintTy) {
+		start, err := strconv.ParseInt(bounds[0], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range start: %s", bounds[0])
+		}
+		end, err := strconv.ParseInt(bounds[1], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range end: %s", bounds[1])
+		}
+		if end < start {
+			return nil, fmt.Errorf("invalid range: %s", arg)
+		}
+		if end-start >= int64(maxRows) {
+			return nil, fmt.Errorf("range %s has more than %d values", arg, maxRows)
+		}
+		if _, err := parseAbiValue(bounds[0], typ); err != nil {
+			return nil, err
+		}
+		if _, err := parseAbiValue(bounds[1], typ); err != nil {
+			return nil, err
+		}
+		return keyRange(typ, start, end), nil
+	}
+	value, err := parseAbiValue(arg, typ)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{value}, nil
+}
+
+// keyProduct returns every combination of the given key values, in lexicographic order, or an error
+// if there are more than maxRows.
+func keyProduct(values [][]interface{}, maxRows int) ([][]interface{}, error) {
+	product := [][]interface{}{{}}
+	for _, keyValues := range values {
+		if len(product)*len(keyValues) > maxRows {
+			return nil, fmt.Errorf("more than %d rows to read", maxRows)
+		}
+		next := make([][]interface{}, 0, len(product)*len(keyValues))
+		for _, keys := range product {
+			for _, value := range keyValues {
+				row := make([]interface{}, len(keys), len(keys)+1)
+				copy(row, keys)
+				next = append(next, append(row, value))
+			}
+		}
+		product = next
+	}
+	return product, nil
+}
+
+// tableRow is a row of a table read from the contract.
+type tableRow struct {
+	keys []interface{}
+	row  interface{}
+}
+
+// readRows reads the rows of a table with the given keys.
+// Rows with all values set to zero, i.e., never written, are skipped unless includeEmpty is true.
+func readRows(getter *rpc.TableGetter, schema arch.TableSchema, keys [][]interface{}, includeEmpty bool) ([]tableRow, error) {
+	reads := make([]rpc.TableRead, len(keys))
+	for ii, rowKeys := range keys {
+		reads[ii] = rpc.TableRead{Table: schema.Name, Keys: rowKeys}
+	}
+	rows, err := getter.ReadMany(reads)
+	if err != nil {
+		return nil, err
+	}
+	tableRows := make([]tableRow, 0, len(rows))
+	for ii, row := range rows {
+		if !includeEmpty && reflect.Indirect(reflect.ValueOf(row)).IsZero() {
+			continue
+		}
+		tableRows = append(tableRows, tableRow{keys: keys[ii], row: row})
+	}
+	return tableRows, nil
+}
+
+// columns returns the names of the key and value fields of a table, in schema order.
+func columns(schema arch.TableSchema) []string {
+	names := make([]string, 0, len(schema.Keys)+len(schema.Values))
+	for _, field := range schema.Keys {
+		names = append(names, field.Name)
+	}
+	for _, field := range schema.Values {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+// rowWithKeysToJson returns the keys and fields of a table row keyed by their name in the table
+// schema, in schema order.
+func rowWithKeysToJson(schema arch.TableSchema, row tableRow) *orderedmap.OrderedMap {
+	obj := orderedmap.New()
+	for ii, field := range schema.Keys {
+		obj.Set(field.Name, jsonValue(row.keys[ii]))
+	}
+	values := rowToJson(schema, row.row)
+	for _, name := range values.Keys() {
+		value, _ := values.Get(name)
+		obj.Set(name, value)
+	}
+	return obj
+}
+
+// writeCsv writes the rows of a table as CSV with a header of key and value names.
+func writeCsv(w io.Writer, schema arch.TableSchema, rows []tableRow) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(columns(schema)); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		obj := rowWithKeysToJson(schema, row)
+		record := make([]string, 0, len(obj.Keys()))
+		for _, name := range obj.Keys() {
+			value, _ := obj.Get(name)
+			record = append(record, fmt.Sprint(value))
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func printJson(value interface{}) {
+	jsonStr, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		logFatal(err)
+	}
+	logInfo(string(jsonStr))
+}
+
+func getMaxRows(cmd *cobra.Command) int {
+	maxRows, err := cmd.Flags().GetInt("max-rows")
+	if err != nil {
+		logFatal(err)
+	}
+	return maxRows
+}
+
+func newRunTableList(defaultSchemas arch.TableSchemas) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		schemas := loadTableSchemas(cmd, defaultSchemas)
+		tables := orderedmap.New()
+		for _, name := range schemas.TableNames() {
+			schema, _ := getTableSchema(schemas, name)
+			keys := orderedmap.New()
+			for _, field := range schema.Keys {
+				keys.Set(field.Name, field.Type.Name)
+			}
+			values := orderedmap.New()
+			for _, field := range schema.Values {
+				values.Set(field.Name, field.Type.Name)
+			}
+			table := orderedmap.New()
+			table.Set("keySchema", keys)
+			table.Set("schema", values)
+			tables.Set(name, table)
+		}
+		printJson(tables)
+	}
+}
+
+func newRunTableRead(defaultSchemas arch.TableSchemas) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		schemas := loadTableSchemas(cmd, defaultSchemas)
+		schema, err := getTableSchema(schemas, args[0])
+		if err != nil {
+			logFatalNoContext(err)
+		}
+		keys, err := parseTableKeys(schema, args[1:])
+		if err != nil {
+			logFatalNoContext(err)
+		}
+
+		getter := newTableGetter(cmd, schemas)
+		row, err := getter.Read(schema.Name, keys...)
+		if err != nil {
+			logFatalNoContext(err)
+		}
+		printJson(rowToJson(schema, row))
+	}
+}
+
+func newRunTableIter(defaultSchemas arch.TableSchemas) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		schemas := loadTableSchemas(cmd, defaultSchemas)
+		schema, err := getTableSchema(schemas, args[0])
+		if err != nil {
+			logFatalNoContext(err)
+		}
+		includeEmpty, err := cmd.Flags().GetBool("empty")
+		if err != nil {
+			logFatal(err)
+		}
+		maxRows := getMaxRows(cmd)
+
+		inputs := schema.Method.Inputs
+		if len(args)-1 != len(inputs) {
+			logFatalNoContext(fmt.Errorf("table %s has %d keys, got %d ranges", schema.Name, len(inputs), len(args)-1))
+		}
+		values := make([][]interface{}, len(inputs))
+		for ii, arg := range args[1:] {
+			if values[ii], err = parseKeyRange(arg, inputs[ii].Type, maxRows); err != nil {
+				logFatalNoContext(fmt.Errorf("key %s: %w", inputs[ii].Name, err))
+			}
+		}
+		keys, err := keyProduct(values, maxRows)
+		if err != nil {
+			logFatalNoContext(err)
+		}
+
+		getter := newTableGetter(cmd, schemas)
+		rows, err := readRows(getter, schema, keys, includeEmpty)
+		if err != nil {
+			logFatalNoContext(err)
+		}
+		objs := make([]*orderedmap.OrderedMap, len(rows))
+		for ii, row := range rows {
+			objs[ii] = rowWithKeysToJson(schema, row)
+		}
+		printJson(objs)
+	}
+}
+
+func newRunTableDump(defaultSchemas arch.TableSchemas) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		schemas := loadTableSchemas(cmd, defaultSchemas)
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			logFatal(err)
+		}
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			logFatal(err)
+		}
+		includeEmpty, err := cmd.Flags().GetBool("empty")
+		if err != nil {
+			logFatal(err)
+		}
+		maxRows := getMaxRows(cmd)
+		if format != "json" && format != "csv" {
+			logFatalNoContext(fmt.Errorf("unsupported format: %s", format))
+		}
+
+		tableNames := args
+		if len(tableNames) == 0 {
+			tableNames = schemas.TableNames()
+		}
+		if format == "csv" && len(tableNames) > 1 && out == "" {
+			logFatalNoContext(errors.New("--out directory is required to dump multiple tables as CSV"))
+		}
+
+		getter := newTableGetter(cmd, schemas)
+		dump := orderedmap.New()
+		for _, name := range tableNames {
+			schema, err := getTableSchema(schemas, name)
+			if err != nil {
+				logFatalNoContext(err)
+			}
+			// Enumerate every key combination of tables with small integer or boolean keys
+			values := make([][]interface{}, len(schema.Method.Inputs))
+			enumerable := true
+			for ii, input := range schema.Method.Inputs {
+				if values[ii], enumerable = keySpace(input.Type, maxRows); !enumerable {
+					break
+				}
+			}
+			keys, err := keyProduct(values, maxRows)
+			if !enumerable || err != nil {
+				logWarning(fmt.Sprintf("skipping table %s: keys cannot be enumerated, use table iter with key ranges", name))
+				continue
+			}
+			rows, err := readRows(getter, schema, keys, includeEmpty || len(schema.Keys) == 0)
+			if err != nil {
+				logFatalNoContext(err)
+			}
+
+			switch format {
+			case "json":
+				objs := make([]*orderedmap.OrderedMap, len(rows))
+				for ii, row := range rows {
+					objs[ii] = rowWithKeysToJson(schema, row)
+				}
+				dump.Set(name, objs)
+			case "csv":
+				var w io.Writer = os.Stdout
+				if out != "" {
+					path := out
+					if len(tableNames) > 1 {
+						if err := os.MkdirAll(out, 0755); err != nil {
+							logFatalNoContext(err)
+						}
+						path = filepath.Join(out, name+".csv")
+					}
+					file, err := os.Create(path)
+					if err != nil {
+						logFatalNoContext(err)
+					}
+					defer file.Close()
+					w = file
+				}
+				if err := writeCsv(w, schema, rows); err != nil {
+					logFatalNoContext(err)
+				}
+			}
+		}
+
+		if format == "json" {
+			jsonStr, err := json.MarshalIndent(dump, "", "    ")
+			if err != nil {
+				logFatal(err)
+			}
+			if out == "" {
+				logInfo(string(jsonStr))
+			} else if err := os.WriteFile(out, jsonStr, 0644); err != nil {
+				logFatalNoContext(err)
+			}
+		}
+	}
+}
+
+// AddTableCommand adds commands that list, read, iterate and dump the tables of a game from the game
+// contract. Tables are described by the given schemas unless the --abi and --tables flags are set.
+func AddTableCommand(parent *cobra.Command, schemas arch.TableSchemas) {
+	tableCmd := &cobra.Command{Use: "table", Short: "Inspect game tables"}
+	tableCmd.PersistentFlags().StringP("address", "a", "", "core proxy contract address")
+	tableCmd.PersistentFlags().StringP("manifest", "m", "", "deployment manifest to read the address from if --address is not set")
+	tableCmd.PersistentFlags().StringP("block", "b", "latest", "block number or hash to read the tables at")
+	tableCmd.PersistentFlags().String("abi", "", "tables ABI or forge artifact JSON file")
+	tableCmd.PersistentFlags().String("tables", "", "tables JSON schema file")
+	tableCmd.PersistentFlags().Int("max-rows", 1<<16, "maximum number of rows to read from a table")
+	addRpcFlags(tableCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the tables and their schemas",
+		Args:  cobra.NoArgs,
+		Run:   newRunTableList(schemas),
+	}
+	readCmd := &cobra.Command{
+		Use:   "read <table> [keys...]",
+		Short: "Read a table row and print it as JSON",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   newRunTableRead(schemas),
+	}
+	iterCmd := &cobra.Command{
+		Use:   "iter <table> [key ranges...]",
+		Short: "Read the rows of a table in the given key ranges and print them as JSON",
+		Long: `Read the rows of a table in the given key ranges and print them as JSON.
+Every key is given as a single value, an inclusive range of integers "start:end", or "*" for all
+values of a small integer or boolean key. Rows never written are skipped unless --empty is set.`,
+		Args: cobra.MinimumNArgs(1),
+		Run:  newRunTableIter(schemas),
+	}
+	iterCmd.Flags().Bool("empty", false, "include rows never written")
+	dumpCmd := &cobra.Command{
+		Use:   "dump [tables...]",
+		Short: "Dump all reachable table rows as JSON or CSV",
+		Long: `Dump all reachable table rows as JSON or CSV.
+Rows of tables with no keys, and of tables with small integer or boolean keys are dumped; other tables
+are skipped. Rows never written are skipped unless --empty is set.`,
+		Run: newRunTableDump(schemas),
+	}
+	dumpCmd.Flags().StringP("format", "f", "json", "output format (json or csv)")
+	dumpCmd.Flags().StringP("out", "o", "", "output file, or directory when dumping multiple tables as CSV")
+	dumpCmd.Flags().Bool("empty", false, "include rows never written")
+
+	tableCmd.AddCommand(listCmd, readCmd, iterCmd, dumpCmd)
+	parent.AddCommand(tableCmd)
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/concrete-eth/archetype/codegen"
+	"github.com/concrete-eth/archetype/deploy"
+	"github.com/concrete-eth/archetype/migrate"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+func runUpgrade(cmd *cobra.Command, args []string) {
+	logicHex, err := cmd.Flags().GetString("logic")
+	if err != nil {
+		logFatal(err)
+	}
+	initDataHex, err := cmd.Flags().GetString("init-data")
+	if err != nil {
+		logFatal(err)
+	}
+	migrationId, err := cmd.Flags().GetString("migration")
+	if err != nil {
+		logFatal(err)
+	}
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		logFatal(err)
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		logFatal(err)
+	}
+
+	checkIsHexAddress(logicHex)
+	logic := common.HexToAddress(logicHex)
+	var initData []byte
+	if initDataHex != "" {
+		if initData, err = hexutil.Decode(initDataHex); err != nil {
+			logFatalNoContext(fmt.Errorf("invalid init data: %w", err))
+		}
+	}
+	if migrationId != "" {
+		if initData != nil {
+			logFatalNoContext(errors.New("--migration cannot be set with --init-data"))
+		}
+		initData = migrate.Calldata(migrationId)
+	}
+
+	// Check schema compatibility before upgrading
+	oldTablesPath, err := cmd.Flags().GetString("old-tables")
+	if err != nil {
+		logFatal(err)
+	}
+	oldActionsPath, err := cmd.Flags().GetString("old-actions")
+	if err != nil {
+		logFatal(err)
+	}
+	if oldTablesPath == "" || oldActionsPath == "" {
+		if !force {
+			logFatalNoContext(errors.New("--old-tables and --old-actions are required to check the schema changes, use --force to upgrade without checking them"))
+		}
+		logWarning("upgrading without checking every schema change")
+	}
+	tablesCompat, err := checkSchemaFiles(cmd, "Tables", "old-tables", "tables", codegen.CheckTableSchemas)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	actionsCompat, err := checkSchemaFiles(cmd, "Actions", "old-actions", "actions", codegen.CheckActionSchemas)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	compat := max(tablesCompat, actionsCompat)
+	if compat == codegen.Breaking || (compat == codegen.NeedsMigration && migrationId == "") {
+		if !force {
+			logFatalNoContext(errors.New("schema changes are not backwards compatible, use --migration to migrate the storage or --force to upgrade anyway"))
+		}
+		logWarning("upgrading with schema changes that are not backwards compatible")
+	}
+
+	gameAddr := getGameAddress(cmd)
+	ethcli := ethclient.NewClient(newRpcClient(cmd))
+	proxyAddr, logicAddr := getProxyAndLogic(ethcli, gameAddr)
+	logDebug("Upgrading proxy %s from logic %s to %s", proxyAddr.Hex(), logicAddr.Hex(), logic.Hex())
+	if dryRun {
+		return
+	}
+
+	privateKey, err := loadPrivateKey(cmd)
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	if privateKey == nil {
+		logFatalNoContext(errors.New("--keystore or --private-key-file is required to upgrade"))
+	}
+	chainId, err := ethcli.ChainID(context.Background())
+	if err != nil {
+		logFatalNoContext(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+	if err != nil {
+		logFatal(err)
+	}
+	if err := deploy.UpgradeGame(auth, ethcli, gameAddr, logic, initData, false); err != nil {
+		logFatalNoContext(err)
+	}
+
+	// Update the logic address in the deployment manifest, if any
+	manifestPath, err := cmd.Flags().GetString("manifest")
+	if err != nil {
+		logFatal(err)
+	}
+	if manifestPath != "" {
+		manifest, err := deploy.ReadManifest(manifestPath)
+		if err != nil {
+			logFatalNoContext(err)
+		}
+		if manifest.Game == gameAddr {
+			manifest.Logic = logic
+			if err := deploy.WriteManifest(manifestPath, manifest); err != nil {
+				logFatalNoContext(err)
+			}
+		}
+	}
+	logTaskSuccess("Upgrade", logic.Hex())
+}
+
+// AddUpgradeCommand adds a command that points the core proxy of a game contract to a new logic address.
+func AddUpgradeCommand(parent *cobra.Command) {
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the core logic of a game contract",
+		Long: `Upgrade the core logic of a game contract, e.g., to a new core precompile.
+The changes between the old and new table and action schemas are printed first and the upgrade is
+aborted if any of them is breaking, or needs a storage migration and --migration is not set, unless
+--force is set. The upgrade is also aborted if the old schemas are not given, unless --force is set.
+Storage migrations registered in the new core precompile are run once with --migration.`,
+		Args: cobra.NoArgs,
+		Run:  runUpgrade,
+	}
+	addAddressFlags(upgradeCmd, "game contract address")
+	upgradeCmd.Flags().String("logic", "", "new core logic address, e.g., the new core precompile address")
+	upgradeCmd.Flags().String("init-data", "", "hex-encoded data to call the new logic with")
+	upgradeCmd.Flags().String("migration", "", "id of the storage migration to run after upgrading")
+	upgradeCmd.Flags().String("old-tables", "", "table schema file of the current logic")
+	upgradeCmd.Flags().StringP("tables", "t", "", "table schema file of the new logic")
+	upgradeCmd.Flags().String("old-actions", "", "action schema file of the current logic")
+	upgradeCmd.Flags().String("actions", "", "action schema file of the new logic")
+	upgradeCmd.Flags().Bool("force", false, "upgrade even if the schema changes are not backwards compatible")
+	upgradeCmd.Flags().Bool("dry-run", false, "check the schema changes without upgrading")
+	upgradeCmd.MarkFlagRequired("logic")
+	addKeyFlags(upgradeCmd)
+	addRpcFlags(upgradeCmd)
+
+	parent.AddCommand(upgradeCmd)
+}
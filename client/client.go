@@ -1,11 +1,16 @@
 package client
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/concrete-eth/archetype/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/concrete/lib"
 	"github.com/ethereum/go-ethereum/log"
 
@@ -18,13 +23,86 @@ var (
 	ErrTickNotFirst           = errors.New("tick not first action")
 	ErrChannelClosed          = errors.New("channel closed")
 	ErrChannelBlockedOrClosed = errors.New("channel blocked or closed")
+	ErrReorgTooDeep           = errors.New("reorg too deep")
+	ErrCheckpointOrphaned     = errors.New("checkpoint block orphaned")
+	ErrChangeFeedUnsupported  = errors.New("core does not support the change feed")
 )
 
+var (
+	MaxRollbackDepth uint64 = 64 // Maximum number of blocks that can be unwound on a chain reorganization
+)
+
+// blockJournal holds the changes to the key-value store made by applying the action batch of a block.
+type blockJournal struct {
+	blockNumber uint64
+	journal     *kvstore.Journal
+}
+
+// ActionValidator checks an action before it is simulated and sent.
+// The core reflects the state the action would be executed on and must not be modified.
+type ActionValidator interface {
+	ValidateAction(action arch.Action, core arch.Core) error
+}
+
+// ActionValidatorFunc is an adapter to allow the use of ordinary functions as action validators.
+type ActionValidatorFunc func(action arch.Action, core arch.Core) error
+
+// ValidateAction calls f(action, core).
+func (f ActionValidatorFunc) ValidateAction(action arch.Action, core arch.Core) error {
+	return f(action, core)
+}
+
+// RejectedAction is an action that was not sent because it failed validation or simulation.
+type RejectedAction struct {
+	Index  int         // Index of the action in the slice passed to SendActionsWithResult
+	Action arch.Action // The rejected action
+	Err    error       // Reason for the rejection
+}
+
+// Error implements the error interface.
+func (r *RejectedAction) Error() string {
+	return fmt.Sprintf("action %d (%T) rejected: %v", r.Index, r.Action, r.Err)
+}
+
+// Unwrap returns the reason for the rejection.
+func (r *RejectedAction) Unwrap() error {
+	return r.Err
+}
+
+// SendResult reports which actions passed to SendActionsWithResult were sent and which were rejected.
+type SendResult struct {
+	Sent     []arch.Action
+	Rejected []*RejectedAction
+}
+
+// OK returns whether all actions were sent.
+func (r SendResult) OK() bool {
+	return len(r.Rejected) == 0
+}
+
+// EmittedEvent is a custom event emitted by the core while applying a confirmed action batch.
+type EmittedEvent struct {
+	BlockNumber uint64     // Block the action batch belongs to
+	ActionIndex int        // Index of the action that emitted the event in the batch
+	Event       arch.Event // The emitted event
+}
+
+// RowChange is a table row written while syncing.
+type RowChange struct {
+	TableId arch.ValidTableId
+	Table   string        // Name of the table
+	Keys    []interface{} // Row keys, in the order of the table key schema
+}
+
 type Client struct {
 	schemas arch.ArchSchemas
 
-	core arch.Core
-	kv   *kvstore.StagedKeyValueStore
+	core       arch.Core
+	kv         *kvstore.StagedKeyValueStore
+	checkpoint kvstore.Checkpointer // Nil if the underlying key-value store does not record checkpoints
+
+	resumedBlockNumber uint64      // Block of the checkpoint the client resumed from
+	resumedBlockHash   common.Hash // Hash of the checkpoint block, zero if the client did not resume
 
 	actionBatchInChan <-chan arch.ActionBatch
 	actionOutChan     chan<- []arch.Action
@@ -34,6 +112,23 @@ type Client struct {
 	lastNewBatchTime  time.Time
 	ticksRunThisBlock uint64
 
+	journals []blockJournal
+
+	validators map[reflect.Type]ActionValidator
+
+	events       *arch.EventBuffer
+	eventIndices []int // Index in the batch of the action that emitted each buffered event
+	eventChan    chan<- EmittedEvent
+
+	rowIndex     *arch.RowIndex              // Nil if the change feed is disabled
+	changedKeys  map[common.Hash]struct{}    // Keys committed or reverted during the current sync
+	stagedValues map[common.Hash]common.Hash // Staged values at the start of the current sync
+	changes      []RowChange
+
+	localCtx arch.ExecutionContext // Context of simulated actions and anticipated ticks
+	owner    common.Address        // Owner of the game
+	account  common.Address        // Account actions are sent from, checked against the permission of restricted actions
+
 	lock sync.Mutex
 
 	now func() time.Time
@@ -42,24 +137,60 @@ type Client struct {
 }
 
 // New create a new client object.
+// If kv implements kvstore.Checkpointer and holds a checkpoint past blockNumber, the client resumes
+// from the block following the checkpoint and records a new checkpoint after every applied batch with
+// a known block hash.
+// If the batch of the checkpoint block is received again, its hash is checked against the checkpoint
+// and syncing fails with ErrCheckpointOrphaned on mismatch, as the block was orphaned while the client
+// was not running and its changes cannot be unwound.
 func New(schemas arch.ArchSchemas, core arch.Core, kv lib.KeyValueStore, actionBatchChan <-chan arch.ActionBatch, actionChan chan<- []arch.Action, blockTime time.Duration, blockNumber uint64) *Client {
+	checkpoint, _ := kv.(kvstore.Checkpointer)
+	var resumedBlockHash common.Hash
+	if resumeBN := ResumeBlockNumber(kv, blockNumber); resumeBN != blockNumber {
+		_, resumedBlockHash, _ = checkpoint.Checkpoint()
+		blockNumber = resumeBN
+	}
 	stagedKv := kvstore.NewStagedKeyValueStore(kv)
 	core.SetKV(stagedKv)
 	core.SetBlockNumber(blockNumber)
+	events := arch.NewEventBuffer()
+	if c, ok := core.(arch.ISetEventEmitter); ok {
+		c.SetEventEmitter(events)
+	}
 	return &Client{
-		schemas:           schemas,
-		core:              core,
-		kv:                stagedKv,
-		actionBatchInChan: actionBatchChan,
-		actionOutChan:     actionChan,
-		blockTime:         blockTime,
-		ticksRunThisBlock: 0,
-		now:               time.Now,
+		schemas:            schemas,
+		core:               core,
+		kv:                 stagedKv,
+		checkpoint:         checkpoint,
+		resumedBlockNumber: blockNumber - 1,
+		resumedBlockHash:   resumedBlockHash,
+		actionBatchInChan:  actionBatchChan,
+		actionOutChan:      actionChan,
+		blockTime:          blockTime,
+		ticksRunThisBlock:  0,
+		journals:           make([]blockJournal, 0, MaxRollbackDepth),
+		validators:         make(map[reflect.Type]ActionValidator),
+		events:             events,
+		eventIndices:       make([]int, 0),
+		now:                time.Now,
 
 		_tickTime: blockTime / time.Duration(core.TicksPerBlock()),
 	}
 }
 
+// ResumeBlockNumber returns the block following the checkpoint recorded in kv, or blockNumber if kv
+// does not hold a later checkpoint.
+func ResumeBlockNumber(kv lib.KeyValueStore, blockNumber uint64) uint64 {
+	checkpoint, ok := kv.(kvstore.Checkpointer)
+	if !ok {
+		return blockNumber
+	}
+	if checkpointBN, _, ok := checkpoint.Checkpoint(); ok && checkpointBN+1 > blockNumber {
+		return checkpointBN + 1
+	}
+	return blockNumber
+}
+
 // Core returns the core.
 func (c *Client) Core() arch.Core {
 	return c.core
@@ -97,9 +228,24 @@ func (c *Client) applyBatch(batch arch.ActionBatch) (bool, error) {
 		return false, ErrBlockNumberMismatch
 	}
 	tickActionInBatch := false
+	// Discard events emitted by simulations and tick anticipation
+	c.events.Reset()
+	c.eventIndices = c.eventIndices[:0]
+	setCtx, hasCtx := c.core.(arch.ISetExecutionContext)
+	if hasCtx {
+		defer setCtx.SetExecutionContext(c.localCtx)
+	}
 	for ii, action := range batch.Actions {
+		if hasCtx {
+			setCtx.SetExecutionContext(batch.Context(ii))
+		}
 		if err := c.schemas.Actions.ExecuteAction(action, c.core); err != nil {
 			c.error("failed to execute action", "err", err)
+			// Discard the events emitted by the failed action
+			c.events.Truncate(len(c.eventIndices))
+		}
+		for len(c.eventIndices) < c.events.Len() {
+			c.eventIndices = append(c.eventIndices, ii)
 		}
 		if _, ok := action.(*arch.CanonicalTickAction); ok {
 			if ii != 0 {
@@ -111,19 +257,244 @@ func (c *Client) applyBatch(batch arch.ActionBatch) (bool, error) {
 	return tickActionInBatch, nil
 }
 
+// rollback unwinds the changes made by the last depth applied action batches and rewinds the core block number.
+// Any staged changes are discarded.
+func (c *Client) rollback(depth uint64) error {
+	if depth > uint64(len(c.journals)) {
+		return ErrReorgTooDeep
+	}
+	c.kv.Revert()
+	for ii := uint64(0); ii < depth; ii++ {
+		last := c.journals[len(c.journals)-1]
+		c.journals = c.journals[:len(c.journals)-1]
+		c.recordChanges(last.journal.Keys())
+		last.journal.Revert(c.kv)
+		c.kv.Commit()
+		c.core.SetBlockNumber(last.blockNumber)
+	}
+	return nil
+}
+
+// pushJournal stores the journal of the last applied action batch, discarding the oldest journal
+// if more than MaxRollbackDepth are held.
+func (c *Client) pushJournal(blockNumber uint64, journal *kvstore.Journal) {
+	if MaxRollbackDepth == 0 {
+		return
+	}
+	if uint64(len(c.journals)) >= MaxRollbackDepth {
+		c.journals = append(c.journals[:0], c.journals[1:]...)
+	}
+	c.journals = append(c.journals, blockJournal{blockNumber: blockNumber, journal: journal})
+}
+
+// verifyResumedBatch returns ErrCheckpointOrphaned if the given already applied batch is the batch of the
+// checkpoint block the client resumed from, and its hash does not match the checkpoint.
+func (c *Client) verifyResumedBatch(batch arch.ActionBatch) error {
+	if c.resumedBlockHash == (common.Hash{}) || batch.BlockNumber != c.resumedBlockNumber || batch.BlockHash == (common.Hash{}) {
+		return nil
+	}
+	if batch.BlockHash != c.resumedBlockHash {
+		return fmt.Errorf("%w: block %d has hash %s, expected %s", ErrCheckpointOrphaned, batch.BlockNumber, batch.BlockHash.Hex(), c.resumedBlockHash.Hex())
+	}
+	return nil
+}
+
 // applyBatchAndCommit applies the given action batch to the core, commits the changes to the key-value store,
 // and updates the core block number.
+// If the batch follows a chain reorganization, the orphaned batches are unwound first.
+// Batches for blocks that were already applied, e.g. before resuming from a checkpoint, are ignored.
 func (c *Client) applyBatchAndCommit(batch arch.ActionBatch) (bool, error) {
+	if batch.IsReorg() {
+		if err := c.rollback(batch.ReorgDepth); err != nil {
+			return false, err
+		}
+	} else if batch.BlockNumber < c.core.BlockNumber() {
+		return false, c.verifyResumedBatch(batch)
+	}
 	tickActionInBatch, err := c.applyBatch(batch)
 	if err != nil {
 		return false, err
 	}
-	c.kv.Commit()
+	journal := c.kv.CommitWithJournal()
+	c.recordChanges(journal.Keys())
+	c.pushJournal(batch.BlockNumber, journal)
+	if c.checkpoint != nil && batch.BlockHash != (common.Hash{}) {
+		// Batches with unknown hashes are persisted with the next checkpoint
+		if err := c.checkpoint.SetCheckpoint(batch.BlockNumber, batch.BlockHash); err != nil {
+			return false, err
+		}
+	}
 	c.lastNewBatchTime = c.now()
 	c.core.SetBlockNumber(batch.BlockNumber + 1)
+	c.sendEvents(batch.BlockNumber)
 	return tickActionInBatch, nil
 }
 
+// sendEvents sends the events emitted while applying the last action batch to the event channel, if any.
+func (c *Client) sendEvents(blockNumber uint64) {
+	defer c.events.Reset()
+	if c.eventChan == nil {
+		return
+	}
+	for ii, event := range c.events.Events() {
+		select {
+		case c.eventChan <- EmittedEvent{BlockNumber: blockNumber, ActionIndex: c.eventIndices[ii], Event: event}:
+		default:
+			c.error("failed to send event", "err", ErrChannelBlockedOrClosed, "block", blockNumber)
+		}
+	}
+}
+
+// SetEventChannel sets the channel the custom events emitted by the core while applying confirmed
+// action batches are sent to, in order. Events emitted while simulating actions or anticipating ticks
+// are not sent, and events of batches orphaned by a chain reorganization are not retracted.
+// The channel should be buffered and drained regularly; events that cannot be sent without blocking
+// are dropped. The core must implement arch.ISetEventEmitter, e.g. by embedding arch.BaseCore.
+func (c *Client) SetEventChannel(eventChan chan<- EmittedEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.eventChan = eventChan
+}
+
+// EnableChangeFeed enables the table change feed: after every call to Sync, SyncUntil or
+// InterpolatedSync, Changes returns the table rows written during the call, so renderers can update
+// only the entities that changed.
+// The core must implement arch.ISetDatastoreWrapper, e.g. by embedding arch.BaseCore, and the feed
+// should be enabled before syncing, as rows written before are only reported once written again.
+// The feed keeps an entry for every storage slot of every keyed table row accessed since it was
+// enabled, so its memory grows with the number of distinct rows the game has ever touched.
+func (c *Client) EnableChangeFeed() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.rowIndex != nil {
+		return nil
+	}
+	core, ok := c.core.(arch.ISetDatastoreWrapper)
+	if !ok {
+		return ErrChangeFeedUnsupported
+	}
+	rowIndex, err := arch.NewRowIndex(c.schemas.Tables)
+	if err != nil {
+		return err
+	}
+	core.SetDatastoreWrapper(rowIndex.Wrap)
+	c.rowIndex = rowIndex
+	return nil
+}
+
+// Changes returns the table rows written during the last call to Sync, SyncUntil or InterpolatedSync,
+// including rows reverted by a chain reorganization or by discarding anticipated ticks, sorted by
+// table name. Returns nil if the change feed is not enabled.
+func (c *Client) Changes() []RowChange {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.changes
+}
+
+// beginChanges starts recording the keys written until endChanges is called.
+func (c *Client) beginChanges() {
+	if c.rowIndex == nil {
+		return
+	}
+	c.changedKeys = make(map[common.Hash]struct{})
+	c.stagedValues = make(map[common.Hash]common.Hash)
+	c.kv.ForEachStaged(func(key, value common.Hash) bool {
+		c.stagedValues[key] = value
+		return true
+	})
+}
+
+// recordChanges records keys committed or reverted since beginChanges was called.
+func (c *Client) recordChanges(keys []common.Hash) {
+	if c.changedKeys == nil {
+		return
+	}
+	for _, key := range keys {
+		c.changedKeys[key] = struct{}{}
+	}
+}
+
+// endChanges maps the keys written since beginChanges was called to the table rows they belong to.
+func (c *Client) endChanges() {
+	if c.rowIndex == nil {
+		return
+	}
+	// Staged keys written, e.g. by anticipated ticks, or discarded since the start of the sync
+	c.kv.ForEachStaged(func(key, value common.Hash) bool {
+		if prev, ok := c.stagedValues[key]; !ok || prev != value {
+			c.changedKeys[key] = struct{}{}
+		}
+		delete(c.stagedValues, key)
+		return true
+	})
+	for key := range c.stagedValues {
+		c.changedKeys[key] = struct{}{}
+	}
+
+	type change struct {
+		RowChange
+		rowSlot common.Hash
+	}
+	changes := make([]change, 0)
+	seen := make(map[common.Hash]struct{})
+	for key := range c.changedKeys {
+		row, rowSlot, ok := c.rowIndex.Lookup(key)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[rowSlot]; ok {
+			continue
+		}
+		seen[rowSlot] = struct{}{}
+		schema := c.schemas.Tables.GetTableSchema(row.TableId)
+		changes = append(changes, change{
+			RowChange: RowChange{TableId: row.TableId, Table: schema.Name, Keys: row.Keys},
+			rowSlot:   rowSlot,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Table != changes[j].Table {
+			return changes[i].Table < changes[j].Table
+		}
+		return bytes.Compare(changes[i].rowSlot.Bytes(), changes[j].rowSlot.Bytes()) < 0
+	})
+	c.changes = make([]RowChange, len(changes))
+	for ii, change := range changes {
+		c.changes[ii] = change.RowChange
+	}
+	c.changedKeys = nil
+	c.stagedValues = nil
+}
+
+// SetExecutionContext sets the execution context of the actions simulated by SendActions and Simulate
+// and of the ticks anticipated by InterpolatedSync, e.g., with the origin set to the address actions are
+// sent from. Actions applied from action batches run with the context of the batch.
+func (c *Client) SetExecutionContext(ctx arch.ExecutionContext) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.localCtx = ctx
+	if setCtx, ok := c.core.(arch.ISetExecutionContext); ok {
+		setCtx.SetExecutionContext(ctx)
+	}
+}
+
+// SetOwner sets the owner of the game. SendActions rejects actions restricted to the owner unless the
+// account set by SetAccount is the owner.
+func (c *Client) SetOwner(owner common.Address) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.owner = owner
+}
+
+// SetAccount sets the account actions are sent to the game contract from, i.e., the caller the
+// permission modifiers of the game contract check. SendActions rejects actions the account is not
+// authorized to execute.
+func (c *Client) SetAccount(account common.Address) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.account = account
+}
+
 // Simulate runs the given function and then reverts all the changes to the key-value store.
 func (c *Client) Simulate(f func(core arch.Core)) {
 	// Put another stage on top of the current key-value store that will never be committed
@@ -136,35 +507,99 @@ func (c *Client) Simulate(f func(core arch.Core)) {
 	c.core.SetKV(c.kv)
 }
 
+// SetActionValidator sets the validator run on actions of the same type as the given action before
+// they are simulated in SendActions, e.g. SetActionValidator(&archmod.ActionData_Move{}, validator).
+// A nil validator removes the validator for the action type.
+func (c *Client) SetActionValidator(action arch.Action, validator ActionValidator) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	actionType := reflect.TypeOf(action)
+	if validator == nil {
+		delete(c.validators, actionType)
+	} else {
+		c.validators[actionType] = validator
+	}
+}
+
 // SendAction is a shorthand for sending a single action to the client.
-// See SendActions for more details.
+// If the action is rejected, the returned error is a *RejectedAction holding the reason.
+// See SendActionsWithResult for more details.
 func (c *Client) SendAction(action arch.Action) error {
-	return c.SendActions([]arch.Action{action})
+	result, err := c.SendActionsWithResult([]arch.Action{action})
+	if err != nil {
+		return err
+	}
+	if !result.OK() {
+		return result.Rejected[0]
+	}
+	return nil
 }
 
 // SendActions sends a slice of actions to actionOutChan.
+// Rejected actions are logged and skipped. See SendActionsWithResult for more details.
 func (c *Client) SendActions(actions []arch.Action) error {
-	actionsToSend := make([]arch.Action, 0)
+	result, err := c.SendActionsWithResult(actions)
+	for _, r := range result.Rejected {
+		c.error("failed to execute action", "index", r.Index, "err", r.Err)
+	}
+	return err
+}
+
+// SendActionsWithResult validates and simulates a slice of actions in order and sends the ones that
+// succeed to actionOutChan. Each action is simulated on top of the changes made by the previously
+// accepted ones. The result lists the sent actions and the rejected ones with the reason of the rejection.
+// If all actions are rejected, nothing is sent.
+func (c *Client) SendActionsWithResult(actions []arch.Action) (SendResult, error) {
+	result := SendResult{
+		Sent:     make([]arch.Action, 0, len(actions)),
+		Rejected: make([]*RejectedAction, 0),
+	}
 	c.Simulate(func(core arch.Core) {
-		for _, action := range actions {
-			if err := c.schemas.Actions.ExecuteAction(action, core); err != nil {
-				c.error("failed to execute action", "err", err)
+		for ii, action := range actions {
+			if err := c.validateAndExecute(action, core); err != nil {
+				result.Rejected = append(result.Rejected, &RejectedAction{Index: ii, Action: action, Err: err})
 				continue
 			}
-			actionsToSend = append(actionsToSend, action)
+			result.Sent = append(result.Sent, action)
 		}
 	})
 
-	if len(actionsToSend) == 0 {
-		return nil
+	if len(result.Sent) == 0 {
+		return result, nil
 	}
 
 	select {
-	case c.actionOutChan <- actionsToSend:
-		return nil
+	case c.actionOutChan <- result.Sent:
+		return result, nil
 	default:
-		return ErrChannelBlockedOrClosed
+		return result, ErrChannelBlockedOrClosed
+	}
+}
+
+// validateAndExecute checks the account actions are sent from can execute the action, runs the
+// validator registered for the action type, if any, and executes the action on the given core,
+// discarding the changes made by the action if any fails.
+// Must be called from within Simulate.
+func (c *Client) validateAndExecute(action arch.Action, core arch.Core) error {
+	if err := c.schemas.Actions.CheckPermission(action, core.KV(), c.account, func() common.Address {
+		return c.owner
+	}); err != nil {
+		return err
+	}
+	if validator, ok := c.validators[reflect.TypeOf(action)]; ok {
+		if err := validator.ValidateAction(action, core); err != nil {
+			return err
+		}
+	}
+	kv := core.KV()
+	actionKv := kvstore.NewStagedKeyValueStore(kv)
+	core.SetKV(actionKv)
+	defer core.SetKV(kv)
+	if err := c.schemas.Actions.ExecuteAction(action, core); err != nil {
+		return err
 	}
+	actionKv.Commit()
+	return nil
 }
 
 // Sync apply all buffered action batches and commit the changes to the key-value store.
@@ -173,6 +608,8 @@ func (c *Client) SendActions(actions []arch.Action) error {
 func (c *Client) Sync() (didReceiveNewBatch bool, didTick bool, err error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	c.beginChanges()
+	defer c.endChanges()
 	select {
 	case batch, ok := <-c.actionBatchInChan:
 		if !ok {
@@ -191,6 +628,8 @@ func (c *Client) Sync() (didReceiveNewBatch bool, didTick bool, err error) {
 func (c *Client) SyncUntil(blockNumber uint64) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	c.beginChanges()
+	defer c.endChanges()
 	for {
 		if c.core.BlockNumber() >= blockNumber {
 			break
@@ -212,6 +651,10 @@ func (c *Client) InterpolatedSync() (didReceiveNewBatch bool, didTick bool, err
 	if !c.core.ExpectTick() {
 		return c.Sync()
 	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.beginChanges()
+	defer c.endChanges()
 	select {
 	case batch, ok := <-c.actionBatchInChan:
 		if !ok {
@@ -255,9 +698,6 @@ func (c *Client) InterpolatedSync() (didReceiveNewBatch bool, didTick bool, err
 		return didReceiveNewBatch, didTick, nil
 	}
 
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
 	for c.ticksRunThisBlock < targetTicks {
 		c.core.SetInBlockTickIndex(c.ticksRunThisBlock)
 		arch.RunSingleTick(c.core)
@@ -1,7 +1,9 @@
 package client
 
 import (
+	"errors"
 	"math"
+	"math/big"
 	"reflect"
 	"testing"
 	"time"
@@ -10,7 +12,9 @@ import (
 	"github.com/concrete-eth/archetype/kvstore"
 	"github.com/concrete-eth/archetype/testutils"
 	"github.com/concrete-eth/archetype/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/concrete/lib"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 )
 
 func newTestClient(t *testing.T) (*Client, lib.KeyValueStore, chan arch.ActionBatch, chan []arch.Action) {
@@ -72,6 +76,113 @@ func TestSendAction(t *testing.T) {
 	}
 }
 
+func TestSendActionsRejected(t *testing.T) {
+	client, _, _, actionChan := newTestClient(t)
+	errNegativeSummand := errors.New("negative summand")
+	client.SetActionValidator(&testutils.ActionData_Add{}, ActionValidatorFunc(func(action arch.Action, core arch.Core) error {
+		if action.(*testutils.ActionData_Add).Summand < 0 {
+			return errNegativeSummand
+		}
+		return nil
+	}))
+
+	actionsIn := []arch.Action{
+		&testutils.ActionData_Add{Summand: 1},
+		&testutils.ActionData_Add{Summand: -1},
+		&struct{}{},
+	}
+	resultChan := make(chan SendResult, 1)
+	go func() {
+		result, err := client.SendActionsWithResult(actionsIn)
+		if err != nil {
+			t.Error(err)
+		}
+		resultChan <- result
+	}()
+	select {
+	case <-time.After(10 * time.Millisecond):
+		t.Fatal("timeout")
+	case actionsOut := <-actionChan:
+		if !reflect.DeepEqual(actionsIn[:1], actionsOut) {
+			t.Fatal("unexpected actions")
+		}
+	}
+
+	result := <-resultChan
+	if result.OK() {
+		t.Fatal("expected rejected actions")
+	}
+	if len(result.Rejected) != 2 {
+		t.Fatalf("expected %v rejected actions, got %v", 2, len(result.Rejected))
+	}
+	if r := result.Rejected[0]; r.Index != 1 || !errors.Is(r, errNegativeSummand) {
+		t.Errorf("unexpected rejection: %v", r)
+	}
+	if r := result.Rejected[1]; r.Index != 2 || !errors.Is(r, arch.ErrInvalidAction) {
+		t.Errorf("unexpected rejection: %v", r)
+	}
+
+	// A single rejected action is returned as an error and nothing is sent
+	if err := client.SendAction(actionsIn[1]); !errors.Is(err, errNegativeSummand) {
+		t.Errorf("expected %v, got %v", errNegativeSummand, err)
+	}
+	select {
+	case <-actionChan:
+		t.Fatal("unexpected actions sent")
+	default:
+	}
+}
+
+func TestSendActionsUnauthorized(t *testing.T) {
+	client, _, _, actionChan := newTestClient(t)
+	client.schemas.Actions.SetPermissions(map[string]arch.Permission{"Add": {Kind: arch.PermissionRole, Role: "adder"}})
+	actionBatchChan := make(chan arch.ActionBatch, 1)
+	client.actionBatchInChan = actionBatchChan
+
+	var (
+		owner   = common.Address{0x01}
+		account = common.Address{0x02}
+		add     = &testutils.ActionData_Add{Summand: 1}
+		grant   = &arch.GrantRoleAction{Role: arch.RoleId("adder"), Account: account}
+	)
+	client.SetOwner(owner)
+	client.SetAccount(account)
+
+	// Actions the account is not authorized to execute are rejected and nothing is sent
+	result, err := client.SendActionsWithResult([]arch.Action{add, grant})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Sent) != 0 || len(result.Rejected) != 2 {
+		t.Fatalf("expected all actions to be rejected, got %v sent", len(result.Sent))
+	}
+	for _, r := range result.Rejected {
+		if !errors.Is(r, arch.ErrUnauthorized) {
+			t.Errorf("expected %v, got %v", arch.ErrUnauthorized, r)
+		}
+	}
+	select {
+	case <-actionChan:
+		t.Fatal("unexpected actions sent")
+	default:
+	}
+
+	// Batches are applied without checking permissions, as they were checked on chain
+	actionBatchChan <- arch.ActionBatch{BlockNumber: 0, Actions: []arch.Action{grant}}
+	if _, _, err := client.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	go client.SendAction(add)
+	select {
+	case <-time.After(10 * time.Millisecond):
+		t.Fatal("timeout")
+	case actionsOut := <-actionChan:
+		if !reflect.DeepEqual([]arch.Action{add}, actionsOut) {
+			t.Fatal("unexpected actions")
+		}
+	}
+}
+
 var testData = []struct {
 	batch                arch.ActionBatch
 	expTickActionInBatch bool
@@ -166,6 +277,173 @@ func TestSync(t *testing.T) {
 	}
 }
 
+func TestSyncReorg(t *testing.T) {
+	client, _, _, _ := newTestClient(t)
+	actionBatchChan := make(chan arch.ActionBatch, 1)
+	client.actionBatchInChan = actionBatchChan
+
+	for _, actionBatch := range testData {
+		actionBatchChan <- actionBatch.batch
+		if _, _, err := client.Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Orphan the batches of blocks 2 and 3 and replace them with a canonical batch for block 2
+	actionBatchChan <- arch.ActionBatch{
+		BlockNumber: 2,
+		ReorgDepth:  2,
+		Actions: []arch.Action{
+			&testutils.ActionData_Add{Summand: 3},
+		},
+	}
+	if _, _, err := client.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if client.Core().BlockNumber() != 3 {
+		t.Errorf("expected %v, got %v", 3, client.Core().BlockNumber())
+	}
+	expCounterValue := testData[1].expCounterValue + 3
+	if c := client.Core().(*testutils.Core).GetCounter(); c != expCounterValue {
+		t.Errorf("expected %v, got %v", expCounterValue, c)
+	}
+
+	// Unwinding more blocks than journaled must fail
+	actionBatchChan <- arch.ActionBatch{
+		BlockNumber: 0,
+		ReorgDepth:  MaxRollbackDepth + 1,
+	}
+	if _, _, err := client.Sync(); err != ErrReorgTooDeep {
+		t.Errorf("expected %v, got %v", ErrReorgTooDeep, err)
+	}
+}
+
+func TestSyncResumeFromCheckpoint(t *testing.T) {
+	var (
+		schemas   = testutils.NewTestArchSchemas(t)
+		db        = memorydb.New()
+		blockTime = 1 * time.Second
+	)
+
+	// Apply the first two batches and record a checkpoint
+	actionBatchChan := make(chan arch.ActionBatch, 1)
+	client := New(schemas, &testutils.Core{}, kvstore.NewDiskKeyValueStore(db), actionBatchChan, nil, blockTime, 0)
+	for ii, actionBatch := range testData[:2] {
+		actionBatch.batch.BlockHash = common.BigToHash(big.NewInt(int64(ii + 1)))
+		actionBatchChan <- actionBatch.batch
+		if _, _, err := client.Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A new client on the same database resumes after the checkpoint
+	kv := kvstore.NewDiskKeyValueStore(db)
+	if blockNumber, blockHash, _ := kv.Checkpoint(); blockNumber != 1 || blockHash != common.BigToHash(big.NewInt(2)) {
+		t.Fatalf("unexpected checkpoint (%v, %v)", blockNumber, blockHash)
+	}
+	client = New(schemas, &testutils.Core{}, kv, actionBatchChan, nil, blockTime, 0)
+	if client.Core().BlockNumber() != 2 {
+		t.Fatalf("expected %v, got %v", 2, client.Core().BlockNumber())
+	}
+	if c := client.Core().(*testutils.Core).GetCounter(); c != testData[1].expCounterValue {
+		t.Errorf("expected %v, got %v", testData[1].expCounterValue, c)
+	}
+
+	// Batches already applied are ignored
+	for _, actionBatch := range testData {
+		actionBatchChan <- actionBatch.batch
+		if _, _, err := client.Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expCounterValue := testData[len(testData)-1].expCounterValue
+	if c := client.Core().(*testutils.Core).GetCounter(); c != expCounterValue {
+		t.Errorf("expected %v, got %v", expCounterValue, c)
+	}
+}
+
+func TestEventChannel(t *testing.T) {
+	client, _, _, actionChan := newTestClient(t)
+	actionBatchChan := make(chan arch.ActionBatch, 1)
+	client.actionBatchInChan = actionBatchChan
+	eventChan := make(chan EmittedEvent, 4)
+	client.SetEventChannel(eventChan)
+
+	// Events emitted while simulating actions are not sent
+	go client.SendAction(&testutils.ActionData_Add{Summand: 5})
+	<-actionChan
+
+	actionBatchChan <- arch.ActionBatch{
+		BlockNumber: 0,
+		Actions: []arch.Action{
+			&testutils.ActionData_Add{Summand: 1},
+			&testutils.ActionData_Add{Summand: math.MaxInt16}, // Fails, its events are discarded
+			&testutils.ActionData_Add{Summand: 2},
+		},
+	}
+	if _, _, err := client.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	expEvents := []EmittedEvent{
+		{BlockNumber: 0, ActionIndex: 0, Event: &testutils.Event_CounterUpdated{Previous: 0, Value: 1}},
+		{BlockNumber: 0, ActionIndex: 2, Event: &testutils.Event_CounterUpdated{Previous: 1, Value: 3}},
+	}
+	for _, expEvent := range expEvents {
+		select {
+		case event := <-eventChan:
+			if !reflect.DeepEqual(event, expEvent) {
+				t.Errorf("expected %v, got %v", expEvent, event)
+			}
+		default:
+			t.Fatal("expected event")
+		}
+	}
+	select {
+	case event := <-eventChan:
+		t.Fatalf("unexpected event %v", event)
+	default:
+	}
+}
+
+func TestChangeFeed(t *testing.T) {
+	client, _, _, _ := newTestClient(t)
+	actionBatchChan := make(chan arch.ActionBatch, 1)
+	client.actionBatchInChan = actionBatchChan
+	if err := client.EnableChangeFeed(); err != nil {
+		t.Fatal(err)
+	}
+	counterId, _ := client.schemas.Tables.TableIdFromName("Counter")
+	expChange := []RowChange{{TableId: counterId, Table: "Counter", Keys: []interface{}{}}}
+
+	for ii, data := range []struct {
+		batch      arch.ActionBatch
+		expChanges []RowChange
+	}{
+		{arch.ActionBatch{BlockNumber: 0, Actions: []arch.Action{&testutils.ActionData_Add{Summand: 1}}}, expChange},
+		{arch.ActionBatch{BlockNumber: 1, Actions: []arch.Action{&testutils.ActionData_Add{Summand: 0}}}, []RowChange{}},
+		{arch.ActionBatch{BlockNumber: 2, Actions: []arch.Action{}}, []RowChange{}},
+		// Unwinding block 0 reverts the counter
+		{arch.ActionBatch{BlockNumber: 0, ReorgDepth: 3, Actions: []arch.Action{}}, expChange},
+	} {
+		actionBatchChan <- data.batch
+		if _, _, err := client.Sync(); err != nil {
+			t.Fatal(err)
+		}
+		if changes := client.Changes(); !reflect.DeepEqual(changes, data.expChanges) {
+			t.Errorf("batch %d: expected %v, got %v", ii, data.expChanges, changes)
+		}
+	}
+
+	// No batch, no changes
+	if _, _, err := client.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if changes := client.Changes(); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
 func TestSyncUntil(t *testing.T) {
 	client, _, actionBatchChan, _ := newTestClient(t)
 
@@ -272,3 +550,94 @@ func TestInterpolatedSync(t *testing.T) {
 		clock.Advance(client.blockTime / time.Duration(ticksPerBlock*2))
 	}
 }
+
+// contextCore records the execution context of every Add action.
+type contextCore struct {
+	testutils.Core
+	ctxs []arch.ExecutionContext
+}
+
+func (c *contextCore) Add(action *testutils.ActionData_Add) error {
+	c.ctxs = append(c.ctxs, c.ExecutionContext())
+	return c.Core.Add(action)
+}
+
+func TestSyncExecutionContext(t *testing.T) {
+	var (
+		schemas         = testutils.NewTestArchSchemas(t)
+		core            = &contextCore{}
+		actionBatchChan = make(chan arch.ActionBatch, 1)
+		client          = New(schemas, core, kvstore.NewMemoryKeyValueStore(), actionBatchChan, make(chan []arch.Action, 1), time.Second, 0)
+		localCtx        = arch.ExecutionContext{Origin: common.Address{0x01}}
+		batchCtxs       = []arch.ExecutionContext{
+			{Caller: common.Address{0x02}, Origin: common.Address{0x03}, BlockTimestamp: 10, TxHash: common.Hash{0x04}},
+			{Caller: common.Address{0x02}, Origin: common.Address{0x05}, BlockTimestamp: 10, TxHash: common.Hash{0x06}},
+		}
+	)
+	client.SetExecutionContext(localCtx)
+
+	// Actions in a batch run with the context of the batch
+	actionBatchChan <- arch.ActionBatch{
+		BlockNumber: 0,
+		Actions:     []arch.Action{&testutils.ActionData_Add{}, &testutils.ActionData_Add{}},
+		Contexts:    batchCtxs,
+	}
+	if _, _, err := client.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(core.ctxs, batchCtxs) {
+		t.Errorf("expected %v, got %v", batchCtxs, core.ctxs)
+	}
+
+	// Actions with unknown context run with a zero context
+	core.ctxs = nil
+	actionBatchChan <- arch.NewActionBatch(1, []arch.Action{&testutils.ActionData_Add{}})
+	if _, _, err := client.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if expCtxs := []arch.ExecutionContext{{}}; !reflect.DeepEqual(core.ctxs, expCtxs) {
+		t.Errorf("expected %v, got %v", expCtxs, core.ctxs)
+	}
+
+	// Simulated actions run with the local context
+	core.ctxs = nil
+	if err := client.SendAction(&testutils.ActionData_Add{}); err != nil {
+		t.Fatal(err)
+	}
+	if expCtxs := []arch.ExecutionContext{localCtx}; !reflect.DeepEqual(core.ctxs, expCtxs) {
+		t.Errorf("expected %v, got %v", expCtxs, core.ctxs)
+	}
+}
+
+func TestSyncCheckpointUnknownHash(t *testing.T) {
+	var (
+		schemas   = testutils.NewTestArchSchemas(t)
+		kv        = kvstore.NewDiskKeyValueStore(memorydb.New())
+		blockTime = 1 * time.Second
+	)
+	actionBatchChan := make(chan arch.ActionBatch, 1)
+	client := New(schemas, &testutils.Core{}, kv, actionBatchChan, nil, blockTime, 0)
+
+	// Batches with unknown hashes are not recorded as checkpoints
+	for ii, actionBatch := range testData[:2] {
+		if ii == 0 {
+			actionBatch.batch.BlockHash = common.BigToHash(big.NewInt(1))
+		}
+		actionBatchChan <- actionBatch.batch
+		if _, _, err := client.Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if blockNumber, blockHash, _ := kv.Checkpoint(); blockNumber != 0 || blockHash != common.BigToHash(big.NewInt(1)) {
+		t.Fatalf("unexpected checkpoint (%v, %v)", blockNumber, blockHash)
+	}
+
+	// A client resuming from a checkpoint fails if the checkpoint block is received with another hash
+	client = New(schemas, &testutils.Core{}, kv, actionBatchChan, nil, blockTime, 0)
+	batch := testData[0].batch
+	batch.BlockHash = common.BigToHash(big.NewInt(2))
+	actionBatchChan <- batch
+	if _, _, err := client.Sync(); !errors.Is(err, ErrCheckpointOrphaned) {
+		t.Errorf("expected %v, got %v", ErrCheckpointOrphaned, err)
+	}
+}
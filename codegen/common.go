@@ -2,6 +2,7 @@ package codegen
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -10,14 +11,17 @@ import (
 	"text/tabwriter"
 	"text/template"
 
+	"github.com/concrete-eth/archetype/arch"
 	"github.com/concrete-eth/archetype/params"
 	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/iancoleman/orderedmap"
 )
 
 type Config struct {
 	ActionsJsonPath string
 	TablesJsonPath  string
+	EventsJsonPath  string // Optional
 	Out             string
 }
 
@@ -37,6 +41,12 @@ func (c Config) Validate() error {
 		return errors.New("error validating tables schema file: " + err.Error())
 	}
 
+	if c.EventsJsonPath != "" {
+		if err := CheckFile(c.EventsJsonPath); err != nil {
+			return errors.New("error validating events schema file: " + err.Error())
+		}
+	}
+
 	if c.Out == "" {
 		return errors.New("output directory is required")
 	}
@@ -71,24 +81,81 @@ func CheckDir(dirPath string) error {
 	return nil
 }
 
+// actionParam is a parameter of the Solidity method of an action.
+type actionParam struct {
+	Type string // Type of the parameter, including its data location
+	Name string
+}
+
+// actionParams returns the parameters of the Solidity method of an action, i.e., its keys followed by
+// the struct of its values, if any.
+func actionParams(schema datamod.TableSchema) []actionParam {
+	list := make([]actionParam, 0, len(schema.Keys)+1)
+	for _, key := range schema.Keys {
+		list = append(list, actionParam{Type: key.Type.SolType, Name: key.Name})
+	}
+	if len(schema.Values) > 0 {
+		list = append(list, actionParam{Type: params.SolidityActionStructName(schema.Name) + " memory", Name: "action"})
+	}
+	return list
+}
+
+// actionParamDecls returns the declarations of the parameters of the Solidity method of an action.
+func actionParamDecls(schema datamod.TableSchema) []string {
+	decls := make([]string, 0, len(schema.Keys)+1)
+	for _, param := range actionParams(schema) {
+		decls = append(decls, param.Type+" "+param.Name)
+	}
+	return decls
+}
+
 var DefaultFuncMap = template.FuncMap{
 	"_sub": func(a, b int) int { return a - b },
 	"_actionId": func(schema datamod.TableSchema) string {
-		var argsSign string
+		argTypes := make([]string, 0, len(schema.Keys)+1)
+		for _, field := range schema.Keys {
+			argTypes = append(argTypes, field.Type.SolType)
+		}
 		if len(schema.Values) > 0 {
 			solTypes := make([]string, 0, len(schema.Values))
 			for _, field := range schema.Values {
 				solTypes = append(solTypes, field.Type.SolType)
 			}
-			argsSign = "(" + strings.Join(solTypes, ",") + ")"
-		} else {
-			argsSign = ""
+			argTypes = append(argTypes, "("+strings.Join(solTypes, ",")+")")
 		}
 		methodName := params.SolidityActionMethodName(schema.Name)
-		sign := fmt.Sprintf("%s(%s)", methodName, argsSign)
+		sign := fmt.Sprintf("%s(%s)", methodName, strings.Join(argTypes, ","))
 		hash := crypto.Keccak256([]byte(sign))
 		return fmt.Sprintf("0x%x", hash[:4])
 	},
+	// Parameter declarations of the Solidity method of an action, e.g., "uint8 id, ActionData_Move memory action"
+	"_actionParams": func(schema datamod.TableSchema) string {
+		return strings.Join(actionParamDecls(schema), ", ")
+	},
+	// Arguments of a call to the Solidity method of an action, e.g., "id, action"
+	"_actionArgs": func(schema datamod.TableSchema) string {
+		names := make([]string, 0, len(schema.Keys)+1)
+		for _, param := range actionParams(schema) {
+			names = append(names, param.Name)
+		}
+		return strings.Join(names, ", ")
+	},
+	// Types of the parameters of the Solidity method of an action, as passed to abi.decode
+	"_actionTypes": func(schema datamod.TableSchema) string {
+		types := make([]string, 0, len(schema.Keys)+1)
+		for _, param := range actionParams(schema) {
+			types = append(types, strings.TrimSuffix(param.Type, " memory"))
+		}
+		return strings.Join(types, ", ")
+	},
+	// Declaration of the variables assigned the result of abi.decode of the parameters of an action
+	"_actionDecl": func(schema datamod.TableSchema) string {
+		decls := actionParamDecls(schema)
+		if len(decls) == 1 {
+			return decls[0]
+		}
+		return "(" + strings.Join(decls, ", ") + ")"
+	},
 }
 
 // ExecuteTemplate executes a template with the given data and writes the output to a file.
@@ -151,6 +218,85 @@ func ExecuteTemplate(tplStr string, jsonSchemaPath, outPath string, data map[str
 	return nil
 }
 
+// UnmarshalErrorSchemas unmarshals the custom errors declared in an actions schema.
+// Errors are declared per action under the "errors" key, mapping error names to their fields:
+//
+//	"add": {
+//	    "schema": {"summand": "int16"},
+//	    "errors": {"counterOverflow": {"counter": "int16", "summand": "int16"}}
+//	}
+//
+// Error names are shared by all actions. An error can be declared by several actions as long as
+// all declarations have the same fields.
+func UnmarshalErrorSchemas(jsonContent []byte) ([]datamod.TableSchema, error) {
+	jsonActions := orderedmap.New()
+	if err := json.Unmarshal(jsonContent, &jsonActions); err != nil {
+		return nil, err
+	}
+
+	jsonErrors := orderedmap.New()
+	for _, actionName := range jsonActions.Keys() {
+		_jsonAction, _ := jsonActions.Get(actionName)
+		jsonAction, ok := _jsonAction.(orderedmap.OrderedMap)
+		if !ok {
+			return nil, fmt.Errorf("invalid schema for action '%s'", actionName)
+		}
+		_jsonActionErrors, ok := jsonAction.Get("errors")
+		if !ok {
+			continue
+		}
+		jsonActionErrors, ok := _jsonActionErrors.(orderedmap.OrderedMap)
+		if !ok {
+			return nil, fmt.Errorf("invalid errors for action '%s'", actionName)
+		}
+		for _, errorName := range jsonActionErrors.Keys() {
+			_jsonFields, _ := jsonActionErrors.Get(errorName)
+			jsonFields, ok := _jsonFields.(orderedmap.OrderedMap)
+			if !ok {
+				return nil, fmt.Errorf("invalid schema for error '%s' in action '%s'", errorName, actionName)
+			}
+			if _prevJsonError, ok := jsonErrors.Get(errorName); ok {
+				prevJsonError := _prevJsonError.(*orderedmap.OrderedMap)
+				_prevJsonFields, _ := prevJsonError.Get("schema")
+				prev, _ := json.Marshal(_prevJsonFields)
+				this, _ := json.Marshal(jsonFields)
+				if !bytes.Equal(prev, this) {
+					return nil, fmt.Errorf("error '%s' declared with different fields", errorName)
+				}
+				continue
+			}
+			jsonError := orderedmap.New()
+			jsonError.Set("schema", jsonFields)
+			jsonErrors.Set(errorName, jsonError)
+		}
+	}
+
+	// Error fields are parsed as a table value schema
+	errorsJsonContent, err := json.Marshal(jsonErrors)
+	if err != nil {
+		return nil, err
+	}
+	return datamod.UnmarshalTableSchemas(errorsJsonContent, false)
+}
+
+// LoadErrorSchemas loads the custom errors declared in the actions schema at the given path.
+func LoadErrorSchemas(jsonSchemaPath string) ([]datamod.TableSchema, error) {
+	jsonContent, err := os.ReadFile(jsonSchemaPath)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalErrorSchemas(jsonContent)
+}
+
+// LoadPermissions loads the action permissions declared in the actions schema at the given path.
+func LoadPermissions(jsonSchemaPath string) (map[string]arch.Permission, error) {
+	jsonContent, err := os.ReadFile(jsonSchemaPath)
+	if err != nil {
+		return nil, err
+	}
+	return arch.UnmarshalPermissions(jsonContent)
+}
+
 // GenerateSchemasDescriptionString generates a string with the description of the schemas.
 func GenerateSchemasDescriptionString(schemas []datamod.TableSchema) string {
 	sizeData := [][]string{{"Table", "KeySize", "ValueSize"}}
@@ -0,0 +1,197 @@
+package codegen
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
+)
+
+// Compatibility classifies a schema change by its effect on the data written with the old schemas.
+type Compatibility int
+
+const (
+	// Safe changes do not affect how existing rows and action logs are read.
+	Safe Compatibility = iota
+	// NeedsMigration changes leave existing rows in a layout the new schemas read incorrectly, so they
+	// must be migrated before they are read.
+	NeedsMigration
+	// Breaking changes make existing rows unreachable or old action logs impossible to decode.
+	Breaking
+)
+
+func (c Compatibility) String() string {
+	switch c {
+	case Safe:
+		return "safe"
+	case NeedsMigration:
+		return "needs-migration"
+	case Breaking:
+		return "breaking"
+	default:
+		return "unknown"
+	}
+}
+
+// SchemaChange is a change to a table or action schema between two schema versions.
+type SchemaChange struct {
+	Schema        string // Name of the table or action
+	Field         string // Name of the field or key changed, empty if the whole schema changed
+	Description   string
+	Compatibility Compatibility
+}
+
+func (c SchemaChange) String() string {
+	name := c.Schema
+	if c.Field != "" {
+		name += "." + c.Field
+	}
+	return fmt.Sprintf("%s: %s", name, c.Description)
+}
+
+// MaxCompatibility returns the least compatible classification of the given changes, or Safe if there
+// are none.
+func MaxCompatibility(changes []SchemaChange) Compatibility {
+	max := Safe
+	for _, change := range changes {
+		if change.Compatibility > max {
+			max = change.Compatibility
+		}
+	}
+	return max
+}
+
+// fieldChangeCompatibility classifies the ways the fields of a schema can change.
+type fieldChangeCompatibility struct {
+	typeChanged Compatibility
+	removed     Compatibility
+	added       Compatibility
+}
+
+// compareFields returns the changes between two lists of fields of the given kind, i.e., "key" or
+// "field". Fields are matched by position, as they are laid out in storage and encoded in calldata.
+func compareFields(schema, kind string, oldFields, newFields []datamod.FieldSchema, compat fieldChangeCompatibility) []SchemaChange {
+	changes := make([]SchemaChange, 0)
+	for ii, oldField := range oldFields {
+		if ii >= len(newFields) {
+			changes = append(changes, SchemaChange{
+				Schema:        schema,
+				Field:         oldField.Name,
+				Description:   kind + " removed",
+				Compatibility: compat.removed,
+			})
+			continue
+		}
+		newField := newFields[ii]
+		if oldField.Type.Name != newField.Type.Name {
+			changes = append(changes, SchemaChange{
+				Schema:        schema,
+				Field:         oldField.Name,
+				Description:   fmt.Sprintf("%s type changed from %s to %s", kind, oldField.Type.Name, newField.Type.Name),
+				Compatibility: compat.typeChanged,
+			})
+		}
+		if oldField.Name != newField.Name {
+			changes = append(changes, SchemaChange{
+				Schema:        schema,
+				Field:         oldField.Name,
+				Description:   fmt.Sprintf("%s renamed to %s", kind, newField.Name),
+				Compatibility: Safe,
+			})
+		}
+	}
+	for ii := len(oldFields); ii < len(newFields); ii++ {
+		changes = append(changes, SchemaChange{
+			Schema:        schema,
+			Field:         newFields[ii].Name,
+			Description:   kind + " added",
+			Compatibility: compat.added,
+		})
+	}
+	return changes
+}
+
+// compareSchemas returns the changes between two schema versions, in the order of the old schemas
+// followed by the added schemas in the order of the new schemas.
+func compareSchemas(
+	oldSchemas, newSchemas []datamod.TableSchema,
+	kind string,
+	removed Compatibility,
+	compareFn func(oldSchema, newSchema datamod.TableSchema) []SchemaChange,
+) []SchemaChange {
+	oldByName := make(map[string]struct{}, len(oldSchemas))
+	for _, schema := range oldSchemas {
+		oldByName[schema.Name] = struct{}{}
+	}
+	newByName := make(map[string]datamod.TableSchema, len(newSchemas))
+	for _, schema := range newSchemas {
+		newByName[schema.Name] = schema
+	}
+
+	changes := make([]SchemaChange, 0)
+	for _, oldSchema := range oldSchemas {
+		newSchema, ok := newByName[oldSchema.Name]
+		if !ok {
+			changes = append(changes, SchemaChange{
+				Schema:        oldSchema.Name,
+				Description:   kind + " removed",
+				Compatibility: removed,
+			})
+			continue
+		}
+		changes = append(changes, compareFn(oldSchema, newSchema)...)
+	}
+	for _, newSchema := range newSchemas {
+		if _, ok := oldByName[newSchema.Name]; !ok {
+			changes = append(changes, SchemaChange{
+				Schema:        newSchema.Name,
+				Description:   kind + " added",
+				Compatibility: Safe,
+			})
+		}
+	}
+	return changes
+}
+
+// CheckTableSchemas compares two versions of the table schemas and classifies every change by its
+// effect on the rows already in storage:
+//   - Adding a table, renaming a key or field, or appending a field is safe.
+//   - Removing a table or field, or changing the type of a field, needs a migration as the old values are
+//     left in storage and read with the new layout.
+//   - Adding, removing or changing the type of a key is breaking as the rows are stored at slots derived
+//     from their keys, so existing rows cannot be found with the new keys.
+//
+// Renaming a table is reported as removing it and adding a new one.
+func CheckTableSchemas(oldSchemas, newSchemas []datamod.TableSchema) []SchemaChange {
+	return compareSchemas(oldSchemas, newSchemas, "table", NeedsMigration, func(oldSchema, newSchema datamod.TableSchema) []SchemaChange {
+		keyChanges := compareFields(oldSchema.Name, "key", oldSchema.Keys, newSchema.Keys, fieldChangeCompatibility{
+			typeChanged: Breaking,
+			removed:     Breaking,
+			added:       Breaking,
+		})
+		valueChanges := compareFields(oldSchema.Name, "field", oldSchema.Values, newSchema.Values, fieldChangeCompatibility{
+			typeChanged: NeedsMigration,
+			removed:     NeedsMigration,
+			added:       Safe,
+		})
+		return append(keyChanges, valueChanges...)
+	})
+}
+
+// CheckActionSchemas compares two versions of the action schemas and classifies every change by its
+// effect on decoding old action logs:
+//   - Adding an action or renaming a key or field is safe.
+//   - Removing an action, or adding, removing or changing the type of a key or field is breaking as old
+//     action logs are decoded by the selector of the action, which is derived from its name and its key
+//     and field types.
+func CheckActionSchemas(oldSchemas, newSchemas []datamod.TableSchema) []SchemaChange {
+	return compareSchemas(oldSchemas, newSchemas, "action", Breaking, func(oldSchema, newSchema datamod.TableSchema) []SchemaChange {
+		compat := fieldChangeCompatibility{
+			typeChanged: Breaking,
+			removed:     Breaking,
+			added:       Breaking,
+		}
+		keyChanges := compareFields(oldSchema.Name, "key", oldSchema.Keys, newSchema.Keys, compat)
+		valueChanges := compareFields(oldSchema.Name, "field", oldSchema.Values, newSchema.Values, compat)
+		return append(keyChanges, valueChanges...)
+	})
+}
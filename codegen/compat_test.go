@@ -0,0 +1,249 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
+)
+
+func mustSchemas(t *testing.T, schemaJson string) []datamod.TableSchema {
+	schemas, err := datamod.UnmarshalTableSchemas([]byte(schemaJson), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schemas
+}
+
+type expectedChange struct {
+	schema        string
+	field         string
+	compatibility Compatibility
+}
+
+func testCompat(
+	t *testing.T,
+	check func(oldSchemas, newSchemas []datamod.TableSchema) []SchemaChange,
+	name, oldJson, newJson string,
+	expected []expectedChange,
+	expectedMax Compatibility,
+) {
+	t.Helper()
+	changes := check(mustSchemas(t, oldJson), mustSchemas(t, newJson))
+	if len(changes) != len(expected) {
+		t.Fatalf("%s: expected %d changes, got %d: %v", name, len(expected), len(changes), changes)
+	}
+	for ii, change := range changes {
+		if change.Schema != expected[ii].schema || change.Field != expected[ii].field || change.Compatibility != expected[ii].compatibility {
+			t.Errorf("%s: unexpected change %d: %v (%v)", name, ii, change, change.Compatibility)
+		}
+	}
+	if max := MaxCompatibility(changes); max != expectedMax {
+		t.Errorf("%s: expected %v, got %v", name, expectedMax, max)
+	}
+}
+
+func TestCheckTableSchemas(t *testing.T) {
+	base := `{"players":{"keySchema":{"id":"uint8"},"schema":{"score":"int16","name":"string"}}}`
+	testData := []struct {
+		name     string
+		oldJson  string
+		newJson  string
+		expected []expectedChange
+		max      Compatibility
+	}{
+		{
+			name:    "unchanged",
+			oldJson: base,
+			newJson: base,
+			max:     Safe,
+		},
+		{
+			name:     "table added",
+			oldJson:  base,
+			newJson:  `{"players":{"keySchema":{"id":"uint8"},"schema":{"score":"int16","name":"string"}},"config":{"schema":{"speed":"uint8"}}}`,
+			expected: []expectedChange{{"Config", "", Safe}},
+			max:      Safe,
+		},
+		{
+			name:     "field appended",
+			oldJson:  base,
+			newJson:  `{"players":{"keySchema":{"id":"uint8"},"schema":{"score":"int16","name":"string","active":"bool"}}}`,
+			expected: []expectedChange{{"Players", "active", Safe}},
+			max:      Safe,
+		},
+		{
+			name:     "field renamed",
+			oldJson:  base,
+			newJson:  `{"players":{"keySchema":{"id":"uint8"},"schema":{"points":"int16","name":"string"}}}`,
+			expected: []expectedChange{{"Players", "score", Safe}},
+			max:      Safe,
+		},
+		{
+			name:     "key renamed",
+			oldJson:  base,
+			newJson:  `{"players":{"keySchema":{"playerId":"uint8"},"schema":{"score":"int16","name":"string"}}}`,
+			expected: []expectedChange{{"Players", "id", Safe}},
+			max:      Safe,
+		},
+		{
+			name:     "field type changed",
+			oldJson:  base,
+			newJson:  `{"players":{"keySchema":{"id":"uint8"},"schema":{"score":"int64","name":"string"}}}`,
+			expected: []expectedChange{{"Players", "score", NeedsMigration}},
+			max:      NeedsMigration,
+		},
+		{
+			name:     "field removed",
+			oldJson:  base,
+			newJson:  `{"players":{"keySchema":{"id":"uint8"},"schema":{"score":"int16"}}}`,
+			expected: []expectedChange{{"Players", "name", NeedsMigration}},
+			max:      NeedsMigration,
+		},
+		{
+			name:     "table removed",
+			oldJson:  base,
+			newJson:  `{"config":{"schema":{"speed":"uint8"}}}`,
+			expected: []expectedChange{{"Players", "", NeedsMigration}, {"Config", "", Safe}},
+			max:      NeedsMigration,
+		},
+		{
+			name:     "table renamed",
+			oldJson:  base,
+			newJson:  `{"users":{"keySchema":{"id":"uint8"},"schema":{"score":"int16","name":"string"}}}`,
+			expected: []expectedChange{{"Players", "", NeedsMigration}, {"Users", "", Safe}},
+			max:      NeedsMigration,
+		},
+		{
+			name:     "key type changed",
+			oldJson:  base,
+			newJson:  `{"players":{"keySchema":{"id":"uint16"},"schema":{"score":"int16","name":"string"}}}`,
+			expected: []expectedChange{{"Players", "id", Breaking}},
+			max:      Breaking,
+		},
+		{
+			name:     "key added",
+			oldJson:  base,
+			newJson:  `{"players":{"keySchema":{"id":"uint8","team":"uint8"},"schema":{"score":"int16","name":"string"}}}`,
+			expected: []expectedChange{{"Players", "team", Breaking}},
+			max:      Breaking,
+		},
+		{
+			name:     "key removed",
+			oldJson:  base,
+			newJson:  `{"players":{"schema":{"score":"int16","name":"string"}}}`,
+			expected: []expectedChange{{"Players", "id", Breaking}},
+			max:      Breaking,
+		},
+		{
+			name:     "key and field changed",
+			oldJson:  base,
+			newJson:  `{"players":{"keySchema":{"id":"uint16"},"schema":{"score":"int64","name":"string","active":"bool"}}}`,
+			expected: []expectedChange{{"Players", "id", Breaking}, {"Players", "score", NeedsMigration}, {"Players", "active", Safe}},
+			max:      Breaking,
+		},
+	}
+	for _, tt := range testData {
+		testCompat(t, CheckTableSchemas, tt.name, tt.oldJson, tt.newJson, tt.expected, tt.max)
+	}
+}
+
+func TestCheckActionSchemas(t *testing.T) {
+	var (
+		base  = `{"move":{"schema":{"x":"int16","y":"int16"}}}`
+		keyed = `{"move":{"keySchema":{"id":"uint8"},"schema":{"x":"int16","y":"int16"}}}`
+	)
+	testData := []struct {
+		name     string
+		oldJson  string
+		newJson  string
+		expected []expectedChange
+		max      Compatibility
+	}{
+		{
+			name:    "unchanged",
+			oldJson: base,
+			newJson: base,
+			max:     Safe,
+		},
+		{
+			name:     "action added",
+			oldJson:  base,
+			newJson:  `{"move":{"schema":{"x":"int16","y":"int16"}},"attack":{"schema":{"target":"uint8"}}}`,
+			expected: []expectedChange{{"Attack", "", Safe}},
+			max:      Safe,
+		},
+		{
+			name:     "field renamed",
+			oldJson:  base,
+			newJson:  `{"move":{"schema":{"dx":"int16","y":"int16"}}}`,
+			expected: []expectedChange{{"Move", "x", Safe}},
+			max:      Safe,
+		},
+		{
+			name:     "field type changed",
+			oldJson:  base,
+			newJson:  `{"move":{"schema":{"x":"int32","y":"int16"}}}`,
+			expected: []expectedChange{{"Move", "x", Breaking}},
+			max:      Breaking,
+		},
+		{
+			name:     "field added",
+			oldJson:  base,
+			newJson:  `{"move":{"schema":{"x":"int16","y":"int16","z":"int16"}}}`,
+			expected: []expectedChange{{"Move", "z", Breaking}},
+			max:      Breaking,
+		},
+		{
+			name:     "field removed",
+			oldJson:  base,
+			newJson:  `{"move":{"schema":{"x":"int16"}}}`,
+			expected: []expectedChange{{"Move", "y", Breaking}},
+			max:      Breaking,
+		},
+		{
+			name:     "action removed",
+			oldJson:  base,
+			newJson:  `{"attack":{"schema":{"target":"uint8"}}}`,
+			expected: []expectedChange{{"Move", "", Breaking}, {"Attack", "", Safe}},
+			max:      Breaking,
+		},
+		{
+			name:     "action renamed",
+			oldJson:  base,
+			newJson:  `{"walk":{"schema":{"x":"int16","y":"int16"}}}`,
+			expected: []expectedChange{{"Move", "", Breaking}, {"Walk", "", Safe}},
+			max:      Breaking,
+		},
+		{
+			name:     "key renamed",
+			oldJson:  keyed,
+			newJson:  `{"move":{"keySchema":{"unit":"uint8"},"schema":{"x":"int16","y":"int16"}}}`,
+			expected: []expectedChange{{"Move", "id", Safe}},
+			max:      Safe,
+		},
+		{
+			name:     "key type changed",
+			oldJson:  keyed,
+			newJson:  `{"move":{"keySchema":{"id":"uint16"},"schema":{"x":"int16","y":"int16"}}}`,
+			expected: []expectedChange{{"Move", "id", Breaking}},
+			max:      Breaking,
+		},
+		{
+			name:     "key added",
+			oldJson:  base,
+			newJson:  keyed,
+			expected: []expectedChange{{"Move", "id", Breaking}},
+			max:      Breaking,
+		},
+		{
+			name:     "key removed",
+			oldJson:  keyed,
+			newJson:  base,
+			expected: []expectedChange{{"Move", "id", Breaking}},
+			max:      Breaking,
+		},
+	}
+	for _, tt := range testData {
+		testCompat(t, CheckActionSchemas, tt.name, tt.oldJson, tt.newJson, tt.expected, tt.max)
+	}
+}
@@ -3,22 +3,33 @@ package gogen
 import (
 	_ "embed"
 	"errors"
+	"fmt"
 	"html/template"
 	"path/filepath"
 
 	"github.com/concrete-eth/archetype/codegen"
 	"github.com/concrete-eth/archetype/params"
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
 )
 
 //go:embed templates/types.go.tpl
 var typesTpl string
 
+//go:embed templates/errors.go.tpl
+var errorsTpl string
+
 //go:embed templates/actions.go.tpl
 var actionsTpl string
 
+//go:embed templates/events.go.tpl
+var eventsTpl string
+
 //go:embed templates/tables.go.tpl
 var tablesTpl string
 
+//go:embed templates/codecs.go.tpl
+var codecsTpl string
+
 type importSpecs struct {
 	Name string
 	Path string
@@ -47,19 +58,39 @@ func (c Config) Validate() error {
 }
 
 // GenerateActionTypes generates the go code for the action types.
+// The fields of keyed actions are their keys followed by their values.
 func GenerateActionTypes(config Config) error {
 	data := make(map[string]interface{})
 	data["Package"] = config.PackageName
+	data["Keyed"] = true
 	funcMap := make(template.FuncMap)
 	funcMap["StructNameFn"] = params.GoActionStructName
 	outPath := filepath.Join(config.Out, "action_types.go")
 	return codegen.ExecuteTemplate(typesTpl, config.ActionsJsonPath, outPath, data, funcMap)
 }
 
+// GenerateActionErrors generates the go code for the action error types.
+func GenerateActionErrors(config Config) error {
+	errorSchemas, err := codegen.LoadErrorSchemas(config.ActionsJsonPath)
+	if err != nil {
+		return err
+	}
+	data := make(map[string]interface{})
+	data["Package"] = config.PackageName
+	data["Errors"] = errorSchemas
+	outPath := filepath.Join(config.Out, "action_errors.go")
+	return codegen.ExecuteTemplate(errorsTpl, "", outPath, data, nil)
+}
+
 // GenerateActions generates the go code for the ActionSchemas.
 func GenerateActions(config Config) error {
+	errorSchemas, err := codegen.LoadErrorSchemas(config.ActionsJsonPath)
+	if err != nil {
+		return err
+	}
 	data := make(map[string]interface{})
 	data["Package"] = config.PackageName
+	data["Errors"] = errorSchemas
 	data["Imports"] = []importSpecs{
 		{"contract", filepath.Join(config.ContractsImportPath, params.EntrypointContract.PackageName)},
 	}
@@ -68,6 +99,123 @@ func GenerateActions(config Config) error {
 	return codegen.ExecuteTemplate(actionsTpl, config.ActionsJsonPath, outPath, data, nil)
 }
 
+// isDynamic returns whether a schema is encoded as a dynamic ABI tuple, i.e., has a bytes or string field.
+func isDynamic(schema datamod.TableSchema) bool {
+	for _, value := range schema.Values {
+		if value.Type.Type == datamod.BytesType {
+			return true
+		}
+	}
+	return false
+}
+
+// abiEncodeStatement returns the statement that ABI-encodes a field of the struct v with the encoder enc.
+func abiEncodeStatement(field datamod.FieldSchema) (string, error) {
+	value := "v." + field.Title
+	if field.Type.Type == datamod.BytesType {
+		if field.Type.GoType == "string" {
+			return fmt.Sprintf("enc.WriteString(%s)", value), nil
+		}
+		return fmt.Sprintf("enc.WriteBytes(%s)", value), nil
+	}
+	switch field.Type.GoType {
+	case "bool":
+		return fmt.Sprintf("enc.WriteBool(%s)", value), nil
+	case "common.Address":
+		return fmt.Sprintf("enc.WriteAddress(%s)", value), nil
+	case "common.Hash":
+		return fmt.Sprintf("enc.WriteHash(%s)", value), nil
+	case "[]byte":
+		return fmt.Sprintf("enc.WriteFixedBytes(%s)", value), nil
+	case "*uint256.Int":
+		return fmt.Sprintf("enc.WriteUint256(%s)", value), nil
+	case "int8", "int16", "int32", "int64":
+		return fmt.Sprintf("enc.WriteInt(int64(%s))", value), nil
+	case "uint8", "uint16", "uint32", "uint64":
+		return fmt.Sprintf("enc.WriteUint(uint64(%s))", value), nil
+	}
+	return "", fmt.Errorf("field %s of type %s cannot be ABI-encoded", field.Name, field.Type.Name)
+}
+
+// abiDecodeStatement returns the statement that decodes a field of the struct v with the decoder dec.
+func abiDecodeStatement(field datamod.FieldSchema) (string, error) {
+	value := "v." + field.Title
+	if field.Type.Type == datamod.BytesType {
+		if field.Type.GoType == "string" {
+			return fmt.Sprintf("%s = dec.ReadString()", value), nil
+		}
+		return fmt.Sprintf("%s = dec.ReadBytes()", value), nil
+	}
+	switch goType := field.Type.GoType; goType {
+	case "bool":
+		return fmt.Sprintf("%s = dec.ReadBool()", value), nil
+	case "common.Address":
+		return fmt.Sprintf("%s = dec.ReadAddress()", value), nil
+	case "common.Hash":
+		return fmt.Sprintf("%s = dec.ReadHash()", value), nil
+	case "[]byte":
+		return fmt.Sprintf("%s = dec.ReadFixedBytes(%d)", value, field.Type.Size), nil
+	case "*uint256.Int":
+		return fmt.Sprintf("%s = dec.ReadUint256()", value), nil
+	case "int8", "int16", "int32", "int64":
+		return fmt.Sprintf("%s = %s(dec.ReadInt(%d))", value, goType, 8*field.Type.Size), nil
+	case "uint8", "uint16", "uint32", "uint64":
+		return fmt.Sprintf("%s = %s(dec.ReadUint(%d))", value, goType, 8*field.Type.Size), nil
+	}
+	return "", fmt.Errorf("field %s of type %s cannot be ABI-decoded", field.Name, field.Type.Name)
+}
+
+// codecsFuncMap returns the template functions of the codecs template for structs named by structNameFn.
+func codecsFuncMap(structNameFn func(string) string) template.FuncMap {
+	funcMap := make(template.FuncMap)
+	funcMap["StructNameFn"] = structNameFn
+	funcMap["IsDynamicFn"] = isDynamic
+	funcMap["ABIEncodeFn"] = abiEncodeStatement
+	funcMap["ABIDecodeFn"] = abiDecodeStatement
+	return funcMap
+}
+
+// GenerateActionCodecs generates the go code for the reflection-free ABI codecs of the action types.
+func GenerateActionCodecs(config Config) error {
+	data := make(map[string]interface{})
+	data["Package"] = config.PackageName
+	data["Kind"] = "action"
+	data["Encoding"] = "arguments of its action method"
+	data["Keyed"] = true
+	outPath := filepath.Join(config.Out, "action_codecs.go")
+	return codegen.ExecuteTemplate(codecsTpl, config.ActionsJsonPath, outPath, data, codecsFuncMap(params.GoActionStructName))
+}
+
+// GenerateTableCodecs generates the go code for the reflection-free ABI codecs of the table row types.
+func GenerateTableCodecs(config Config) error {
+	data := make(map[string]interface{})
+	data["Package"] = config.PackageName
+	data["Kind"] = "row"
+	data["Encoding"] = "return value of its table method"
+	outPath := filepath.Join(config.Out, "table_codecs.go")
+	return codegen.ExecuteTemplate(codecsTpl, config.TablesJsonPath, outPath, data, codecsFuncMap(params.GoTableStructName))
+}
+
+// GenerateEventTypes generates the go code for the event types.
+func GenerateEventTypes(config Config) error {
+	data := make(map[string]interface{})
+	data["Package"] = config.PackageName
+	funcMap := make(template.FuncMap)
+	funcMap["StructNameFn"] = params.GoEventStructName
+	outPath := filepath.Join(config.Out, "event_types.go")
+	return codegen.ExecuteTemplate(typesTpl, config.EventsJsonPath, outPath, data, funcMap)
+}
+
+// GenerateEvents generates the go code for the EventSchemas.
+// If no events schema is given, the EventSchemas are empty.
+func GenerateEvents(config Config) error {
+	data := make(map[string]interface{})
+	data["Package"] = config.PackageName
+	data["Json"] = "{}"
+	outPath := filepath.Join(config.Out, "events.go")
+	return codegen.ExecuteTemplate(eventsTpl, config.EventsJsonPath, outPath, data, nil)
+}
+
 // GenerateTableTypes generates the go code for the table types.
 func GenerateTableTypes(config Config) error {
 	data := make(map[string]interface{})
@@ -102,12 +250,29 @@ func Codegen(config Config) error {
 	if err := GenerateActionTypes(config); err != nil {
 		return errors.New("error generating go action types binding: " + err.Error())
 	}
+	if err := GenerateActionErrors(config); err != nil {
+		return errors.New("error generating go action errors binding: " + err.Error())
+	}
+	if err := GenerateActionCodecs(config); err != nil {
+		return errors.New("error generating go action codecs: " + err.Error())
+	}
 	if err := GenerateActions(config); err != nil {
 		return errors.New("error generating go actions binding: " + err.Error())
 	}
+	if config.EventsJsonPath != "" {
+		if err := GenerateEventTypes(config); err != nil {
+			return errors.New("error generating go event types binding: " + err.Error())
+		}
+	}
+	if err := GenerateEvents(config); err != nil {
+		return errors.New("error generating go events binding: " + err.Error())
+	}
 	if err := GenerateTableTypes(config); err != nil {
 		return errors.New("error generating go table types binding: " + err.Error())
 	}
+	if err := GenerateTableCodecs(config); err != nil {
+		return errors.New("error generating go table codecs: " + err.Error())
+	}
 	if err := GenerateTables(config); err != nil {
 		return errors.New("error generating go tables binding: " + err.Error())
 	}
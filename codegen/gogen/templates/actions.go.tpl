@@ -30,11 +30,59 @@ func init() {
 	if ActionSchemas, err = arch.NewActionSchemasFromRaw(ActionsABIJson, ActionSchemasJson, types); err != nil {
 		panic(err)
 	}
+    errorTypes := map[string]reflect.Type{
+        {{- range $schema := $.Errors }}
+        "{{ SolidityActionErrorNameFn $schema.Name }}": reflect.TypeOf({{ GoActionErrorStructNameFn $schema.Name }}{}),
+        {{- end }}
+    }
+    if err = ActionSchemas.RegisterErrors(errorTypes); err != nil {
+        panic(err)
+    }
+    ActionSchemas.SetActionDispatcher(ActionDispatcher{})
 }
 
-type IActions interface {
+// ActionHandler is implemented by cores that execute every action.
+type ActionHandler interface {
     {{- range $schema := $.Schemas }}
     {{ GoActionMethodNameFn $schema.Name }}(action *{{ GoActionStructNameFn $schema.Name }}) error
     {{- end }}
+}
+
+type IActions interface {
+    ActionHandler
     {{ GoActionMethodNameFn $.ArchParams.TickActionName }}()
 }
+
+// ActionDispatcher matches and executes the actions of this package without reflection.
+type ActionDispatcher struct{}
+
+var _ arch.ActionDispatcher = ActionDispatcher{}
+
+// ActionName returns the schema name of the action, or false if it is not an action of this package.
+func (ActionDispatcher) ActionName(action arch.Action) (string, bool) {
+    switch action.(type) {
+    {{- range $schema := $.Schemas }}
+    case *{{ GoActionStructNameFn $schema.Name }}:
+        return "{{$schema.Name}}", true
+    {{- end }}
+    }
+    return "", false
+}
+
+// DispatchAction executes the action on the target if it is an action of this package and the target
+// implements ActionHandler.
+func (ActionDispatcher) DispatchAction(action arch.Action, target arch.Core) (bool, error) {
+    {{- if $.Schemas }}
+    handler, ok := target.(ActionHandler)
+    if !ok {
+        return false, nil
+    }
+    switch action := action.(type) {
+    {{- range $schema := $.Schemas }}
+    case *{{ GoActionStructNameFn $schema.Name }}:
+        return true, handler.{{ GoActionMethodNameFn $schema.Name }}(action)
+    {{- end }}
+    }
+    {{- end }}
+    return false, nil
+}
@@ -23,11 +23,23 @@ var (
 
 {{ range $schema := $.Schemas }}
 type {{ StructNameFn $schema.Name }} struct{
+    {{- if $.Keyed }}
+    {{- range $key := $schema.Keys }}
+    {{$key.Title}} {{$key.Type.GoType}} `json:"{{$key.Name}}"`
+    {{- end }}
+    {{- end }}
     {{- range $value := $schema.Values }}
     {{$value.Title}} {{$value.Type.GoType}} `json:"{{$value.Name}}"`
     {{- end }}
 }
-{{ range $value := $schema.Values }}
+{{ if $.Keyed }}
+{{- range $key := $schema.Keys }}
+func (row *{{ StructNameFn $schema.Name }}) Get{{$key.Title}}() {{$key.Type.GoType}} {
+    return row.{{$key.Title}}
+}
+{{ end }}
+{{- end }}
+{{- range $value := $schema.Values }}
 func (row *{{ StructNameFn $schema.Name }}) Get{{$value.Title}}() {{$value.Type.GoType}} {
     return row.{{$value.Title}}
 }
@@ -3,8 +3,11 @@ package solgen
 import (
 	_ "embed"
 	"errors"
+	"fmt"
 	"path/filepath"
+	"text/template"
 
+	"github.com/concrete-eth/archetype/arch"
 	"github.com/concrete-eth/archetype/codegen"
 	"github.com/concrete-eth/archetype/params"
 )
@@ -15,6 +18,9 @@ var tablesTpl string
 //go:embed templates/actions.sol.tpl
 var actionsTpl string
 
+//go:embed templates/events.sol.tpl
+var eventsTpl string
+
 //go:embed templates/core.sol.tpl
 var coreTpl string
 
@@ -28,10 +34,31 @@ type Config struct {
 	codegen.Config
 }
 
+// hasRoles returns whether any action is restricted to a role. The built-in role actions are only
+// generated for games with roles, so the action IDs of other games do not change.
+func hasRoles(permissions map[string]arch.Permission) bool {
+	for _, permission := range permissions {
+		if permission.Kind == arch.PermissionRole {
+			return true
+		}
+	}
+	return false
+}
+
 // GenerateActions generates the solidity interface from the actions schema.
 func GenerateActions(config Config) error {
+	errorSchemas, err := codegen.LoadErrorSchemas(config.ActionsJsonPath)
+	if err != nil {
+		return err
+	}
+	permissions, err := codegen.LoadPermissions(config.ActionsJsonPath)
+	if err != nil {
+		return err
+	}
 	data := make(map[string]interface{})
 	data["Name"] = params.IActionsContract.ContractName
+	data["Errors"] = errorSchemas
+	data["HasRoles"] = hasRoles(permissions)
 	outPath := filepath.Join(config.Out, params.IActionsContract.FileName)
 	return codegen.ExecuteTemplate(actionsTpl, config.ActionsJsonPath, outPath, data, nil)
 }
@@ -44,6 +71,15 @@ func GenerateTables(config Config) error {
 	return codegen.ExecuteTemplate(tablesTpl, config.TablesJsonPath, outPath, data, nil)
 }
 
+// GenerateEvents generates the solidity interface from the events schema.
+// If no events schema is given, the interface is empty.
+func GenerateEvents(config Config) error {
+	data := make(map[string]interface{})
+	data["Name"] = params.IEventsContract.ContractName
+	outPath := filepath.Join(config.Out, params.IEventsContract.FileName)
+	return codegen.ExecuteTemplate(eventsTpl, config.EventsJsonPath, outPath, data, nil)
+}
+
 // GenerateCore generates the core solidity interface.
 func GenerateCore(config Config) error {
 	data := make(map[string]interface{})
@@ -51,29 +87,57 @@ func GenerateCore(config Config) error {
 	data["Imports"] = []string{
 		"./" + params.IActionsContract.FileName,
 		"./" + params.ITablesContract.FileName,
+		"./" + params.IEventsContract.FileName,
 	}
 	data["Interfaces"] = []string{
 		params.IActionsContract.ContractName,
 		params.ITablesContract.ContractName,
+		params.IEventsContract.ContractName,
 	}
 	outPath := filepath.Join(config.Out, params.ICoreContract.FileName)
 	return codegen.ExecuteTemplate(coreTpl, "", outPath, data, nil)
 }
 
+// permissionModifier returns the modifier restricting an action to the accounts allowed by the given
+// permission, or an empty string if the action is open.
+func permissionModifier(permission arch.Permission) string {
+	switch permission.Kind {
+	case arch.PermissionOwner:
+		return "onlyOwner"
+	case arch.PermissionRole:
+		return fmt.Sprintf("onlyRole(keccak256(%q))", permission.Role)
+	default:
+		return ""
+	}
+}
+
 // GenerateEntrypoint generates the entrypoint solidity abstract contract.
+// Actions restricted by a permission are implemented by an internal method guarded by a modifier.
 func GenerateEntrypoint(config Config) error {
+	permissions, err := codegen.LoadPermissions(config.ActionsJsonPath)
+	if err != nil {
+		return err
+	}
 	data := make(map[string]interface{})
 	data["Name"] = params.EntrypointContract.ContractName
 	data["Imports"] = []string{"./" + params.IActionsContract.FileName}
 	data["Interfaces"] = []string{params.IActionsContract.ContractName}
+	data["Permissions"] = permissions
+	data["HasRoles"] = hasRoles(permissions)
+	funcMap := template.FuncMap{"_modifier": permissionModifier}
 	outPath := filepath.Join(config.Out, params.EntrypointContract.FileName)
-	return codegen.ExecuteTemplate(entrypointTpl, config.ActionsJsonPath, outPath, data, nil)
+	return codegen.ExecuteTemplate(entrypointTpl, config.ActionsJsonPath, outPath, data, funcMap)
 }
 
-// GenerateEntrypoint generates the entrypoint solidity abstract contract.
+// GenerateArch generates the arch solidity abstract contract.
 func GenerateArch(config Config) error {
+	permissions, err := codegen.LoadPermissions(config.ActionsJsonPath)
+	if err != nil {
+		return err
+	}
 	data := make(map[string]interface{})
 	data["Name"] = params.ArchContract.ContractName
+	data["HasRoles"] = hasRoles(permissions)
 	data["Imports"] = []string{
 		"./" + params.ICoreContract.FileName,
 		"./" + params.EntrypointContract.FileName,
@@ -101,6 +165,9 @@ func Codegen(config Config) error {
 	if err := GenerateTables(config); err != nil {
 		return errors.New("error generating solidity tables interface: " + err.Error())
 	}
+	if err := GenerateEvents(config); err != nil {
+		return errors.New("error generating solidity events interface: " + err.Error())
+	}
 	if err := GenerateCore(config); err != nil {
 		return errors.New("error generating solidity core interface: " + err.Error())
 	}
@@ -2,7 +2,17 @@
 pragma solidity >=0.8.0;
 
 /* Autogenerated file. Do not edit manually. */
+{{ if $.HasRoles }}
+struct {{ SolidityActionStructNameFn $.ArchParams.GrantRoleActionName }} {
+    bytes32 role;
+    address account;
+}
 
+struct {{ SolidityActionStructNameFn $.ArchParams.RevokeRoleActionName }} {
+    bytes32 role;
+    address account;
+}
+{{ end }}
 {{ range $schema := .Schemas }}
 {{- if $schema.Values }}
 struct {{ SolidityActionStructNameFn $schema.Name }} {
@@ -15,14 +25,26 @@ struct {{ SolidityActionStructNameFn $schema.Name }} {
 
 interface {{$.Name}} {
     event {{$.ArchParams.ActionExecutedEventName}}(bytes4 actionId, bytes data);
+{{- if .Errors }}
+{{ range $schema := .Errors }}
+    error {{ SolidityActionErrorNameFn $schema.Name }}(
+        {{- $length := len $schema.Values -}}
+        {{- range $index, $value := $schema.Values -}}
+        {{- $value.Type.SolType }} {{$value.Name}}{{if lt $index (_sub $length 1)}}, {{ end -}}
+        {{- end -}}
+    );
+{{- end }}
+{{- end }}
 
     function {{ SolidityActionMethodNameFn $.ArchParams.TickActionName }}() external;
+{{ if $.HasRoles }}
+    function {{ SolidityActionMethodNameFn $.ArchParams.GrantRoleActionName }}({{ SolidityActionStructNameFn $.ArchParams.GrantRoleActionName }} memory action) external;
+
+    function {{ SolidityActionMethodNameFn $.ArchParams.RevokeRoleActionName }}({{ SolidityActionStructNameFn $.ArchParams.RevokeRoleActionName }} memory action) external;
 
+    function {{$.ArchParams.HasRoleMethodName}}(bytes32 role, address account) external view returns (bool);
+{{ end }}
 {{ range $schema := .Schemas }}
-    {{- if $schema.Values }}
-    function {{ SolidityActionMethodNameFn $schema.Name }}({{ SolidityActionStructNameFn $schema.Name }} memory action) external;
-    {{- else }}
-    function {{ SolidityActionMethodNameFn $schema.Name }}() external;
-    {{- end }}
+    function {{ SolidityActionMethodNameFn $schema.Name }}({{ _actionParams $schema }}) external;
 {{- end }}
 }
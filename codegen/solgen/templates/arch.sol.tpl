@@ -12,7 +12,15 @@ import {ArchProxyAdmin} from "arch/ArchProxyAdmin.sol";
 import {ArchProxy} from "arch/ArchProxy.sol";
 
 abstract contract {{$.Name}} is {{ range $i, $v := .Interfaces }}{{ if $i }}, {{ end }}{{ $v }}{{ end }} {
-    function initialize(address _logic, bytes memory data) public initializer {
+    constructor() {
+        // Set the owner on deployment so initialize cannot be front-run
+        _setOwner(msg.sender);
+    }
+
+    function initialize(
+        address _logic,
+        bytes memory data
+    ) public onlyOwner initializer {
         address proxyAddress = address(
             new ArchProxy(address(this), _logic, "")
         );
@@ -28,4 +36,26 @@ abstract contract {{$.Name}} is {{ range $i, $v := .Interfaces }}{{ if $i }}, {{
         require(block.number > lastTickBlockNumber, "already ticked");
         ICore(proxy).tick();
     }
+
+    function _checkOwner() internal view override {
+        require(msg.sender == owner, "Arch: caller is not the owner");
+    }
+{{- if $.HasRoles }}
+
+    function {{$.ArchParams.HasRoleMethodName}}(bytes32 role, address account) public view returns (bool) {
+        return ICore(proxy).{{$.ArchParams.HasRoleMethodName}}(role, account);
+    }
+
+    function _checkRole(bytes32 role) internal view override {
+        require({{$.ArchParams.HasRoleMethodName}}(role, msg.sender), "Arch: caller is missing role");
+    }
+
+    function _{{ SolidityActionMethodNameFn $.ArchParams.GrantRoleActionName }}({{ SolidityActionStructNameFn $.ArchParams.GrantRoleActionName }} memory action) internal override {
+        ICore(proxy).{{ SolidityActionMethodNameFn $.ArchParams.GrantRoleActionName }}(action);
+    }
+
+    function _{{ SolidityActionMethodNameFn $.ArchParams.RevokeRoleActionName }}({{ SolidityActionStructNameFn $.ArchParams.RevokeRoleActionName }} memory action) internal override {
+        ICore(proxy).{{ SolidityActionMethodNameFn $.ArchParams.RevokeRoleActionName }}(action);
+    }
+{{- end }}
 }
@@ -8,6 +8,19 @@ import "{{ . }}";
 {{- end }}
 
 abstract contract {{$.Name}} is {{ range $i, $v := .Interfaces }}{{ if $i }}, {{ end }}{{ $v }}{{ end }} {
+    modifier onlyOwner() {
+        _checkOwner();
+        _;
+    }
+
+{{- if $.HasRoles }}
+
+    modifier onlyRole(bytes32 role) {
+        _checkRole(role);
+        _;
+    }
+{{- end }}
+
     function {{$.ArchParams.MultiActionMethodName}}(
         uint32[] memory actionIds,
         uint8[] memory actionCount,
@@ -26,15 +39,29 @@ abstract contract {{$.Name}} is {{ range $i, $v := .Interfaces }}{{ if $i }}, {{
     function _executeAction(uint32 actionId, bytes memory actionData) private {
         if (actionId == {{$.ArchParams.TickActionIdHex}}) {
             {{ SolidityActionMethodNameFn .ArchParams.TickActionName }}();
-        } else 
+        } else
+        {{- if $.HasRoles }} if (actionId == {{$.ArchParams.GrantRoleActionIdHex}}) {
+            {{ SolidityActionStructNameFn $.ArchParams.GrantRoleActionName }} memory action = abi.decode(
+                actionData,
+                ({{ SolidityActionStructNameFn $.ArchParams.GrantRoleActionName }})
+            );
+            {{ SolidityActionMethodNameFn $.ArchParams.GrantRoleActionName }}(action);
+        } else if (actionId == {{$.ArchParams.RevokeRoleActionIdHex}}) {
+            {{ SolidityActionStructNameFn $.ArchParams.RevokeRoleActionName }} memory action = abi.decode(
+                actionData,
+                ({{ SolidityActionStructNameFn $.ArchParams.RevokeRoleActionName }})
+            );
+            {{ SolidityActionMethodNameFn $.ArchParams.RevokeRoleActionName }}(action);
+        } else
+        {{- end }}
         {{- range $schema := .Schemas }}
-        {{- if $schema.Values }}
+        {{- if or $schema.Keys $schema.Values }}
         if (actionId == {{ _actionId $schema }}) {
-            {{ SolidityActionStructNameFn $schema.Name }} memory action = abi.decode(
+            {{ _actionDecl $schema }} = abi.decode(
                 actionData,
-                ({{ SolidityActionStructNameFn $schema.Name }})
+                ({{ _actionTypes $schema }})
             );
-            {{ SolidityActionMethodNameFn $schema.Name }}(action);
+            {{ SolidityActionMethodNameFn $schema.Name }}({{ _actionArgs $schema }});
         }
         {{- else }}
         if (actionId == {{ _actionId $schema }}) {
@@ -45,13 +72,36 @@ abstract contract {{$.Name}} is {{ range $i, $v := .Interfaces }}{{ if $i }}, {{
         }
     }
 
+    function _checkOwner() internal view virtual;
+
     function {{ SolidityActionMethodNameFn $.ArchParams.TickActionName }}() public virtual;
+{{- if $.HasRoles }}
+
+    function _checkRole(bytes32 role) internal view virtual;
+
+    function {{ SolidityActionMethodNameFn $.ArchParams.GrantRoleActionName }}({{ SolidityActionStructNameFn $.ArchParams.GrantRoleActionName }} memory action) public onlyOwner {
+        _{{ SolidityActionMethodNameFn $.ArchParams.GrantRoleActionName }}(action);
+    }
+
+    function _{{ SolidityActionMethodNameFn $.ArchParams.GrantRoleActionName }}({{ SolidityActionStructNameFn $.ArchParams.GrantRoleActionName }} memory action) internal virtual;
+
+    function {{ SolidityActionMethodNameFn $.ArchParams.RevokeRoleActionName }}({{ SolidityActionStructNameFn $.ArchParams.RevokeRoleActionName }} memory action) public onlyOwner {
+        _{{ SolidityActionMethodNameFn $.ArchParams.RevokeRoleActionName }}(action);
+    }
+
+    function _{{ SolidityActionMethodNameFn $.ArchParams.RevokeRoleActionName }}({{ SolidityActionStructNameFn $.ArchParams.RevokeRoleActionName }} memory action) internal virtual;
+{{- end }}
 
     {{- range $schema := .Schemas }}
-    {{ if $schema.Values }}
-    function {{ SolidityActionMethodNameFn .Name }}({{ SolidityActionStructNameFn $schema.Name }} memory action) public virtual;
+    {{- $modifier := _modifier (index $.Permissions $schema.Name) }}
+    {{ if $modifier }}
+    function {{ SolidityActionMethodNameFn .Name }}({{ _actionParams $schema }}) public {{ $modifier }} {
+        _{{ SolidityActionMethodNameFn .Name }}({{ _actionArgs $schema }});
+    }
+
+    function _{{ SolidityActionMethodNameFn .Name }}({{ _actionParams $schema }}) internal virtual;
     {{- else }}
-    function {{ SolidityActionMethodNameFn .Name }}() public virtual;
+    function {{ SolidityActionMethodNameFn .Name }}({{ _actionParams $schema }}) public virtual;
     {{- end }}
     {{- end }}
 }
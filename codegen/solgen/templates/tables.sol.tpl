@@ -22,4 +22,7 @@ interface {{$.Name}} {
         {{- end -}}
     ) external view returns ({{ SolidityTableStructNameFn .Name }} memory);
 {{- end }}
+    function {{$.ArchParams.MultiTableReadMethodName}}(
+        bytes[] calldata calls
+    ) external view returns (bytes[] memory);
 }
@@ -0,0 +1,96 @@
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Artifact is a compiled contract.
+type Artifact struct {
+	ABI      abi.ABI
+	Bytecode []byte
+}
+
+// LoadForgeArtifact loads a compiled contract from a forge build artifact, i.e., a JSON file with the
+// contract ABI and bytecode.
+func LoadForgeArtifact(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var artifact struct {
+		Abi      json.RawMessage `json:"abi"`
+		Bytecode struct {
+			Object string `json:"object"`
+		} `json:"bytecode"`
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, err
+	}
+	if len(artifact.Abi) == 0 {
+		return nil, errors.New("artifact has no ABI")
+	}
+	ABI, err := abi.JSON(strings.NewReader(string(artifact.Abi)))
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(artifact.Bytecode.Object, "0x") {
+		artifact.Bytecode.Object = "0x" + artifact.Bytecode.Object
+	}
+	bytecode, err := hexutil.Decode(artifact.Bytecode.Object)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytecode) == 0 {
+		// Abstract contracts and interfaces have no bytecode
+		return nil, errors.New("artifact has no bytecode")
+	}
+	return &Artifact{ABI: ABI, Bytecode: bytecode}, nil
+}
+
+// proxyAdmin is a game contract bound through its ABI.
+type proxyAdmin struct {
+	contract *bind.BoundContract
+}
+
+var _ InitializableProxyAdmin = (*proxyAdmin)(nil)
+
+func (p *proxyAdmin) Proxy(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	if err := p.contract.Call(opts, &out, "proxy"); err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+func (p *proxyAdmin) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	if err := p.contract.Call(opts, &out, "owner"); err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+func (p *proxyAdmin) Initialize(auth *bind.TransactOpts, logic common.Address, data []byte) (*types.Transaction, error) {
+	return p.contract.Transact(auth, "initialize", logic, data)
+}
+
+// NewArtifactDeployer returns a GameContractDeployer that deploys the game contract in the given artifact.
+// The game contract must be an ArchProxyAdmin.
+func NewArtifactDeployer(artifact *Artifact) GameContractDeployer {
+	return func(auth *bind.TransactOpts, ethcli bind.ContractBackend) (common.Address, *types.Transaction, InitializableProxyAdmin, error) {
+		address, tx, contract, err := bind.DeployContract(auth, artifact.ABI, artifact.Bytecode, ethcli)
+		if err != nil {
+			return common.Address{}, nil, nil, err
+		}
+		return address, tx, &proxyAdmin{contract: contract}, nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	admin_contract "github.com/concrete-eth/archetype/abigen/arch_proxy_admin"
 	"github.com/concrete-eth/archetype/rpc"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -15,6 +16,7 @@ type GameContractDeployer = func(auth *bind.TransactOpts, ethcli bind.ContractBa
 
 type InitializableProxyAdmin interface {
 	Proxy(opts *bind.CallOpts) (common.Address, error)
+	Owner(opts *bind.CallOpts) (common.Address, error)
 	Initialize(auth *bind.TransactOpts, logic common.Address, data []byte) (*types.Transaction, error)
 }
 
@@ -49,6 +51,18 @@ func DeployGame(auth *bind.TransactOpts, ethcli rpc.EthCli, deployer GameContrac
 		return
 	}
 
+	// Game contracts set their owner on deployment, so only the deployer can initialize them.
+	// Contracts built before ownership was added have no owner and must be rebuilt.
+	var owner common.Address
+	if owner, err = proxyAdmin.Owner(nil); err != nil {
+		err = fmt.Errorf("could not get game contract owner, the contract bytecode may be out of date: %w", err)
+		return
+	}
+	if owner != auth.From {
+		err = fmt.Errorf("game contract is owned by %s instead of the deployer %s", owner.Hex(), auth.From.Hex())
+		return
+	}
+
 	rpc.SetNonce(auth, ethcli)
 	tx, err = proxyAdmin.Initialize(auth, logic, data)
 	if err != nil {
@@ -68,3 +82,25 @@ func DeployGame(auth *bind.TransactOpts, ethcli rpc.EthCli, deployer GameContrac
 
 	return gameAddr, coreAddr, nil
 }
+
+// UpgradeGame points the core proxy of the game contract at gameAddr to a new logic address, e.g., a
+// new core precompile, and calls it with data if not empty. Only the owner of the game contract, i.e.,
+// the account that initialized it, can upgrade it.
+func UpgradeGame(auth *bind.TransactOpts, ethcli rpc.EthCli, gameAddr common.Address, logic common.Address, data []byte, commit bool) error {
+	adminContract, err := admin_contract.NewContract(gameAddr, ethcli)
+	if err != nil {
+		return err
+	}
+	rpc.SetNonce(auth, ethcli)
+	tx, err := adminContract.UpgradeProxy(auth, logic, data)
+	if err != nil {
+		return err
+	}
+	if commit {
+		ethcli.(interface{ Commit() }).Commit()
+	}
+	if err := waitForSuccess(ethcli, tx); err != nil {
+		return fmt.Errorf("upgrade game contract failed: %w", err)
+	}
+	return nil
+}
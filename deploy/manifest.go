@@ -0,0 +1,38 @@
+package deploy
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Manifest describes a deployed game.
+type Manifest struct {
+	ChainId uint64         `json:"chainId"`
+	Game    common.Address `json:"game"`  // Game contract, i.e., the proxy admin actions are sent to
+	Proxy   common.Address `json:"proxy"` // Core proxy, which emits action logs and serves table reads
+	Logic   common.Address `json:"logic"` // Core logic, e.g., the core precompile
+}
+
+// WriteManifest writes a deployment manifest as JSON to the given path.
+func WriteManifest(path string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// ReadManifest reads a deployment manifest from the given path.
+func ReadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
@@ -1,11 +1,14 @@
 package e2e
 
 import (
+	"context"
+	"errors"
 	"math/big"
 	"testing"
 	"time"
 
 	"github.com/concrete-eth/archetype/arch"
+	"github.com/concrete-eth/archetype/client"
 	"github.com/concrete-eth/archetype/kvstore"
 	"github.com/concrete-eth/archetype/precompile"
 	"github.com/concrete-eth/archetype/rpc"
@@ -16,6 +19,7 @@ import (
 	"github.com/ethereum/go-ethereum/concrete"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 )
 
 var (
@@ -133,3 +137,40 @@ func TestE2E(t *testing.T) {
 		t.Errorf("expected remote counter to be 1, got %d", remoteCounter)
 	}
 }
+
+func TestE2EResumeFromCheckpoint(t *testing.T) {
+	var (
+		blockTime = 10 * time.Millisecond
+		ethcli    = newTestSimulatedBackend(t)
+		schemas   = testutils.NewTestArchSchemas(t)
+	)
+	ethcli.Commit()
+	ethcli.Commit()
+	header, err := ethcli.HeaderByNumber(context.Background(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		blockHash common.Hash
+		expErr    error
+	}{
+		{"canonical", header.Hash(), nil},
+		{"orphaned", common.Hash{0x01}, client.ErrCheckpointOrphaned},
+	} {
+		kv := kvstore.NewDiskKeyValueStore(memorydb.New())
+		if err := kv.SetCheckpoint(1, tt.blockHash); err != nil {
+			t.Fatal(err)
+		}
+		io := rpc.NewIO(ethcli, blockTime, schemas, newTestTxOpts(t), testPcAddress, testPcAddress, 0, 0)
+		c := io.NewClient(kv, &testutils.Core{})
+		if c.Core().BlockNumber() != 2 {
+			t.Fatalf("%s: expected client to resume at block 2, got %d", tt.name, c.Core().BlockNumber())
+		}
+		if err := c.SyncUntil(3); !errors.Is(err, tt.expErr) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expErr, err)
+		}
+		io.Stop()
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/concrete-eth/archetype/cli"
+	"github.com/concrete-eth/archetype/example/engine"
+)
+
+func main() {
+	// The archetype CLI with the schemas and core of the example game built in
+	cli.ExecuteCmd(cli.NewGameRootCmd(engine.Schemas, engine.NewCore))
+}
@@ -28,7 +28,7 @@ func main() {
 	log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, log.LevelInfo, true)))
 
 	// Create schemas from codegen
-	schemas := arch.ArchSchemas{Actions: archmod.ActionSchemas, Tables: archmod.TableSchemas}
+	schemas := arch.ArchSchemas{Actions: archmod.ActionSchemas, Tables: archmod.TableSchemas, Events: archmod.EventSchemas}
 
 	// Create precompile
 	pc := precompile.NewCorePrecompile(schemas, func() arch.Core { return &physics.Core{} })
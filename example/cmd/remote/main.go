@@ -45,7 +45,7 @@ func main() {
 	rpc.SetNonce(auth, ethcli)
 
 	// Create schemas from codegen
-	schemas := arch.ArchSchemas{Actions: archmod.ActionSchemas, Tables: archmod.TableSchemas}
+	schemas := arch.ArchSchemas{Actions: archmod.ActionSchemas, Tables: archmod.TableSchemas, Events: archmod.EventSchemas}
 
 	// Create chain IO
 	var (
@@ -17,16 +17,26 @@ import (
 
 const (
 	SnapshotNamespace = "arch"
+	GameName          = "physics"
 )
 
+var Schemas = arch.ArchSchemas{Actions: archmod.ActionSchemas, Tables: archmod.TableSchemas, Events: archmod.EventSchemas}
+
+func NewCore() arch.Core {
+	return &physics.Core{}
+}
+
 func NewRegistry() concrete.PrecompileRegistry {
-	schemas := arch.ArchSchemas{Actions: archmod.ActionSchemas, Tables: archmod.TableSchemas}
-	pc := precompile.NewCorePrecompile(schemas, func() arch.Core { return &physics.Core{} })
+	builder := precompile.NewRegistryBuilder()
+	if err := builder.RegisterGame(GameName, Schemas, NewCore); err != nil {
+		panic(err)
+	}
 	address := common.HexToAddress("0x80")
 	startingBlock := uint64(0)
-	registry := concrete.NewRegistry()
-	registry.AddPrecompile(startingBlock, address, pc)
-	return registry
+	if err := builder.AddPrecompile(GameName, address, startingBlock); err != nil {
+		panic(err)
+	}
+	return builder.Build()
 }
 
 func SnapshotWriterConstructor(ethereum *eth.Ethereum) rpc.API {
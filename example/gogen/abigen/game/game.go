@@ -40,7 +40,7 @@ type ActionDataAddBody struct {
 
 // ContractMetaData contains all meta data concerning the Contract contract.
 var ContractMetaData = &bind.MetaData{
-	ABI: "[{\"type\":\"fallback\",\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"addBody\",\"inputs\":[{\"name\":\"action\",\"type\":\"tuple\",\"internalType\":\"structActionData_AddBody\",\"components\":[{\"name\":\"x\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"y\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"r\",\"type\":\"uint32\",\"internalType\":\"uint32\"},{\"name\":\"vx\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"vy\",\"type\":\"int32\",\"internalType\":\"int32\"}]}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"executeMultipleActions\",\"inputs\":[{\"name\":\"actionIds\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"actionCount\",\"type\":\"uint8[]\",\"internalType\":\"uint8[]\"},{\"name\":\"actionData\",\"type\":\"bytes[]\",\"internalType\":\"bytes[]\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"initialize\",\"inputs\":[{\"name\":\"_logic\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"data\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"lastTickBlockNumber\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"proxy\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"tick\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"event\",\"name\":\"ActionExecuted\",\"inputs\":[{\"name\":\"actionId\",\"type\":\"bytes4\",\"indexed\":false,\"internalType\":\"bytes4\"},{\"name\":\"data\",\"type\":\"bytes\",\"indexed\":false,\"internalType\":\"bytes\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Initialized\",\"inputs\":[{\"name\":\"version\",\"type\":\"uint64\",\"indexed\":false,\"internalType\":\"uint64\"}],\"anonymous\":false},{\"type\":\"error\",\"name\":\"InvalidInitialization\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"NotInitializing\",\"inputs\":[]}]",
+	ABI: "[{\"type\":\"fallback\",\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"addBody\",\"inputs\":[{\"name\":\"action\",\"type\":\"tuple\",\"internalType\":\"structActionData_AddBody\",\"components\":[{\"name\":\"x\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"y\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"r\",\"type\":\"uint32\",\"internalType\":\"uint32\"},{\"name\":\"vx\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"vy\",\"type\":\"int32\",\"internalType\":\"int32\"}]}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"executeMultipleActions\",\"inputs\":[{\"name\":\"actionIds\",\"type\":\"uint32[]\",\"internalType\":\"uint32[]\"},{\"name\":\"actionCount\",\"type\":\"uint8[]\",\"internalType\":\"uint8[]\"},{\"name\":\"actionData\",\"type\":\"bytes[]\",\"internalType\":\"bytes[]\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"initialize\",\"inputs\":[{\"name\":\"_logic\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"data\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"lastTickBlockNumber\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"uint256\",\"internalType\":\"uint256\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"owner\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"proxy\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"tick\",\"inputs\":[],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"function\",\"name\":\"upgradeProxy\",\"inputs\":[{\"name\":\"newLogic\",\"type\":\"address\",\"internalType\":\"address\"},{\"name\":\"data\",\"type\":\"bytes\",\"internalType\":\"bytes\"}],\"outputs\":[],\"stateMutability\":\"nonpayable\"},{\"type\":\"event\",\"name\":\"ActionExecuted\",\"inputs\":[{\"name\":\"actionId\",\"type\":\"bytes4\",\"indexed\":false,\"internalType\":\"bytes4\"},{\"name\":\"data\",\"type\":\"bytes\",\"indexed\":false,\"internalType\":\"bytes\"}],\"anonymous\":false},{\"type\":\"event\",\"name\":\"Initialized\",\"inputs\":[{\"name\":\"version\",\"type\":\"uint64\",\"indexed\":false,\"internalType\":\"uint64\"}],\"anonymous\":false},{\"type\":\"error\",\"name\":\"InvalidInitialization\",\"inputs\":[]},{\"type\":\"error\",\"name\":\"NotInitializing\",\"inputs\":[]}]",
 	Bin: "0x6080604052348015600f57600080fd5b506111cf8061001f6000396000f3fe608060405234801561001057600080fd5b50600436106100625760003560e01c806322c5eafe1461006c5780633eaf5d9f1461007f578063d0b3617114610087578063d1f578941461009a578063ec556889146100ad578063ff280198146100dd575b61006a6100f4565b005b61006a61007a3660046107d4565b610169565b61006a6101ce565b61006a6100953660046109e1565b61026b565b61006a6100a8366004610ac7565b61030a565b6000546100c0906001600160a01b031681565b6040516001600160a01b0390911681526020015b60405180910390f35b6100e660015481565b6040519081526020016100d4565b6000546001600160a01b03166101515760405162461bcd60e51b815260206004820152601d60248201527f4172636850726f787941646d696e3a2070726f7879206e6f742073657400000060448201526064015b60405180910390fd5b600054610166906001600160a01b031661047a565b50565b600054604051631162f57f60e11b81526001600160a01b03909116906322c5eafe90610199908490600401610b23565b600060405180830381600087803b1580156101b357600080fd5b505af11580156101c7573d6000803e3d6000fd5b5050505050565b60015443116102105760405162461bcd60e51b815260206004820152600e60248201526d185b1c9958591e481d1a58dad95960921b6044820152606401610148565b6000805460408051633eaf5d9f60e01b815290516001600160a01b0390921692633eaf5d9f9260048084019382900301818387803b15801561025157600080fd5b505af1158015610265573d6000803e3d6000fd5b50505050565b6000805b84518110156101c757600084828151811061028c5761028c610b6f565b602002602001015160ff16905060008390505b6102a98285610b9b565b8110156102f4576102ec8784815181106102c5576102c5610b6f565b60200260200101518683815181106102df576102df610b6f565b60200260200101516104a0565b60010161029f565b506102ff8184610b9b565b92505060010161026f565b7ff0c57e16840df040f15088dc2f81fe391c3923bec73e23a9662efc9c229c6a008054600160401b810460ff16159067ffffffffffffffff166000811580156103505750825b905060008267ffffffffffffffff16600114801561036d5750303b155b90508115801561037b575080155b156103995760405163f92ee8a960e01b815260040160405180910390fd5b845467ffffffffffffffff1916600117855583156103c357845460ff60401b1916600160401b1785555b600030886040516103d390610736565b6001600160a01b03928316815291166020820152606060408201819052600090820152608001604051809103906000f080158015610415573d6000803e3d6000fd5b5090506104218161053c565b61042a87610625565b50831561047157845460ff60401b19168555604051600181527fc7f505b2f371ae2175ee4913f4499e1f2633a7b5936321eed1cdaeb6115181d29060200160405180910390a15b50505050505050565b60603660008037600080366000855afa3d6000803e80801561049b573d6000f35b3d6000fd5b8163ffffffff16633eaf5d9f036104bd576104b96101ce565b5050565b8163ffffffff166322c5eafe036104f4576000818060200190518101906104e49190610bb4565b90506104ef81610169565b505050565b60405162461bcd60e51b815260206004820152601d60248201527f456e747279706f696e743a20496e76616c696420616374696f6e2049440000006044820152606401610148565b6001600160a01b0381166105a05760405162461bcd60e51b815260206004820152602560248201527f4172636850726f787941646d696e3a20696e76616c69642070726f7879206164604482015264647265737360d81b6064820152608401610148565b6000546001600160a01b0316156106035760405162461bcd60e51b815260206004820152602160248201527f4172636850726f787941646d696e3a2070726f787920616c72656164792073656044820152601d60fa1b6064820152608401610148565b600080546001600160a01b0319166001600160a01b0392909216919091179055565b61063f60008061063760646006610c22565b60008061069c565b61067161064f6064603b19610c22565b600061065d60646002610c22565b600061066c6064600319610c22565b61069c565b6101666106806064603c610c22565b600061068e60646002610c22565b600061066c60646004610c22565b6000546040805160a081018252600388810b825287810b602083015263ffffffff87168284015285810b606083015284900b60808201529051631162f57f60e11b81526001600160a01b03909216916322c5eafe916106fd91600401610b23565b600060405180830381600087803b15801561071757600080fd5b505af115801561072b573d6000803e3d6000fd5b505050505050505050565b61055080610c4a83390190565b634e487b7160e01b600052604160045260246000fd5b60405160a0810167ffffffffffffffff8111828210171561077c5761077c610743565b60405290565b604051601f8201601f1916810167ffffffffffffffff811182821017156107ab576107ab610743565b604052919050565b8060030b811461016657600080fd5b63ffffffff8116811461016657600080fd5b600060a082840312156107e657600080fd5b6107ee610759565b82356107f9816107b3565b81526020830135610809816107b3565b6020820152604083013561081c816107c2565b6040820152606083013561082f816107b3565b60608201526080830135610842816107b3565b60808201529392505050565b600067ffffffffffffffff82111561086857610868610743565b5060051b60200190565b600082601f83011261088357600080fd5b813560206108986108938361084e565b610782565b8083825260208201915060208460051b8701019350868411156108ba57600080fd5b602086015b848110156108e657803560ff811681146108d95760008081fd5b83529183019183016108bf565b509695505050505050565b600082601f83011261090257600080fd5b813567ffffffffffffffff81111561091c5761091c610743565b61092f601f8201601f1916602001610782565b81815284602083860101111561094457600080fd5b816020850160208301376000918101602001919091529392505050565b600082601f83011261097257600080fd5b813560206109826108938361084e565b82815260059290921b840181019181810190868411156109a157600080fd5b8286015b848110156108e657803567ffffffffffffffff8111156109c55760008081fd5b6109d38986838b01016108f1565b8452509183019183016109a5565b6000806000606084860312156109f657600080fd5b833567ffffffffffffffff80821115610a0e57600080fd5b818601915086601f830112610a2257600080fd5b81356020610a326108938361084e565b82815260059290921b8401810191818101908a841115610a5157600080fd5b948201945b83861015610a78578535610a69816107c2565b82529482019490820190610a56565b97505087013592505080821115610a8e57600080fd5b610a9a87838801610872565b93506040860135915080821115610ab057600080fd5b50610abd86828701610961565b9150509250925092565b60008060408385031215610ada57600080fd5b82356001600160a01b0381168114610af157600080fd5b9150602083013567ffffffffffffffff811115610b0d57600080fd5b610b19858286016108f1565b9150509250929050565b600060a082019050825160030b8252602083015160030b602083015263ffffffff6040840151166040830152606083015160030b6060830152608083015160030b608083015292915050565b634e487b7160e01b600052603260045260246000fd5b634e487b7160e01b600052601160045260246000fd5b80820180821115610bae57610bae610b85565b92915050565b600060a08284031215610bc657600080fd5b610bce610759565b8251610bd9816107b3565b81526020830151610be9816107b3565b60208201526040830151610bfc816107c2565b60408201526060830151610c0f816107b3565b60608201526080830151610842816107b3565b60008260030b8260030b028060030b9150808214610c4257610c42610b85565b509291505056fe60806040526040516105503803806105508339810160408190526100229161030d565b818161002e8282610042565b5061003a9050836100a1565b5050506103f9565b61004b8261010f565b6040516001600160a01b038316907fbc7cd75a20ee27fd9adebab32041f755214dbc6bffa90cc0225b39da2e5c2d3b90600090a2805115610095576100908282610153565b505050565b61009d6101ca565b5050565b7f7e644d79422f17c01e4894b5f4f588d331ebfa28653d42ae832dc59e38c9798f6100e1600080516020610530833981519152546001600160a01b031690565b604080516001600160a01b03928316815291841660208301520160405180910390a161010c816101eb565b50565b807f360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc5b80546001600160a01b0319166001600160a01b039290921691909117905550565b6060600080846001600160a01b03168460405161017091906103dd565b600060405180830381855af49150503d80600081146101ab576040519150601f19603f3d011682016040523d82523d6000602084013e6101b0565b606091505b5090925090506101c185838361022f565b95945050505050565b34156101e95760405163b398979f60e01b815260040160405180910390fd5b565b6001600160a01b03811661021a57604051633173bdd160e11b8152600060048201526024015b60405180910390fd5b80600080516020610530833981519152610132565b6060826102445761023f8261028e565b610287565b815115801561025b57506001600160a01b0384163b155b1561028457604051639996b31560e01b81526001600160a01b0385166004820152602401610211565b50805b9392505050565b80511561029e5780518082602001fd5b604051630a12f52160e11b815260040160405180910390fd5b80516001600160a01b03811681146102ce57600080fd5b919050565b634e487b7160e01b600052604160045260246000fd5b60005b838110156103045781810151838201526020016102ec565b50506000910152565b60008060006060848603121561032257600080fd5b61032b846102b7565b9250610339602085016102b7565b60408501519092506001600160401b038082111561035657600080fd5b818601915086601f83011261036a57600080fd5b81518181111561037c5761037c6102d3565b604051601f8201601f19908116603f011681019083821181831017156103a4576103a46102d3565b816040528281528960208487010111156103bd57600080fd5b6103ce8360208301602088016102e9565b80955050505050509250925092565b600082516103ef8184602087016102e9565b9190910192915050565b610128806104086000396000f3fe608060405233301480602757506012603a565b6001600160a01b0316336001600160a01b0316145b156033576031606d565b005b603130607b565b60007fb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d61035b546001600160a01b0316919050565b6079607560a0565b60ad565b565b60603660008037600080366000855afa3d6000803e808015609b573d6000f35b3d6000fd5b600060a860cb565b905090565b3660008037600080366000845af43d6000803e808015609b573d6000f35b60007f360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc605e56fea264697066735822122080fba3c7bee25cb2cde5ad6be21f260b752c8d5921f88a4ef4f96c6f73b8f06464736f6c63430008190033b53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103a264697066735822122056e469a9fa2f9c76d79de6ffcb80a5518bd7df0c908d57b19fbba6623c96475764736f6c63430008190033",
 }
 
@@ -242,6 +242,37 @@ func (_Contract *ContractCallerSession) LastTickBlockNumber() (*big.Int, error)
 	return _Contract.Contract.LastTickBlockNumber(&_Contract.CallOpts)
 }
 
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_Contract *ContractCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_Contract *ContractSession) Owner() (common.Address, error) {
+	return _Contract.Contract.Owner(&_Contract.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_Contract *ContractCallerSession) Owner() (common.Address, error) {
+	return _Contract.Contract.Owner(&_Contract.CallOpts)
+}
+
 // Proxy is a free data retrieval call binding the contract method 0xec556889.
 //
 // Solidity: function proxy() view returns(address)
@@ -357,6 +388,27 @@ func (_Contract *ContractTransactorSession) Tick() (*types.Transaction, error) {
 	return _Contract.Contract.Tick(&_Contract.TransactOpts)
 }
 
+// UpgradeProxy is a paid mutator transaction binding the contract method 0x1f8ab9ff.
+//
+// Solidity: function upgradeProxy(address newLogic, bytes data) returns()
+func (_Contract *ContractTransactor) UpgradeProxy(opts *bind.TransactOpts, newLogic common.Address, data []byte) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "upgradeProxy", newLogic, data)
+}
+
+// UpgradeProxy is a paid mutator transaction binding the contract method 0x1f8ab9ff.
+//
+// Solidity: function upgradeProxy(address newLogic, bytes data) returns()
+func (_Contract *ContractSession) UpgradeProxy(newLogic common.Address, data []byte) (*types.Transaction, error) {
+	return _Contract.Contract.UpgradeProxy(&_Contract.TransactOpts, newLogic, data)
+}
+
+// UpgradeProxy is a paid mutator transaction binding the contract method 0x1f8ab9ff.
+//
+// Solidity: function upgradeProxy(address newLogic, bytes data) returns()
+func (_Contract *ContractTransactorSession) UpgradeProxy(newLogic common.Address, data []byte) (*types.Transaction, error) {
+	return _Contract.Contract.UpgradeProxy(&_Contract.TransactOpts, newLogic, data)
+}
+
 // Fallback is a paid mutator transaction binding the contract fallback function.
 //
 // Solidity: fallback() returns()
@@ -48,7 +48,7 @@ type RowDataMeta struct {
 
 // ContractMetaData contains all meta data concerning the Contract contract.
 var ContractMetaData = &bind.MetaData{
-	ABI: "[{\"type\":\"function\",\"name\":\"getBodiesRow\",\"inputs\":[{\"name\":\"bodyId\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structRowData_Bodies\",\"components\":[{\"name\":\"x\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"y\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"r\",\"type\":\"uint32\",\"internalType\":\"uint32\"},{\"name\":\"vx\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"vy\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"ax\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"ay\",\"type\":\"int32\",\"internalType\":\"int32\"}]}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getMetaRow\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structRowData_Meta\",\"components\":[{\"name\":\"maxBodyCount\",\"type\":\"uint8\",\"internalType\":\"uint8\"},{\"name\":\"bodyCount\",\"type\":\"uint8\",\"internalType\":\"uint8\"}]}],\"stateMutability\":\"view\"}]",
+	ABI: "[{\"type\":\"function\",\"name\":\"getBodiesRow\",\"inputs\":[{\"name\":\"bodyId\",\"type\":\"uint8\",\"internalType\":\"uint8\"}],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structRowData_Bodies\",\"components\":[{\"name\":\"x\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"y\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"r\",\"type\":\"uint32\",\"internalType\":\"uint32\"},{\"name\":\"vx\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"vy\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"ax\",\"type\":\"int32\",\"internalType\":\"int32\"},{\"name\":\"ay\",\"type\":\"int32\",\"internalType\":\"int32\"}]}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"getMetaRow\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structRowData_Meta\",\"components\":[{\"name\":\"maxBodyCount\",\"type\":\"uint8\",\"internalType\":\"uint8\"},{\"name\":\"bodyCount\",\"type\":\"uint8\",\"internalType\":\"uint8\"}]}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"readMultipleTables\",\"inputs\":[{\"name\":\"calls\",\"type\":\"bytes[]\",\"internalType\":\"bytes[]\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes[]\",\"internalType\":\"bytes[]\"}],\"stateMutability\":\"view\"}]",
 }
 
 // ContractABI is the input ABI used to generate the binding from.
@@ -258,3 +258,34 @@ func (_Contract *ContractSession) GetMetaRow() (RowDataMeta, error) {
 func (_Contract *ContractCallerSession) GetMetaRow() (RowDataMeta, error) {
 	return _Contract.Contract.GetMetaRow(&_Contract.CallOpts)
 }
+
+// ReadMultipleTables is a free data retrieval call binding the contract method 0x988f7366.
+//
+// Solidity: function readMultipleTables(bytes[] calls) view returns(bytes[])
+func (_Contract *ContractCaller) ReadMultipleTables(opts *bind.CallOpts, calls [][]byte) ([][]byte, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "readMultipleTables", calls)
+
+	if err != nil {
+		return *new([][]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([][]byte)).(*[][]byte)
+
+	return out0, err
+
+}
+
+// ReadMultipleTables is a free data retrieval call binding the contract method 0x988f7366.
+//
+// Solidity: function readMultipleTables(bytes[] calls) view returns(bytes[])
+func (_Contract *ContractSession) ReadMultipleTables(calls [][]byte) ([][]byte, error) {
+	return _Contract.Contract.ReadMultipleTables(&_Contract.CallOpts, calls)
+}
+
+// ReadMultipleTables is a free data retrieval call binding the contract method 0x988f7366.
+//
+// Solidity: function readMultipleTables(bytes[] calls) view returns(bytes[])
+func (_Contract *ContractCallerSession) ReadMultipleTables(calls [][]byte) ([][]byte, error) {
+	return _Contract.Contract.ReadMultipleTables(&_Contract.CallOpts, calls)
+}
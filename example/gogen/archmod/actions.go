@@ -35,9 +35,47 @@ func init() {
 	if ActionSchemas, err = arch.NewActionSchemasFromRaw(ActionsABIJson, ActionSchemasJson, types); err != nil {
 		panic(err)
 	}
+	errorTypes := map[string]reflect.Type{}
+	if err = ActionSchemas.RegisterErrors(errorTypes); err != nil {
+		panic(err)
+	}
+	ActionSchemas.SetActionDispatcher(ActionDispatcher{})
 }
 
-type IActions interface {
+// ActionHandler is implemented by cores that execute every action.
+type ActionHandler interface {
 	AddBody(action *ActionData_AddBody) error
+}
+
+type IActions interface {
+	ActionHandler
 	Tick()
 }
+
+// ActionDispatcher matches and executes the actions of this package without reflection.
+type ActionDispatcher struct{}
+
+var _ arch.ActionDispatcher = ActionDispatcher{}
+
+// ActionName returns the schema name of the action, or false if it is not an action of this package.
+func (ActionDispatcher) ActionName(action arch.Action) (string, bool) {
+	switch action.(type) {
+	case *ActionData_AddBody:
+		return "AddBody", true
+	}
+	return "", false
+}
+
+// DispatchAction executes the action on the target if it is an action of this package and the target
+// implements ActionHandler.
+func (ActionDispatcher) DispatchAction(action arch.Action, target arch.Core) (bool, error) {
+	handler, ok := target.(ActionHandler)
+	if !ok {
+		return false, nil
+	}
+	switch action := action.(type) {
+	case *ActionData_AddBody:
+		return true, handler.AddBody(action)
+	}
+	return false, nil
+}
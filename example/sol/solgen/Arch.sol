@@ -11,7 +11,15 @@ import {ArchProxyAdmin} from "arch/ArchProxyAdmin.sol";
 import {ArchProxy} from "arch/ArchProxy.sol";
 
 abstract contract Arch is Entrypoint, ArchProxyAdmin, Initializable {
-    function initialize(address _logic, bytes memory data) public initializer {
+    constructor() {
+        // Set the owner on deployment so initialize cannot be front-run
+        _setOwner(msg.sender);
+    }
+
+    function initialize(
+        address _logic,
+        bytes memory data
+    ) public onlyOwner initializer {
         address proxyAddress = address(
             new ArchProxy(address(this), _logic, "")
         );
@@ -27,4 +35,8 @@ abstract contract Arch is Entrypoint, ArchProxyAdmin, Initializable {
         require(block.number > lastTickBlockNumber, "already ticked");
         ICore(proxy).tick();
     }
+
+    function _checkOwner() internal view override {
+        require(msg.sender == owner, "Arch: caller is not the owner");
+    }
 }
@@ -23,4 +23,7 @@ interface ITables {
     function getBodiesRow(
         uint8 bodyId
     ) external view returns (RowData_Bodies memory);
+    function readMultipleTables(
+        bytes[] calldata calls
+    ) external view returns (bytes[] memory);
 }
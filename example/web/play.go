@@ -190,7 +190,7 @@ func runGameClient(params URLParams, privateKeyHex string) {
 
 	// Create chain IO
 	var (
-		schemas             = arch.ArchSchemas{Actions: archmod.ActionSchemas, Tables: archmod.TableSchemas}
+		schemas             = arch.ArchSchemas{Actions: archmod.ActionSchemas, Tables: archmod.TableSchemas, Events: archmod.EventSchemas}
 		blockTime           = params.BlockTime
 		startingBlockNumber = uint64(0) // TODO
 	)
@@ -7,6 +7,7 @@ require (
 	github.com/fatih/color v1.14.1
 	github.com/hajimehoshi/ebiten/v2 v2.7.1
 	github.com/holiman/uint256 v1.2.4
+	github.com/iancoleman/orderedmap v0.3.0
 	github.com/naoina/toml v0.1.2-0.20170918210437-9fafd6967416
 	github.com/spf13/cobra v1.5.0
 	github.com/spf13/viper v1.18.2
@@ -67,7 +68,6 @@ require (
 	github.com/holiman/billy v0.0.0-20240216141850-2abb0c79d3c4 // indirect
 	github.com/holiman/bloomfilter/v2 v2.0.3 // indirect
 	github.com/huin/goupnp v1.3.0 // indirect
-	github.com/iancoleman/orderedmap v0.3.0 // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/influxdata/influxdb-client-go/v2 v2.4.0 // indirect
 	github.com/influxdata/influxdb1-client v0.0.0-20220302092344-a9ab5670611c // indirect
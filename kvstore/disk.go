@@ -0,0 +1,136 @@
+package kvstore
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete/lib"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+var (
+	DiskValuePrefix   = []byte("arch-kv-value-")
+	DiskCheckpointKey = []byte("arch-kv-checkpoint")
+)
+
+var ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+
+// Checkpointer is implemented by key-value stores that record the last block applied to them.
+type Checkpointer interface {
+	// Checkpoint returns the number and hash of the last block applied to the store, if any.
+	Checkpoint() (blockNumber uint64, blockHash common.Hash, ok bool)
+	// SetCheckpoint persists all writes and records the given block as the last one applied to the store.
+	SetCheckpoint(blockNumber uint64, blockHash common.Hash) error
+}
+
+// DiskKeyValueStore is a persistent key-value store backed by a go-ethereum key-value database.
+// Writes are buffered in memory until Flush or SetCheckpoint is called, so the values of a block
+// and its checkpoint are written atomically.
+type DiskKeyValueStore struct {
+	db      ethdb.KeyValueStore
+	pending map[common.Hash]common.Hash
+}
+
+var (
+	_ lib.KeyValueStore = (*DiskKeyValueStore)(nil)
+	_ Checkpointer      = (*DiskKeyValueStore)(nil)
+)
+
+// NewDiskKeyValueStore creates a new DiskKeyValueStore on top of the given database.
+func NewDiskKeyValueStore(db ethdb.KeyValueStore) *DiskKeyValueStore {
+	return &DiskKeyValueStore{
+		db:      db,
+		pending: make(map[common.Hash]common.Hash),
+	}
+}
+
+func diskValueKey(key common.Hash) []byte {
+	return append(append([]byte{}, DiskValuePrefix...), key.Bytes()...)
+}
+
+// Set buffers a key-value pair to be written to the database on the next flush.
+func (kv *DiskKeyValueStore) Set(key common.Hash, value common.Hash) {
+	kv.pending[key] = value
+}
+
+// Get gets the value for a key from the write buffer or the database.
+func (kv *DiskKeyValueStore) Get(key common.Hash) common.Hash {
+	if v, ok := kv.pending[key]; ok {
+		return v
+	}
+	data, err := kv.db.Get(diskValueKey(key))
+	if err != nil {
+		// Missing keys hold the zero value
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// writePending adds the buffered writes to the given batch.
+// Zero values are deleted instead of written.
+func (kv *DiskKeyValueStore) writePending(batch ethdb.Batch) error {
+	for key, value := range kv.pending {
+		var err error
+		if value == (common.Hash{}) {
+			err = batch.Delete(diskValueKey(key))
+		} else {
+			err = batch.Put(diskValueKey(key), value.Bytes())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes the buffered key-value pairs to the database.
+func (kv *DiskKeyValueStore) Flush() error {
+	batch := kv.db.NewBatch()
+	if err := kv.writePending(batch); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	kv.pending = make(map[common.Hash]common.Hash)
+	return nil
+}
+
+// Checkpoint returns the number and hash of the last block recorded with SetCheckpoint.
+func (kv *DiskKeyValueStore) Checkpoint() (uint64, common.Hash, bool) {
+	data, err := kv.db.Get(DiskCheckpointKey)
+	if err != nil || len(data) != 8+common.HashLength {
+		return 0, common.Hash{}, false
+	}
+	blockNumber := binary.BigEndian.Uint64(data[:8])
+	blockHash := common.BytesToHash(data[8:])
+	return blockNumber, blockHash, true
+}
+
+// SetCheckpoint writes the buffered key-value pairs and the given checkpoint to the database atomically.
+func (kv *DiskKeyValueStore) SetCheckpoint(blockNumber uint64, blockHash common.Hash) error {
+	batch := kv.db.NewBatch()
+	if err := kv.writePending(batch); err != nil {
+		return err
+	}
+	data := make([]byte, 8+common.HashLength)
+	binary.BigEndian.PutUint64(data[:8], blockNumber)
+	copy(data[8:], blockHash.Bytes())
+	if err := batch.Put(DiskCheckpointKey, data); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	kv.pending = make(map[common.Hash]common.Hash)
+	return nil
+}
+
+// Close flushes the buffered writes and closes the underlying database.
+func (kv *DiskKeyValueStore) Close() error {
+	if err := kv.Flush(); err != nil {
+		return err
+	}
+	return kv.db.Close()
+}
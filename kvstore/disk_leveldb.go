@@ -0,0 +1,22 @@
+//go:build !js
+// +build !js
+
+package kvstore
+
+import (
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+)
+
+const (
+	diskCacheSize = 16 // Cache size in MiB
+	diskHandles   = 16 // Number of open file handles
+)
+
+// OpenDiskKeyValueStore opens or creates a leveldb backed DiskKeyValueStore at the given path.
+func OpenDiskKeyValueStore(path string) (*DiskKeyValueStore, error) {
+	db, err := leveldb.New(path, diskCacheSize, diskHandles, "", false)
+	if err != nil {
+		return nil, err
+	}
+	return NewDiskKeyValueStore(db), nil
+}
@@ -200,6 +200,15 @@ func (s *StagedKeyValueStore) Get(key common.Hash) common.Hash {
 	return s.kv.Get(key)
 }
 
+// ForEachStaged calls forEach for every staged key-value pair, in no particular order, until it returns false.
+func (s *StagedKeyValueStore) ForEachStaged(forEach func(key, value common.Hash) bool) {
+	for key, value := range s.staged {
+		if !forEach(key, value) {
+			return
+		}
+	}
+}
+
 // Commit writes the staged key-value pairs to the underlying store.
 func (s *StagedKeyValueStore) Commit() {
 	for key, value := range s.staged {
@@ -212,3 +221,56 @@ func (s *StagedKeyValueStore) Commit() {
 func (s *StagedKeyValueStore) Revert() {
 	s.staged = make(map[common.Hash]common.Hash)
 }
+
+// CommitWithJournal writes the staged key-value pairs to the underlying store and returns a journal
+// holding the overwritten values, which can be used to undo the commit.
+func (s *StagedKeyValueStore) CommitWithJournal() *Journal {
+	journal := NewJournal()
+	for key, value := range s.staged {
+		journal.record(key, s.kv.Get(key))
+		s.kv.Set(key, value)
+	}
+	s.staged = make(map[common.Hash]common.Hash)
+	return journal
+}
+
+// Journal holds the values overwritten by a commit to a key-value store.
+type Journal struct {
+	prev map[common.Hash]common.Hash
+}
+
+// NewJournal creates a new empty Journal.
+func NewJournal() *Journal {
+	return &Journal{
+		prev: make(map[common.Hash]common.Hash),
+	}
+}
+
+func (j *Journal) record(key, prevValue common.Hash) {
+	if _, ok := j.prev[key]; ok {
+		// Keep the oldest value
+		return
+	}
+	j.prev[key] = prevValue
+}
+
+// Len returns the number of keys in the journal.
+func (j *Journal) Len() int {
+	return len(j.prev)
+}
+
+// Keys returns the keys written by the commit, in no particular order.
+func (j *Journal) Keys() []common.Hash {
+	keys := make([]common.Hash, 0, len(j.prev))
+	for key := range j.prev {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Revert writes the overwritten values back to the given store.
+func (j *Journal) Revert(kv lib.KeyValueStore) {
+	for key, value := range j.prev {
+		kv.Set(key, value)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/concrete/crypto"
 	"github.com/ethereum/go-ethereum/concrete/lib"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 )
 
 func testKeyValueStore(t *testing.T, kv lib.KeyValueStore) {
@@ -118,6 +119,89 @@ func TestStagedKeyValueStore(t *testing.T) {
 	}
 }
 
+func TestStagedKeyValueStoreJournal(t *testing.T) {
+	baseKv := NewMemoryKeyValueStore()
+	stagedKv := NewStagedKeyValueStore(baseKv)
+
+	var (
+		key0 = common.BytesToHash([]byte("foo"))
+		key1 = common.BytesToHash([]byte("bar"))
+		val0 = common.BytesToHash([]byte("foo"))
+		val1 = common.BytesToHash([]byte("bar"))
+	)
+
+	baseKv.Set(key0, val0)
+
+	stagedKv.Set(key0, val1)
+	stagedKv.Set(key1, val1)
+	journal := stagedKv.CommitWithJournal()
+	if journal.Len() != 2 {
+		t.Fatalf("expected %v, got %v", 2, journal.Len())
+	}
+	if baseKv.Get(key0) != val1 || baseKv.Get(key1) != val1 {
+		t.Fatal("expected staged values to be committed")
+	}
+
+	journal.Revert(baseKv)
+	if baseKv.Get(key0) != val0 {
+		t.Errorf("expected %v, got %v", val0, baseKv.Get(key0))
+	}
+	if baseKv.Get(key1) != (common.Hash{}) {
+		t.Errorf("expected %v, got %v", common.Hash{}, baseKv.Get(key1))
+	}
+}
+
+func TestDiskKeyValueStore(t *testing.T) {
+	testKeyValueStore(t, NewDiskKeyValueStore(memorydb.New()))
+
+	var (
+		db        = memorydb.New()
+		kv        = NewDiskKeyValueStore(db)
+		key0      = common.BytesToHash([]byte("foo"))
+		key1      = common.BytesToHash([]byte("bar"))
+		val0      = common.BytesToHash([]byte("foo"))
+		blockHash = common.BytesToHash([]byte("block"))
+	)
+
+	if _, _, ok := kv.Checkpoint(); ok {
+		t.Fatal("expected no checkpoint")
+	}
+
+	kv.Set(key0, val0)
+	kv.Set(key1, val0)
+	if db.Len() != 0 {
+		t.Fatal("expected writes to be buffered")
+	}
+	if err := kv.SetCheckpoint(5, blockHash); err != nil {
+		t.Fatal(err)
+	}
+
+	// Zero values are deleted from the database
+	kv.Set(key1, common.Hash{})
+	if err := kv.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Values and checkpoint persist across stores on the same database
+	kv = NewDiskKeyValueStore(db)
+	if kv.Get(key0) != val0 {
+		t.Errorf("expected %v, got %v", val0, kv.Get(key0))
+	}
+	if kv.Get(key1) != (common.Hash{}) {
+		t.Errorf("expected %v, got %v", common.Hash{}, kv.Get(key1))
+	}
+	if has, _ := db.Has(diskValueKey(key1)); has {
+		t.Error("expected zero value to be deleted")
+	}
+	blockNumber, hash, ok := kv.Checkpoint()
+	if !ok {
+		t.Fatal("expected checkpoint")
+	}
+	if blockNumber != 5 || hash != blockHash {
+		t.Errorf("expected checkpoint (%v, %v), got (%v, %v)", 5, blockHash, blockNumber, hash)
+	}
+}
+
 type hookedKeyValueStore struct {
 	lib.KeyValueStore
 	getHook func(common.Hash)
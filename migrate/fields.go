@@ -0,0 +1,178 @@
+package migrate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
+	"github.com/holiman/uint256"
+)
+
+var intTypes = map[string]reflect.Type{
+	"int8":   reflect.TypeOf(int8(0)),
+	"int16":  reflect.TypeOf(int16(0)),
+	"int32":  reflect.TypeOf(int32(0)),
+	"int64":  reflect.TypeOf(int64(0)),
+	"uint8":  reflect.TypeOf(uint8(0)),
+	"uint16": reflect.TypeOf(uint16(0)),
+	"uint32": reflect.TypeOf(uint32(0)),
+	"uint64": reflect.TypeOf(uint64(0)),
+}
+
+// DecodeField decodes a field value as stored in a datamod row into the Go type the generated
+// row getters return for the field type, e.g., int16, common.Address or *uint256.Int.
+func DecodeField(fieldType datamod.FieldType, data []byte) (interface{}, error) {
+	switch fieldType.Type {
+	case datamod.BytesType:
+		if fieldType.GoType == "string" {
+			return string(data), nil
+		}
+		return common.CopyBytes(data), nil
+	case datamod.ValueType:
+	default:
+		return nil, fmt.Errorf("unsupported field type %s", fieldType.Name)
+	}
+	if len(data) != fieldType.Size {
+		return nil, fmt.Errorf("invalid %s data size %d", fieldType.Name, len(data))
+	}
+	switch fieldType.GoType {
+	case "bool":
+		return data[0]&1 == 1, nil
+	case "common.Address":
+		return common.BytesToAddress(data), nil
+	case "common.Hash":
+		return common.BytesToHash(data), nil
+	case "[]byte":
+		return common.CopyBytes(data), nil
+	case "*uint256.Int":
+		return new(uint256.Int).SetBytes(data), nil
+	}
+	goType, ok := intTypes[fieldType.GoType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported field type %s", fieldType.Name)
+	}
+	// Integers are stored big-endian in fieldType.Size bytes
+	var buf [8]byte
+	copy(buf[8-len(data):], data)
+	value := binary.BigEndian.Uint64(buf[:])
+	if strings.HasPrefix(fieldType.GoType, "int") {
+		shift := 64 - 8*uint(len(data))
+		return reflect.ValueOf(int64(value<<shift) >> shift).Convert(goType).Interface(), nil
+	}
+	return reflect.ValueOf(value).Convert(goType).Interface(), nil
+}
+
+// EncodeField encodes a field value into the data stored in a datamod row for the field type.
+// Integer fields take any Go integer that fits in the field, e.g., an int16 value can be written to an
+// int32 field, and uint256 fields take a *uint256.Int or a non-negative Go integer. A nil value
+// encodes to the zero value of the field type.
+func EncodeField(fieldType datamod.FieldType, value interface{}) ([]byte, error) {
+	switch fieldType.Type {
+	case datamod.BytesType:
+		switch v := value.(type) {
+		case nil:
+			return []byte{}, nil
+		case string:
+			return []byte(v), nil
+		case []byte:
+			return common.CopyBytes(v), nil
+		}
+		return nil, fmt.Errorf("cannot encode %T as %s", value, fieldType.Name)
+	case datamod.ValueType:
+	default:
+		return nil, fmt.Errorf("unsupported field type %s", fieldType.Name)
+	}
+	if value == nil {
+		return make([]byte, fieldType.Size), nil
+	}
+	switch fieldType.GoType {
+	case "bool":
+		if v, ok := value.(bool); ok {
+			if v {
+				return []byte{1}, nil
+			}
+			return []byte{0}, nil
+		}
+	case "common.Address":
+		if v, ok := value.(common.Address); ok {
+			return v.Bytes(), nil
+		}
+	case "common.Hash", "[]byte":
+		var data []byte
+		switch v := value.(type) {
+		case common.Hash:
+			data = v.Bytes()
+		case []byte:
+			data = v
+		default:
+			return nil, fmt.Errorf("cannot encode %T as %s", value, fieldType.Name)
+		}
+		if len(data) > fieldType.Size {
+			return nil, fmt.Errorf("%d bytes do not fit in %s", len(data), fieldType.Name)
+		}
+		return common.RightPadBytes(data, fieldType.Size), nil
+	default:
+		return encodeInteger(fieldType, value)
+	}
+	return nil, fmt.Errorf("cannot encode %T as %s", value, fieldType.Name)
+}
+
+// encodeInteger encodes a Go integer or *uint256.Int into an integer field, failing if it does not fit.
+func encodeInteger(fieldType datamod.FieldType, value interface{}) ([]byte, error) {
+	signed := strings.HasPrefix(fieldType.GoType, "int")
+	if _, ok := intTypes[fieldType.GoType]; !ok && fieldType.GoType != "*uint256.Int" {
+		return nil, fmt.Errorf("unsupported field type %s", fieldType.Name)
+	}
+
+	var (
+		v        = new(uint256.Int)
+		negative bool
+	)
+	if u, ok := value.(*uint256.Int); ok {
+		v.Set(u)
+	} else {
+		rv := reflect.ValueOf(value)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if i := rv.Int(); i < 0 {
+				// Two's complement in 256 bits, truncated to the field size when encoded
+				negative = true
+				v.Neg(v.SetUint64(uint64(-i)))
+			} else {
+				v.SetUint64(uint64(i))
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v.SetUint64(rv.Uint())
+		default:
+			return nil, fmt.Errorf("cannot encode %T as %s", value, fieldType.Name)
+		}
+	}
+	if negative && !signed {
+		return nil, fmt.Errorf("negative value %d does not fit in %s", value, fieldType.Name)
+	}
+
+	// Check that the value fits in the field size
+	bits := uint(8 * fieldType.Size)
+	if bits < 256 {
+		var limit *uint256.Int
+		if signed {
+			limit = new(uint256.Int).Lsh(uint256.NewInt(1), bits-1)
+		} else {
+			limit = new(uint256.Int).Lsh(uint256.NewInt(1), bits)
+		}
+		abs := v
+		if negative {
+			abs = new(uint256.Int).Neg(v)
+			limit.AddUint64(limit, 1) // The minimum signed value has no positive counterpart
+		}
+		if !abs.Lt(limit) {
+			return nil, fmt.Errorf("value %v does not fit in %s", value, fieldType.Name)
+		}
+	}
+	data := v.Bytes32()
+	return bytes.Clone(data[32-fieldType.Size:]), nil
+}
@@ -0,0 +1,335 @@
+package migrate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode"
+
+	"github.com/concrete-eth/archetype/kvstore"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
+	"github.com/ethereum/go-ethereum/concrete/lib"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrMigrationApplied     = errors.New("migration already applied")
+	ErrCalldataIsNotMigrate = errors.New("calldata is not a migration call")
+)
+
+// MigrateMethodName is the name of the method the core logic is called with to run a migration.
+const MigrateMethodName = "migrate"
+
+var migrateMethod = abi.NewMethod(MigrateMethodName, MigrateMethodName, abi.Function, "nonpayable", false, false,
+	abi.Arguments{{Name: "id", Type: mustNewType("string")}}, nil)
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// Row holds the decoded field values of a table row, keyed by field name.
+// Values have the Go types of the generated row getters, see DecodeField.
+type Row map[string]interface{}
+
+// TableMigration moves the rows of a table from one layout to another.
+// Rows are read with the From schema, transformed, and written with the To schema, which may have
+// a different name, i.e., rows can be moved to a new table. The rows of the old layout are cleared
+// before the new rows are written.
+type TableMigration struct {
+	From datamod.TableSchema
+	To   datamod.TableSchema
+	// Keys returns the keys of the rows to migrate, in the order of the key schema of the table.
+	// Rows of keyed tables are stored at slots derived from their keys so they cannot be enumerated
+	// and must be listed, e.g., from another table holding the number of rows. Keys of tables with
+	// no keys are ignored. Rows that were never written are skipped.
+	Keys func(ds lib.Datastore) ([][]interface{}, error)
+	// Transform returns the new row for an old row. Fields missing from the new row are left zero.
+	// If nil, fields are copied by name, converting integers to the new field sizes.
+	Transform func(keys []interface{}, row Row) (Row, error)
+}
+
+// Migration is a set of table migrations run together, once, under an id.
+type Migration struct {
+	Id     string
+	Tables []TableMigration
+}
+
+// Calldata returns the calldata that runs the migration with the given id when sent to the core logic.
+func Calldata(id string) []byte {
+	data, err := migrateMethod.Inputs.Pack(id)
+	if err != nil {
+		panic(err)
+	}
+	calldata := make([]byte, 0, len(migrateMethod.ID)+len(data))
+	return append(append(calldata, migrateMethod.ID...), data...)
+}
+
+// CalldataToId returns the migration id of calldata created with Calldata.
+func CalldataToId(calldata []byte) (string, error) {
+	if len(calldata) < 4 || !bytes.Equal(calldata[:4], migrateMethod.ID) {
+		return "", ErrCalldataIsNotMigrate
+	}
+	args, err := migrateMethod.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return "", err
+	}
+	return args[0].(string), nil
+}
+
+func appliedSlot(id string) []byte {
+	return crypto.Keccak256([]byte("archetype.migrations.v1." + id))
+}
+
+// IsApplied returns whether the migration with the given id has been applied to the store.
+func IsApplied(kv lib.KeyValueStore, id string) bool {
+	return lib.NewKVDatastore(kv).Get(appliedSlot(id)).Bool()
+}
+
+// Apply runs a migration against a store and records it as applied.
+// Either all rows are migrated or, on error, the store is left unchanged.
+func Apply(kv lib.KeyValueStore, migration Migration) error {
+	if IsApplied(kv, migration.Id) {
+		return fmt.Errorf("%w: %s", ErrMigrationApplied, migration.Id)
+	}
+	skv := kvstore.NewStagedKeyValueStore(kv)
+	ds := lib.NewKVDatastore(skv)
+	for _, table := range migration.Tables {
+		if err := migrateTable(ds, table); err != nil {
+			return fmt.Errorf("migration %s: table %s: %w", migration.Id, table.From.Name, err)
+		}
+	}
+	ds.Get(appliedSlot(migration.Id)).SetBool(true)
+	skv.Commit()
+	return nil
+}
+
+// ApplyAll runs the given migrations in order, skipping the ones already applied, and returns the ids
+// of the migrations it applied. It stops at the first migration that fails.
+func ApplyAll(kv lib.KeyValueStore, migrations ...Migration) ([]string, error) {
+	applied := make([]string, 0, len(migrations))
+	for _, migration := range migrations {
+		if IsApplied(kv, migration.Id) {
+			continue
+		}
+		if err := Apply(kv, migration); err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration.Id)
+	}
+	return applied, nil
+}
+
+// Migrations holds migrations by id.
+type Migrations map[string]Migration
+
+// NewMigrations creates a new Migrations from the given migrations, which must have unique ids.
+func NewMigrations(migrations ...Migration) (Migrations, error) {
+	m := make(Migrations, len(migrations))
+	for _, migration := range migrations {
+		if migration.Id == "" {
+			return nil, errors.New("migration id cannot be empty")
+		}
+		if _, ok := m[migration.Id]; ok {
+			return nil, fmt.Errorf("duplicate migration id %s", migration.Id)
+		}
+		m[migration.Id] = migration
+	}
+	return m, nil
+}
+
+// Run runs the migration called for in the calldata, returning ErrCalldataIsNotMigrate if the calldata
+// is not a migration call.
+func (m Migrations) Run(kv lib.KeyValueStore, calldata []byte) error {
+	id, err := CalldataToId(calldata)
+	if err != nil {
+		return err
+	}
+	migration, ok := m[id]
+	if !ok {
+		return fmt.Errorf("unknown migration %s", id)
+	}
+	return Apply(kv, migration)
+}
+
+func upperFirstLetter(str string) string {
+	if len(str) == 0 {
+		return ""
+	}
+	runes := []rune(str)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// tableSlot returns the slot of a table as the generated datamod code does.
+func tableSlot(ds lib.Datastore, schema datamod.TableSchema) lib.DatastoreSlot {
+	return ds.Get(crypto.Keccak256([]byte("datamod.v1." + upperFirstLetter(schema.Name))))
+}
+
+// tableRow returns the row of a table with the given keys.
+func tableRow(ds lib.Datastore, schema datamod.TableSchema, keys []interface{}) (*lib.DatastoreStruct, error) {
+	slot := tableSlot(ds, schema)
+	if len(schema.Keys) > 0 {
+		if len(keys) != len(schema.Keys) {
+			return nil, fmt.Errorf("expected %d keys, got %d", len(schema.Keys), len(keys))
+		}
+		encodedKeys := make([][]byte, len(keys))
+		for ii, key := range keys {
+			data, err := EncodeField(schema.Keys[ii].Type, key)
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", schema.Keys[ii].Name, err)
+			}
+			encodedKeys[ii] = data
+		}
+		slot = slot.Mapping().GetNested(encodedKeys...)
+	}
+	sizes := make([]int, len(schema.Values))
+	for ii, field := range schema.Values {
+		sizes[ii] = field.Type.Size
+	}
+	return lib.NewDatastoreStruct(slot, sizes), nil
+}
+
+// readRow reads and decodes a row, returning false if the row was never written.
+func readRow(row *lib.DatastoreStruct, schema datamod.TableSchema) (Row, bool, error) {
+	values := make(Row, len(schema.Values))
+	written := false
+	for ii, field := range schema.Values {
+		var data []byte
+		if field.Type.Type == datamod.BytesType {
+			data = row.GetField_bytes(ii)
+		} else {
+			data = row.GetField(ii)
+		}
+		for _, b := range data {
+			if b != 0 {
+				written = true
+				break
+			}
+		}
+		value, err := DecodeField(field.Type, data)
+		if err != nil {
+			return nil, false, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		values[field.Name] = value
+	}
+	return values, written, nil
+}
+
+// clearRow zeroes all the fields of a row, including the tails of long bytes and string fields.
+func clearRow(row *lib.DatastoreStruct, schema datamod.TableSchema) {
+	for ii, field := range schema.Values {
+		if field.Type.Type == datamod.BytesType {
+			row.SetField_bytes(ii, make([]byte, len(row.GetField_bytes(ii))))
+			row.SetField_bytes(ii, nil)
+		} else {
+			row.SetField(ii, make([]byte, field.Type.Size))
+		}
+	}
+}
+
+// writeRow encodes and writes a row.
+func writeRow(row *lib.DatastoreStruct, schema datamod.TableSchema, values Row) error {
+	for ii, field := range schema.Values {
+		data, err := EncodeField(field.Type, values[field.Name])
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if field.Type.Type == datamod.BytesType {
+			row.SetField_bytes(ii, data)
+		} else {
+			row.SetField(ii, data)
+		}
+	}
+	return nil
+}
+
+// checkFields checks that every field of a row is in the schema.
+func checkFields(schema datamod.TableSchema, values Row) error {
+	for name := range values {
+		found := false
+		for _, field := range schema.Values {
+			if field.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown field %s", name)
+		}
+	}
+	return nil
+}
+
+// copyFields returns the fields of a row that are also in the schema.
+func copyFields(schema datamod.TableSchema, values Row) Row {
+	newValues := make(Row, len(schema.Values))
+	for _, field := range schema.Values {
+		if value, ok := values[field.Name]; ok {
+			newValues[field.Name] = value
+		}
+	}
+	return newValues
+}
+
+func migrateTable(ds lib.Datastore, table TableMigration) error {
+	for _, schema := range []datamod.TableSchema{table.From, table.To} {
+		for _, field := range schema.Values {
+			if field.Type.Type == datamod.TableType {
+				return fmt.Errorf("field %s: nested tables cannot be migrated", field.Name)
+			}
+		}
+	}
+	if len(table.From.Keys) != len(table.To.Keys) {
+		return errors.New("tables must have the same number of keys")
+	}
+
+	keys := [][]interface{}{nil}
+	if len(table.From.Keys) > 0 {
+		if table.Keys == nil {
+			return errors.New("keyed tables require a key function")
+		}
+		var err error
+		if keys, err = table.Keys(ds); err != nil {
+			return err
+		}
+	}
+
+	for _, rowKeys := range keys {
+		oldRow, err := tableRow(ds, table.From, rowKeys)
+		if err != nil {
+			return err
+		}
+		values, written, err := readRow(oldRow, table.From)
+		if err != nil {
+			return err
+		}
+		if !written {
+			continue
+		}
+		var newValues Row
+		if table.Transform != nil {
+			if newValues, err = table.Transform(rowKeys, values); err != nil {
+				return err
+			}
+			if err := checkFields(table.To, newValues); err != nil {
+				return err
+			}
+		} else {
+			newValues = copyFields(table.To, values)
+		}
+		newRow, err := tableRow(ds, table.To, rowKeys)
+		if err != nil {
+			return err
+		}
+		clearRow(oldRow, table.From)
+		if err := writeRow(newRow, table.To, newValues); err != nil {
+			return fmt.Errorf("row %v: %w", rowKeys, err)
+		}
+	}
+	return nil
+}
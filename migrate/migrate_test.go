@@ -0,0 +1,282 @@
+package migrate
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/concrete-eth/archetype/kvstore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
+	"github.com/ethereum/go-ethereum/concrete/lib"
+	"github.com/holiman/uint256"
+)
+
+func mustTableSchema(t *testing.T, schemaJson string) datamod.TableSchema {
+	schemas, err := datamod.UnmarshalTableSchemas([]byte(schemaJson), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemas) != 1 {
+		t.Fatalf("expected 1 table schema, got %d", len(schemas))
+	}
+	return schemas[0]
+}
+
+func mustFieldType(t *testing.T, name string) datamod.FieldType {
+	return mustTableSchema(t, `{"table":{"schema":{"field":"`+name+`"}}}`).Values[0].Type
+}
+
+func mustWriteRow(t *testing.T, kv lib.KeyValueStore, schema datamod.TableSchema, keys []interface{}, values Row) {
+	row, err := tableRow(lib.NewKVDatastore(kv), schema, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeRow(row, schema, values); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustReadRow(t *testing.T, kv lib.KeyValueStore, schema datamod.TableSchema, keys []interface{}) Row {
+	row, err := tableRow(lib.NewKVDatastore(kv), schema, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, _, err := readRow(row, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return values
+}
+
+func TestFieldCodec(t *testing.T) {
+	var (
+		int8Type    = mustFieldType(t, "int8")
+		int32Type   = mustFieldType(t, "int32")
+		uint16Type  = mustFieldType(t, "uint16")
+		uint256Type = mustFieldType(t, "uint256")
+		bytes4Type  = mustFieldType(t, "bytes4")
+		addressType = mustFieldType(t, "address")
+		stringType  = mustFieldType(t, "string")
+	)
+	testData := []struct {
+		fieldType datamod.FieldType
+		value     interface{}
+		decoded   interface{}
+	}{
+		{int8Type, int8(-128), int8(-128)},
+		{int8Type, int64(127), int8(127)},
+		{int32Type, int16(-2), int32(-2)},
+		{uint16Type, uint8(7), uint16(7)},
+		{uint16Type, math.MaxUint16, uint16(math.MaxUint16)},
+		{uint256Type, uint64(math.MaxUint64), uint256.NewInt(math.MaxUint64)},
+		{uint256Type, uint256.NewInt(3), uint256.NewInt(3)},
+		{bytes4Type, []byte{1, 2}, []byte{1, 2, 0, 0}},
+		{addressType, common.Address{1}, common.Address{1}},
+		{stringType, "hello", "hello"},
+		{int32Type, nil, int32(0)},
+	}
+	for _, tt := range testData {
+		data, err := EncodeField(tt.fieldType, tt.value)
+		if err != nil {
+			t.Fatalf("%s %v: %v", tt.fieldType.Name, tt.value, err)
+		}
+		decoded, err := DecodeField(tt.fieldType, data)
+		if err != nil {
+			t.Fatalf("%s %v: %v", tt.fieldType.Name, tt.value, err)
+		}
+		if !reflect.DeepEqual(decoded, tt.decoded) {
+			t.Errorf("%s %v: expected %v, got %v", tt.fieldType.Name, tt.value, tt.decoded, decoded)
+		}
+	}
+
+	invalidData := []struct {
+		fieldType datamod.FieldType
+		value     interface{}
+	}{
+		{int8Type, int16(128)},
+		{int8Type, int16(-129)},
+		{uint16Type, int8(-1)},
+		{uint16Type, uint32(math.MaxUint16 + 1)},
+		{bytes4Type, []byte{1, 2, 3, 4, 5}},
+		{addressType, "0x01"},
+	}
+	for _, tt := range invalidData {
+		if _, err := EncodeField(tt.fieldType, tt.value); err == nil {
+			t.Errorf("%s %v: expected error", tt.fieldType.Name, tt.value)
+		}
+	}
+}
+
+func TestMigrateTable(t *testing.T) {
+	var (
+		oldSchema = mustTableSchema(t, `{"players":{"keySchema":{"id":"uint8"},"schema":{"score":"int16","name":"string"}}}`)
+		newSchema = mustTableSchema(t, `{"players":{"keySchema":{"id":"uint8"},"schema":{"score":"int64","name":"string","active":"bool"}}}`)
+		kv        = kvstore.NewMemoryKeyValueStore()
+		longName  = strings.Repeat("a", 41)
+	)
+	mustWriteRow(t, kv, oldSchema, []interface{}{uint8(1)}, Row{"score": int16(-5), "name": "alice"})
+	mustWriteRow(t, kv, oldSchema, []interface{}{uint8(2)}, Row{"score": int16(7), "name": longName})
+
+	migration := Migration{
+		Id: "players-v2",
+		Tables: []TableMigration{{
+			From: oldSchema,
+			To:   newSchema,
+			Keys: func(ds lib.Datastore) ([][]interface{}, error) {
+				return [][]interface{}{{uint8(1)}, {uint8(2)}, {uint8(3)}}, nil
+			},
+			Transform: func(keys []interface{}, row Row) (Row, error) {
+				return Row{
+					"score":  int64(row["score"].(int16)) * 1000,
+					"name":   row["name"],
+					"active": true,
+				}, nil
+			},
+		}},
+	}
+	if err := Apply(kv, migration); err != nil {
+		t.Fatal(err)
+	}
+
+	expRows := map[uint8]Row{
+		1: {"score": int64(-5000), "name": "alice", "active": true},
+		2: {"score": int64(7000), "name": longName, "active": true},
+		3: {"score": int64(0), "name": "", "active": false}, // Never written
+	}
+	for id, expRow := range expRows {
+		if row := mustReadRow(t, kv, newSchema, []interface{}{id}); !reflect.DeepEqual(row, expRow) {
+			t.Errorf("row %d: expected %v, got %v", id, expRow, row)
+		}
+	}
+
+	// Migrations run once
+	if !IsApplied(kv, migration.Id) {
+		t.Error("expected migration to be applied")
+	}
+	if err := Apply(kv, migration); !errors.Is(err, ErrMigrationApplied) {
+		t.Errorf("expected %v, got %v", ErrMigrationApplied, err)
+	}
+	applied, err := ApplyAll(kv, migration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("expected no migrations applied, got %v", applied)
+	}
+}
+
+func TestMigrateRenamedTable(t *testing.T) {
+	var (
+		oldSchema = mustTableSchema(t, `{"counter":{"schema":{"value":"int16","name":"string"}}}`)
+		newSchema = mustTableSchema(t, `{"counterV2":{"schema":{"value":"int32"}}}`)
+		kv        = kvstore.NewMemoryKeyValueStore()
+	)
+	mustWriteRow(t, kv, oldSchema, nil, Row{"value": int16(-3), "name": strings.Repeat("b", 65)})
+
+	// Fields are copied by name when there is no transform
+	applied, err := ApplyAll(kv, Migration{Id: "counter-v2", Tables: []TableMigration{{From: oldSchema, To: newSchema}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(applied, []string{"counter-v2"}) {
+		t.Errorf("expected %v, got %v", []string{"counter-v2"}, applied)
+	}
+	if row := mustReadRow(t, kv, newSchema, nil); row["value"] != int32(-3) {
+		t.Errorf("expected value %v, got %v", int32(-3), row["value"])
+	}
+
+	// The old table is cleared, leaving only the new row and the applied migration record
+	nonZero := 0
+	kv.ForEach(func(key, value common.Hash) bool {
+		if value != (common.Hash{}) {
+			nonZero++
+		}
+		return true
+	})
+	if nonZero != 2 {
+		t.Errorf("expected 2 non-zero slots, got %d", nonZero)
+	}
+}
+
+func TestMigrateFailure(t *testing.T) {
+	var (
+		oldSchema = mustTableSchema(t, `{"players":{"keySchema":{"id":"uint8"},"schema":{"score":"int32"}}}`)
+		newSchema = mustTableSchema(t, `{"players":{"keySchema":{"id":"uint8"},"schema":{"score":"int8"}}}`)
+		kv        = kvstore.NewMemoryKeyValueStore()
+	)
+	mustWriteRow(t, kv, oldSchema, []interface{}{uint8(1)}, Row{"score": int32(1)})
+	mustWriteRow(t, kv, oldSchema, []interface{}{uint8(2)}, Row{"score": int32(1000)})
+
+	migration := Migration{
+		Id: "players-int8",
+		Tables: []TableMigration{{
+			From: oldSchema,
+			To:   newSchema,
+			Keys: func(ds lib.Datastore) ([][]interface{}, error) {
+				return [][]interface{}{{uint8(1)}, {uint8(2)}}, nil
+			},
+		}},
+	}
+	// The score of the second row does not fit in the new field
+	if err := Apply(kv, migration); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// The store is left unchanged
+	if IsApplied(kv, migration.Id) {
+		t.Error("expected migration not to be applied")
+	}
+	for id, score := range map[uint8]int32{1: 1, 2: 1000} {
+		if row := mustReadRow(t, kv, oldSchema, []interface{}{id}); row["score"] != score {
+			t.Errorf("row %d: expected score %v, got %v", id, score, row["score"])
+		}
+	}
+}
+
+func TestMigrationsRun(t *testing.T) {
+	var (
+		schema = mustTableSchema(t, `{"counter":{"schema":{"value":"int16"}}}`)
+		kv     = kvstore.NewMemoryKeyValueStore()
+	)
+	mustWriteRow(t, kv, schema, nil, Row{"value": int16(2)})
+
+	migrations, err := NewMigrations(Migration{
+		Id: "double",
+		Tables: []TableMigration{{
+			From: schema,
+			To:   schema,
+			Transform: func(keys []interface{}, row Row) (Row, error) {
+				return Row{"value": row["value"].(int16) * 2}, nil
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := CalldataToId(Calldata("double"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "double" {
+		t.Errorf("expected id %v, got %v", "double", id)
+	}
+	if err := migrations.Run(kv, []byte{1, 2, 3, 4}); err != ErrCalldataIsNotMigrate {
+		t.Errorf("expected %v, got %v", ErrCalldataIsNotMigrate, err)
+	}
+	if err := migrations.Run(kv, Calldata("unknown")); err == nil {
+		t.Error("expected error running an unknown migration")
+	}
+	if err := migrations.Run(kv, Calldata("double")); err != nil {
+		t.Fatal(err)
+	}
+	if row := mustReadRow(t, kv, schema, nil); row["value"] != int16(4) {
+		t.Errorf("expected value %v, got %v", int16(4), row["value"])
+	}
+	if err := migrations.Run(kv, Calldata("double")); !errors.Is(err, ErrMigrationApplied) {
+		t.Errorf("expected %v, got %v", ErrMigrationApplied, err)
+	}
+}
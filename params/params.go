@@ -9,14 +9,21 @@ import (
 
 // ValueParams holds value parameters.
 var ValueParams = map[string]interface{}{
-	"ActionExecutedEventName": ActionExecutedEventName,
-	"MultiActionMethodName":   MultiActionMethodName,
-	"IActionsContract":        IActionsContract,
-	"ITablesContract":         ITablesContract,
-	"ICoreContract":           ICoreContract,
-	"EntrypointContract":      EntrypointContract,
-	"TickActionName":          TickActionName,
-	"TickActionIdHex":         TickActionIdHex,
+	"ActionExecutedEventName":  ActionExecutedEventName,
+	"MultiActionMethodName":    MultiActionMethodName,
+	"MultiTableReadMethodName": MultiTableReadMethodName,
+	"IActionsContract":         IActionsContract,
+	"ITablesContract":          ITablesContract,
+	"IEventsContract":          IEventsContract,
+	"ICoreContract":            ICoreContract,
+	"EntrypointContract":       EntrypointContract,
+	"TickActionName":           TickActionName,
+	"TickActionIdHex":          TickActionIdHex,
+	"GrantRoleActionName":      GrantRoleActionName,
+	"GrantRoleActionIdHex":     GrantRoleActionIdHex,
+	"RevokeRoleActionName":     RevokeRoleActionName,
+	"RevokeRoleActionIdHex":    RevokeRoleActionIdHex,
+	"HasRoleMethodName":        HasRoleMethodName,
 }
 
 // FunctionParams holds function parameters.
@@ -25,16 +32,21 @@ var FunctionParams = map[string]interface{}{
 	"GoActionStructNameFn":       GoActionStructName,
 	"GoTableMethodNameFn":        GoTableMethodName,
 	"GoTableStructNameFn":        GoTableStructName,
+	"GoActionErrorStructNameFn":  GoActionErrorStructName,
+	"GoEventStructNameFn":        GoEventStructName,
 	"SolidityActionMethodNameFn": SolidityActionMethodName,
 	"SolidityActionStructNameFn": SolidityActionStructName,
 	"SolidityTableMethodNameFn":  SolidityTableMethodName,
 	"SolidityTableStructNameFn":  SolidityTableStructName,
+	"SolidityActionErrorNameFn":  SolidityActionErrorName,
+	"SolidityEventNameFn":        SolidityEventName,
 }
 
 const (
-	ActionExecutedEventName = "ActionExecuted"
-	ActionEventSignature    = "ActionExecuted(bytes4,bytes)"
-	MultiActionMethodName   = "executeMultipleActions"
+	ActionExecutedEventName  = "ActionExecuted"
+	ActionEventSignature     = "ActionExecuted(bytes4,bytes)"
+	MultiActionMethodName    = "executeMultipleActions"
+	MultiTableReadMethodName = "readMultipleTables"
 )
 
 var (
@@ -71,6 +83,14 @@ func tableStructName(name string) string {
 	return "RowData_" + upperFirstChar(name)
 }
 
+func actionErrorName(name string) string {
+	return upperFirstChar(name)
+}
+
+func eventName(name string) string {
+	return upperFirstChar(name)
+}
+
 func GoActionMethodName(name string) string {
 	return upperFirstChar(actionMethodName(name))
 }
@@ -87,6 +107,14 @@ func GoTableStructName(name string) string {
 	return tableStructName(name)
 }
 
+func GoActionErrorStructName(name string) string {
+	return "ActionError_" + actionErrorName(name)
+}
+
+func GoEventStructName(name string) string {
+	return "Event_" + eventName(name)
+}
+
 func SolidityActionMethodName(name string) string {
 	return lowerFirstChar(actionMethodName(name))
 }
@@ -103,6 +131,14 @@ func SolidityTableStructName(name string) string {
 	return tableStructName(name)
 }
 
+func SolidityActionErrorName(name string) string {
+	return actionErrorName(name)
+}
+
+func SolidityEventName(name string) string {
+	return eventName(name)
+}
+
 type ContractSpecs struct {
 	FileName     string
 	ContractName string
@@ -121,6 +157,12 @@ var ITablesContract = ContractSpecs{
 	PackageName:  "tables",
 }
 
+var IEventsContract = ContractSpecs{
+	FileName:     "IEvents.sol",
+	ContractName: "IEvents",
+	PackageName:  "events",
+}
+
 var ICoreContract = ContractSpecs{
 	FileName:     "ICore.sol",
 	ContractName: "ICore",
@@ -144,3 +186,24 @@ var (
 	TickActionId    = crypto.Keccak256([]byte(SolidityActionMethodName(TickActionName) + "()"))[:4]
 	TickActionIdHex = "0x" + common.Bytes2Hex(TickActionId)
 )
+
+// Built-in actions the owner of a game uses to grant and revoke roles to accounts.
+// Both take a single (bytes32 role, address account) tuple argument.
+var (
+	GrantRoleActionName   = "GrantRole"
+	GrantRoleActionId     = crypto.Keccak256([]byte(SolidityActionMethodName(GrantRoleActionName) + "((bytes32,address))"))[:4]
+	GrantRoleActionIdHex  = "0x" + common.Bytes2Hex(GrantRoleActionId)
+	RevokeRoleActionName  = "RevokeRole"
+	RevokeRoleActionId    = crypto.Keccak256([]byte(SolidityActionMethodName(RevokeRoleActionName) + "((bytes32,address))"))[:4]
+	RevokeRoleActionIdHex = "0x" + common.Bytes2Hex(RevokeRoleActionId)
+)
+
+// HasRoleMethodName is the name of the view method of the core that returns whether an account has a role.
+const HasRoleMethodName = "hasRole"
+
+// OwnerSelector is the selector of the owner() method of the game contract.
+var OwnerSelector = crypto.Keccak256([]byte("owner()"))[:4]
+
+// ProxyAdminSlot is the ERC-1967 storage slot holding the admin of a proxy, i.e., the game contract of
+// the core behind it.
+var ProxyAdminSlot = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
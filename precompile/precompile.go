@@ -1,16 +1,96 @@
 package precompile
 
 import (
+	"errors"
+	"fmt"
+	"math"
+
 	"github.com/concrete-eth/archetype/arch"
 	"github.com/concrete-eth/archetype/kvstore"
+	"github.com/concrete-eth/archetype/migrate"
+	"github.com/concrete-eth/archetype/params"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/concrete"
 	"github.com/ethereum/go-ethereum/concrete/lib"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	ErrGasLimitExceeded = errors.New("core gas limit exceeded")
 )
 
+// GasConfig configures the gas charged by CorePrecompile for running core logic, on top of the gas
+// used by storage reads and writes.
+type GasConfig struct {
+	ActionGas uint64 `json:"actionGas" toml:"actionGas"` // Gas charged for every executed action other than the tick action
+	TickGas   uint64 `json:"tickGas" toml:"tickGas"`     // Gas charged per tick, i.e., the tick action costs TickGas * TicksPerBlock
+	GasLimit  uint64 `json:"gasLimit" toml:"gasLimit"`   // Maximum gas charged by the core logic of a single call, zero for no limit
+}
+
+// gasLimitExceeded is the panic value used to abort core execution when the gas limit is exceeded.
+type gasLimitExceeded struct{}
+
+// envGasMeter charges gas to a concrete environment, enforcing a gas limit.
+type envGasMeter struct {
+	env   concrete.Environment
+	limit uint64
+	used  uint64
+}
+
+var _ arch.GasMeter = (*envGasMeter)(nil)
+
+func newEnvGasMeter(env concrete.Environment, limit uint64) *envGasMeter {
+	if limit == 0 {
+		limit = math.MaxUint64
+	}
+	return &envGasMeter{env: env, limit: limit}
+}
+
+// UseGas charges gas to the environment and panics with gasLimitExceeded if the gas limit is exceeded.
+// Running out of transaction gas is handled by the environment.
+func (m *envGasMeter) UseGas(amount uint64) {
+	if amount > m.limit-m.used {
+		panic(gasLimitExceeded{})
+	}
+	m.used += amount
+	m.env.UseGas(amount)
+}
+
+// runMetered runs f, returning ErrGasLimitExceeded if the gas meter aborts it.
+func runMetered(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(gasLimitExceeded); ok {
+				err = ErrGasLimitExceeded
+				return
+			}
+			panic(r)
+		}
+	}()
+	return f()
+}
+
+// revertData is an error holding raw revert data.
+// Concrete returns the message of errors returned by precompiles as revert data.
+type revertData []byte
+
+func (d revertData) Error() string {
+	return string(d)
+}
+
+func mulSaturating(a, b uint64) uint64 {
+	if a != 0 && b > math.MaxUint64/a {
+		return math.MaxUint64
+	}
+	return a * b
+}
+
 type CorePrecompile struct {
 	lib.BlankPrecompile
 	schemas         arch.ArchSchemas
 	coreConstructor func() arch.Core
+	gasConfig       GasConfig
+	migrations      migrate.Migrations
 }
 
 var _ concrete.Precompile = (*CorePrecompile)(nil)
@@ -23,7 +103,43 @@ func NewCorePrecompile(schemas arch.ArchSchemas, coreConstructor func() arch.Cor
 	}
 }
 
+// SetGasConfig sets the gas charged for running core logic.
+// By default only storage reads and writes are charged.
+func (p *CorePrecompile) SetGasConfig(config GasConfig) {
+	p.gasConfig = config
+}
+
+// SetMigrations sets the storage migrations the precompile can run.
+// A migration runs once when the precompile is called with migrate.Calldata of its id. The game contract
+// only forwards action calls, so the call can only reach the precompile as the data of a proxy upgrade,
+// which only the owner of the game contract can make.
+func (p *CorePrecompile) SetMigrations(migrations migrate.Migrations) {
+	p.migrations = migrations
+}
+
+// actionGas returns the base gas charged for executing the given action on the given core.
+func (p *CorePrecompile) actionGas(action arch.Action, core arch.Core) uint64 {
+	if _, ok := action.(*arch.CanonicalTickAction); ok {
+		return mulSaturating(p.gasConfig.TickGas, core.TicksPerBlock())
+	}
+	return p.gasConfig.ActionGas
+}
+
+// proxyAdmin returns the admin of the proxy delegating to the precompile, i.e., the game contract, or the
+// zero address if the precompile is not called through a proxy.
+func proxyAdmin(kv lib.KeyValueStore) common.Address {
+	return common.BytesToAddress(kv.Get(params.ProxyAdminSlot).Bytes())
+}
+
 func (p *CorePrecompile) executeAction(env concrete.Environment, kv lib.KeyValueStore, action arch.Action) error {
+	// The permission modifiers of the game contract authorize restricted actions against the account
+	// calling the game, so restricted actions are only accepted from the game contract
+	if permission := p.schemas.Actions.ActionPermission(action); permission.Kind != arch.PermissionOpen {
+		if admin := proxyAdmin(kv); admin == (common.Address{}) || env.GetCaller() != admin {
+			return fmt.Errorf("%w: %T requires %s and can only be called by the game contract", arch.ErrUnauthorized, action, permission)
+		}
+	}
+
 	// Wrap the persistent kv store in a cached kv store to save gas when reading multiple times from the same slot
 	ckv := kvstore.NewCachedKeyValueStore(kv)
 	// Wrap the cached kv store in a staged kv store to save gas when writing multiple times to the same slot
@@ -36,12 +152,45 @@ func (p *CorePrecompile) executeAction(env concrete.Environment, kv lib.KeyValue
 	core.SetKV(skv)
 	// Set the block number in the core
 	core.SetBlockNumber(env.GetBlockNumber())
+	// Set the execution context in the core
+	if c, ok := core.(arch.ISetExecutionContext); ok {
+		c.SetExecutionContext(arch.ExecutionContext{
+			Caller:         env.GetCaller(),
+			Origin:         env.GetTxOrigin(),
+			BlockTimestamp: env.GetBlockTimestamp(),
+		})
+	}
 
-	// Execute the action
-	if err := p.schemas.Actions.ExecuteAction(action, core); err != nil {
+	// Set the gas meter in the core so core logic can charge gas explicitly
+	meter := newEnvGasMeter(env, p.gasConfig.GasLimit)
+	if c, ok := core.(arch.ISetGasMeter); ok {
+		c.SetGasMeter(meter)
+	}
+
+	// Set the event emitter in the core to collect the custom events emitted by the action
+	events := arch.NewEventBuffer()
+	if c, ok := core.(arch.ISetEventEmitter); ok {
+		c.SetEventEmitter(events)
+	}
+
+	// Charge the base gas and execute the action
+	if err := runMetered(func() error {
+		meter.UseGas(p.actionGas(action, core))
+		return p.schemas.Actions.ExecuteAction(action, core)
+	}); err != nil {
 		return err
 	}
 
+	// Encode the custom events
+	eventLogs := make([]types.Log, 0, events.Len())
+	for _, event := range events.Events() {
+		log, err := p.schemas.Events.EventToLog(event)
+		if err != nil {
+			return err
+		}
+		eventLogs = append(eventLogs, log)
+	}
+
 	// Commit the staged kv store
 	skv.Commit()
 
@@ -52,6 +201,11 @@ func (p *CorePrecompile) executeAction(env concrete.Environment, kv lib.KeyValue
 	}
 	env.Log(log.Topics, log.Data)
 
+	// Emit the custom event logs after the action log
+	for _, log := range eventLogs {
+		env.Log(log.Topics, log.Data)
+	}
+
 	return nil
 }
 
@@ -59,7 +213,7 @@ func (p *CorePrecompile) IsStatic(input []byte) bool {
 	if _, ok := p.schemas.Tables.TargetTableId(input); ok {
 		return true
 	}
-	return false
+	return p.schemas.Tables.IsMultiRead(input) || arch.IsRoleRead(input)
 }
 
 func (p *CorePrecompile) Run(env concrete.Environment, input []byte) (_ret []byte, _err error) {
@@ -77,12 +231,30 @@ func (p *CorePrecompile) Run(env concrete.Environment, input []byte) (_ret []byt
 		return nil, err
 	}
 
+	// Return whether the account has the role if call is a role read
+	if arch.IsRoleRead(input) {
+		return arch.ReadRolePacked(kv, input)
+	}
+
+	// Run the migration if call is a migration
+	if p.migrations != nil {
+		if err := p.migrations.Run(kv, input); err == nil {
+			return nil, nil
+		} else if err != migrate.ErrCalldataIsNotMigrate {
+			return nil, err
+		}
+	}
+
 	// Execute the action if call is an action
 	if action, err := p.schemas.Actions.CalldataToAction(input); err != nil {
 		// fmt.Println("Error converting calldata to action", err)
 		return nil, err
 	} else if err := p.executeAction(env, kv, action); err != nil {
 		// fmt.Println("Error executing action", err)
+		if data, ok := p.schemas.Actions.EncodeError(err); ok {
+			// Revert with the Solidity custom error declared for the action error
+			return nil, revertData(data)
+		}
 		return nil, err
 	}
 
@@ -1 +1,321 @@
 package precompile
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/concrete-eth/archetype/arch"
+	"github.com/concrete-eth/archetype/migrate"
+	"github.com/concrete-eth/archetype/params"
+	"github.com/concrete-eth/archetype/testutils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete/api"
+	"github.com/ethereum/go-ethereum/concrete/codegen/datamod"
+	"github.com/ethereum/go-ethereum/concrete/lib"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+const testExplicitGas = 1000
+
+// gasCore charges gas explicitly on every Add action.
+type gasCore struct {
+	testutils.Core
+}
+
+func (c *gasCore) Add(action *testutils.ActionData_Add) error {
+	c.UseGas(testExplicitGas)
+	return c.Core.Add(action)
+}
+
+// runWithGas runs the precompile with the given calldata in a gas metered environment and returns
+// the gas used and the value of the counter afterwards.
+func runWithGas(t *testing.T, pc *CorePrecompile, input []byte) (uint64, int16, error) {
+	const gas = 1e7
+	contract := api.NewContract(common.Address{}, common.Address{}, common.Address{}, uint256.NewInt(0))
+	contract.Gas = gas
+	env, db, _, _ := api.NewMockEnvironment(api.WithMeterGas(true), api.WithContract(contract))
+	_, err := pc.Run(env, input)
+
+	core := &testutils.Core{}
+	readEnv, _, _, _ := api.NewMockEnvironment(api.WithStateDB(db))
+	core.SetKV(lib.NewEnvStorageKeyValueStore(readEnv))
+	return gas - env.Gas(), core.GetCounter(), err
+}
+
+func TestCorePrecompileGas(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		newCore = func() arch.Core { return &gasCore{} }
+	)
+	addInput, err := schemas.Actions.ActionToCalldata(&testutils.ActionData_Add{Summand: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tickInput, err := schemas.Actions.ActionToCalldata(&arch.CanonicalTickAction{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No gas config: only storage access and explicit charges
+	baseGas, _, err := runWithGas(t, NewCorePrecompile(schemas, newCore), addInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseTickGas, _, err := runWithGas(t, NewCorePrecompile(schemas, newCore), tickInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := GasConfig{ActionGas: 500, TickGas: 300}
+	pc := NewCorePrecompile(schemas, newCore)
+	pc.SetGasConfig(config)
+
+	gasUsed, counter, err := runWithGas(t, pc, addInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counter != 1 {
+		t.Errorf("expected counter %v, got %v", 1, counter)
+	}
+	if gasUsed != baseGas+config.ActionGas {
+		t.Errorf("expected %v gas, got %v", baseGas+config.ActionGas, gasUsed)
+	}
+
+	ticksPerBlock := (&testutils.Core{}).TicksPerBlock()
+	if gasUsed, _, err = runWithGas(t, pc, tickInput); err != nil {
+		t.Fatal(err)
+	}
+	if expGas := baseTickGas + config.TickGas*ticksPerBlock; gasUsed != expGas {
+		t.Errorf("expected %v gas, got %v", expGas, gasUsed)
+	}
+
+	// Exceeding the gas limit reverts the action
+	config.GasLimit = config.ActionGas + testExplicitGas - 1
+	pc.SetGasConfig(config)
+	_, counter, err = runWithGas(t, pc, addInput)
+	if !errors.Is(err, ErrGasLimitExceeded) {
+		t.Fatalf("expected %v, got %v", ErrGasLimitExceeded, err)
+	}
+	if counter != 0 {
+		t.Errorf("expected counter %v, got %v", 0, counter)
+	}
+}
+
+func TestCorePrecompileCustomError(t *testing.T) {
+	var (
+		schemas      = testutils.NewTestArchSchemas(t)
+		pc           = NewCorePrecompile(schemas, func() arch.Core { return &testutils.Core{} })
+		env, _, _, _ = api.NewMockEnvironment()
+	)
+	addMaxInput, err := schemas.Actions.ActionToCalldata(&testutils.ActionData_Add{Summand: math.MaxInt16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addOneInput, err := schemas.Actions.ActionToCalldata(&testutils.ActionData_Add{Summand: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := pc.Run(env, addMaxInput); err != nil {
+		t.Fatal(err)
+	}
+	_, err = pc.Run(env, addOneInput)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	// The revert data is the custom error declared for the action
+	decoded, ok := schemas.Actions.DecodeError([]byte(err.Error()))
+	if !ok {
+		t.Fatalf("expected custom error, got %v", err)
+	}
+	expErr := &testutils.ActionError_CounterOverflow{Counter: math.MaxInt16, Summand: 1}
+	if !reflect.DeepEqual(decoded, expErr) {
+		t.Fatalf("expected %v, got %v", expErr, decoded)
+	}
+}
+
+func TestCorePrecompileMigration(t *testing.T) {
+	var (
+		schemas      = testutils.NewTestArchSchemas(t)
+		pc           = NewCorePrecompile(schemas, func() arch.Core { return &testutils.Core{} })
+		env, _, _, _ = api.NewMockEnvironment()
+	)
+	tableSchemas, err := datamod.UnmarshalTableSchemas([]byte(`{"counter":{"schema":{"value":"int16"}}}`), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrations, err := migrate.NewMigrations(migrate.Migration{
+		Id: "double",
+		Tables: []migrate.TableMigration{{
+			From: tableSchemas[0],
+			To:   tableSchemas[0],
+			Transform: func(keys []interface{}, row migrate.Row) (migrate.Row, error) {
+				return migrate.Row{"value": row["value"].(int16) * 2}, nil
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc.SetMigrations(migrations)
+
+	addInput, err := schemas.Actions.ActionToCalldata(&testutils.ActionData_Add{Summand: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pc.Run(env, addInput); err != nil {
+		t.Fatal(err)
+	}
+	if pc.IsStatic(migrate.Calldata("double")) {
+		t.Error("expected migration call not to be static")
+	}
+	if _, err := pc.Run(env, migrate.Calldata("double")); err != nil {
+		t.Fatal(err)
+	}
+
+	core := &testutils.Core{}
+	core.SetKV(lib.NewEnvStorageKeyValueStore(env))
+	if counter := core.GetCounter(); counter != 6 {
+		t.Errorf("expected counter %v, got %v", 6, counter)
+	}
+
+	// Migrations run once
+	if _, err := pc.Run(env, migrate.Calldata("double")); !errors.Is(err, migrate.ErrMigrationApplied) {
+		t.Errorf("expected %v, got %v", migrate.ErrMigrationApplied, err)
+	}
+	if counter := core.GetCounter(); counter != 6 {
+		t.Errorf("expected counter %v, got %v", 6, counter)
+	}
+}
+
+// contextCore records the execution context of the last Add action.
+type contextCore struct {
+	testutils.Core
+	ctx *arch.ExecutionContext
+}
+
+func (c *contextCore) Add(action *testutils.ActionData_Add) error {
+	*c.ctx = c.ExecutionContext()
+	return c.Core.Add(action)
+}
+
+func TestCorePrecompileExecutionContext(t *testing.T) {
+	var (
+		schemas  = testutils.NewTestArchSchemas(t)
+		ctx      arch.ExecutionContext
+		pc       = NewCorePrecompile(schemas, func() arch.Core { return &contextCore{ctx: &ctx} })
+		contract = api.NewContract(common.Address{0x01}, common.Address{0x02}, common.Address{0x03}, uint256.NewInt(0))
+		blockCtx = api.NewMockBlockContext()
+	)
+	blockCtx.SetTimestamp(1234)
+	env, _, _, _ := api.NewMockEnvironment(api.WithContract(contract), api.WithBlockCtx(blockCtx))
+
+	input, err := schemas.Actions.ActionToCalldata(&testutils.ActionData_Add{Summand: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pc.Run(env, input); err != nil {
+		t.Fatal(err)
+	}
+	expCtx := arch.ExecutionContext{
+		Caller:         common.Address{0x02},
+		Origin:         common.Address{0x01},
+		BlockTimestamp: 1234,
+	}
+	if ctx != expCtx {
+		t.Errorf("expected context %+v, got %+v", expCtx, ctx)
+	}
+}
+
+func TestCorePrecompilePermissions(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		account = common.Address{0x02}
+		game    = common.Address{0x03}
+		role    = arch.RoleId("adder")
+		db      api.StateDB
+	)
+	schemas.Actions.SetPermissions(map[string]arch.Permission{"Add": {Kind: arch.PermissionRole, Role: "adder"}})
+	pc := NewCorePrecompile(schemas, func() arch.Core { return &testutils.Core{} })
+
+	newEnv := func(caller common.Address) *api.Env {
+		contract := api.NewContract(account, caller, common.Address{0x04}, uint256.NewInt(0))
+		opts := []api.MockEnvOption{api.WithContract(contract)}
+		if db != nil {
+			opts = append(opts, api.WithStateDB(db))
+		}
+		env, statedb, _, _ := api.NewMockEnvironment(opts...)
+		db = statedb
+		return env
+	}
+	run := func(caller common.Address, input []byte) ([]byte, error) {
+		return pc.Run(newEnv(caller), input)
+	}
+	runAction := func(caller common.Address, action arch.Action) error {
+		input, err := schemas.Actions.ActionToCalldata(action)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = run(caller, input)
+		return err
+	}
+	hasRole := func() bool {
+		input := append(crypto.Keccak256([]byte("hasRole(bytes32,address)"))[:4], role.Bytes()...)
+		input = append(input, common.LeftPadBytes(account.Bytes(), 32)...)
+		if !pc.IsStatic(input) {
+			t.Error("expected role read to be static")
+		}
+		ret, err := run(account, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return new(uint256.Int).SetBytes(ret).Uint64() == 1
+	}
+
+	var (
+		tick   = &arch.CanonicalTickAction{}
+		add    = &testutils.ActionData_Add{Summand: 1}
+		grant  = &arch.GrantRoleAction{Role: role, Account: account}
+		revoke = &arch.RevokeRoleAction{Role: role, Account: account}
+	)
+
+	// Restricted actions are rejected if the precompile is not behind a proxy
+	if err := runAction(game, add); !errors.Is(err, arch.ErrUnauthorized) {
+		t.Fatalf("expected %v, got %v", arch.ErrUnauthorized, err)
+	}
+	lib.NewEnvStorageKeyValueStore(newEnv(game)).Set(params.ProxyAdminSlot, common.BytesToHash(game.Bytes()))
+
+	// Open actions can be called by any account
+	if err := runAction(account, tick); err != nil {
+		t.Fatal(err)
+	}
+	// Restricted actions can only be called by the game contract, which authorizes the account calling it
+	if err := runAction(account, add); !errors.Is(err, arch.ErrUnauthorized) {
+		t.Fatalf("expected %v, got %v", arch.ErrUnauthorized, err)
+	}
+	if err := runAction(account, grant); !errors.Is(err, arch.ErrUnauthorized) {
+		t.Fatalf("expected %v, got %v", arch.ErrUnauthorized, err)
+	}
+	if hasRole() {
+		t.Fatal("expected role not to be granted")
+	}
+	if err := runAction(game, grant); err != nil {
+		t.Fatal(err)
+	}
+	if !hasRole() {
+		t.Fatal("expected role to be granted")
+	}
+	if err := runAction(game, add); err != nil {
+		t.Fatal(err)
+	}
+	if err := runAction(game, revoke); err != nil {
+		t.Fatal(err)
+	}
+	if hasRole() {
+		t.Fatal("expected role to be revoked")
+	}
+}
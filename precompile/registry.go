@@ -0,0 +1,190 @@
+package precompile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/concrete-eth/archetype/arch"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete"
+	"github.com/naoina/toml"
+)
+
+var (
+	ErrUnknownGame            = errors.New("unknown game")
+	ErrDuplicateGame          = errors.New("duplicate game")
+	ErrDuplicateAddress       = errors.New("duplicate precompile address")
+	ErrInvalidAddress         = errors.New("invalid precompile address")
+	ErrUnsupportedManifestExt = errors.New("unsupported manifest extension")
+)
+
+// Game holds what is needed to create the precompile of a game.
+type Game struct {
+	Schemas         arch.ArchSchemas
+	CoreConstructor func() arch.Core
+}
+
+// PrecompileManifestEntry places the precompile of a registered game at an address from a given block.
+type PrecompileManifestEntry struct {
+	Game    string    `json:"game" toml:"game"`
+	Address string    `json:"address" toml:"address"`
+	Block   uint64    `json:"block" toml:"block"`
+	Gas     GasConfig `json:"gas" toml:"gas"`
+}
+
+// PrecompileManifest lists the game precompiles to register.
+//
+// Example TOML manifest:
+//
+//	[[precompiles]]
+//	game = "physics"
+//	address = "0x80"
+//	block = 0
+//
+//	[precompiles.gas]
+//	actionGas = 5000
+//	tickGas = 1000
+//	gasLimit = 1000000
+type PrecompileManifest struct {
+	Precompiles []PrecompileManifestEntry `json:"precompiles" toml:"precompiles"`
+}
+
+// LoadPrecompileManifest loads a precompile manifest from a .json or .toml file.
+func LoadPrecompileManifest(path string) (PrecompileManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PrecompileManifest{}, err
+	}
+	var manifest PrecompileManifest
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		err = json.Unmarshal(data, &manifest)
+	case ".toml":
+		err = toml.Unmarshal(data, &manifest)
+	default:
+		return PrecompileManifest{}, fmt.Errorf("%w: %s", ErrUnsupportedManifestExt, ext)
+	}
+	if err != nil {
+		return PrecompileManifest{}, err
+	}
+	return manifest, nil
+}
+
+type registryEntry struct {
+	game      string
+	address   common.Address
+	block     uint64
+	gasConfig GasConfig
+}
+
+// RegistryBuilder builds a precompile registry holding the core precompiles of several games.
+// Games are registered by name and placed at addresses and activation blocks either programmatically
+// or from a manifest.
+type RegistryBuilder struct {
+	games   map[string]Game
+	entries []registryEntry
+}
+
+// NewRegistryBuilder creates a new empty RegistryBuilder.
+func NewRegistryBuilder() *RegistryBuilder {
+	return &RegistryBuilder{
+		games:   make(map[string]Game),
+		entries: make([]registryEntry, 0),
+	}
+}
+
+// RegisterGame registers a game under the given name.
+func (b *RegistryBuilder) RegisterGame(name string, schemas arch.ArchSchemas, coreConstructor func() arch.Core) error {
+	if _, ok := b.games[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateGame, name)
+	}
+	b.games[name] = Game{Schemas: schemas, CoreConstructor: coreConstructor}
+	return nil
+}
+
+// AddPrecompile places the precompile of the named game at address from the given block.
+// A precompile placed at an already used address replaces the previous one from its activation block.
+func (b *RegistryBuilder) AddPrecompile(game string, address common.Address, block uint64) error {
+	return b.AddPrecompileWithGasConfig(game, address, block, GasConfig{})
+}
+
+// AddPrecompileWithGasConfig is like AddPrecompile but sets the gas config of the precompile.
+func (b *RegistryBuilder) AddPrecompileWithGasConfig(game string, address common.Address, block uint64, gasConfig GasConfig) error {
+	if _, ok := b.games[game]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownGame, game)
+	}
+	for _, entry := range b.entries {
+		if entry.address == address && entry.block == block {
+			return fmt.Errorf("%w: %s at block %d", ErrDuplicateAddress, address.Hex(), block)
+		}
+	}
+	b.entries = append(b.entries, registryEntry{game: game, address: address, block: block, gasConfig: gasConfig})
+	return nil
+}
+
+// AddManifest places the precompiles listed in the manifest.
+func (b *RegistryBuilder) AddManifest(manifest PrecompileManifest) error {
+	for _, entry := range manifest.Precompiles {
+		address, err := parseAddress(entry.Address)
+		if err != nil {
+			return err
+		}
+		if err := b.AddPrecompileWithGasConfig(entry.Game, address, entry.Block, entry.Gas); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseAddress parses a hex address, allowing short forms such as 0x80.
+func parseAddress(addressHex string) (common.Address, error) {
+	value, ok := new(big.Int).SetString(strings.TrimPrefix(addressHex, "0x"), 16)
+	if !strings.HasPrefix(addressHex, "0x") || !ok || value.Sign() < 0 || value.BitLen() > 8*common.AddressLength {
+		return common.Address{}, fmt.Errorf("%w: %s", ErrInvalidAddress, addressHex)
+	}
+	return common.BigToAddress(value), nil
+}
+
+// LoadManifest loads a manifest from a .json or .toml file and places the precompiles it lists.
+func (b *RegistryBuilder) LoadManifest(path string) error {
+	manifest, err := LoadPrecompileManifest(path)
+	if err != nil {
+		return err
+	}
+	return b.AddManifest(manifest)
+}
+
+// Build creates a precompile registry with a CorePrecompile for every placed game.
+// The registry holds, from every activation block on, all precompiles activated up to that block.
+func (b *RegistryBuilder) Build() concrete.PrecompileRegistry {
+	entries := make([]registryEntry, len(b.entries))
+	copy(entries, b.entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].block < entries[j].block
+	})
+
+	registry := concrete.NewRegistry()
+	active := make(concrete.PrecompileMap)
+	for ii, entry := range entries {
+		game := b.games[entry.game]
+		pc := NewCorePrecompile(game.Schemas, game.CoreConstructor)
+		pc.SetGasConfig(entry.gasConfig)
+		active[entry.address] = pc
+		if ii+1 < len(entries) && entries[ii+1].block == entry.block {
+			// Add all precompiles activated at the same block at once
+			continue
+		}
+		precompiles := make(concrete.PrecompileMap, len(active))
+		for address, pc := range active {
+			precompiles[address] = pc
+		}
+		registry.AddPrecompiles(entry.block, precompiles)
+	}
+	return registry
+}
@@ -0,0 +1,113 @@
+package precompile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/concrete-eth/archetype/arch"
+	"github.com/concrete-eth/archetype/testutils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func newTestRegistryBuilder(t *testing.T) *RegistryBuilder {
+	b := NewRegistryBuilder()
+	schemas := testutils.NewTestArchSchemas(t)
+	for _, name := range []string{"foo", "bar"} {
+		if err := b.RegisterGame(name, schemas, func() arch.Core { return &testutils.Core{} }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b
+}
+
+func TestRegistryBuilder(t *testing.T) {
+	var (
+		b     = newTestRegistryBuilder(t)
+		addr0 = common.HexToAddress("0x80")
+		addr1 = common.HexToAddress("0x81")
+	)
+
+	if err := b.RegisterGame("foo", arch.ArchSchemas{}, nil); err == nil {
+		t.Error("expected duplicate game error")
+	}
+	if err := b.AddPrecompile("baz", addr0, 0); err == nil {
+		t.Error("expected unknown game error")
+	}
+
+	if err := b.AddPrecompile("foo", addr0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddPrecompile("bar", addr1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddPrecompile("bar", addr1, 10); err == nil {
+		t.Error("expected duplicate address error")
+	}
+
+	registry := b.Build()
+	if _, ok := registry.Precompile(addr0, 0); !ok {
+		t.Error("expected precompile at block 0")
+	}
+	if _, ok := registry.Precompile(addr1, 9); ok {
+		t.Error("unexpected precompile before activation block")
+	}
+	// Earlier precompiles stay active after later activations
+	for _, addr := range []common.Address{addr0, addr1} {
+		if _, ok := registry.Precompile(addr, 10); !ok {
+			t.Errorf("expected precompile at %v at block 10", addr)
+		}
+	}
+}
+
+func TestRegistryBuilderManifest(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		tomlPath = filepath.Join(dir, "manifest.toml")
+		jsonPath = filepath.Join(dir, "manifest.json")
+	)
+
+	tomlManifest := `
+[[precompiles]]
+game = "foo"
+address = "0x80"
+block = 0
+
+[[precompiles]]
+game = "bar"
+address = "0x81"
+block = 5
+
+[precompiles.gas]
+actionGas = 100
+`
+	jsonManifest := `{"precompiles": [{"game": "foo", "address": "0x80", "block": 0}, {"game": "bar", "address": "0x81", "block": 5, "gas": {"actionGas": 100}}]}`
+
+	if err := os.WriteFile(tomlPath, []byte(tomlManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(jsonPath, []byte(jsonManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{tomlPath, jsonPath} {
+		b := newTestRegistryBuilder(t)
+		if err := b.LoadManifest(path); err != nil {
+			t.Fatal(err)
+		}
+		registry := b.Build()
+		if addrs := registry.PrecompiledAddresses(5); len(addrs) != 2 {
+			t.Errorf("%s: expected %v precompiles at block 5, got %v", filepath.Base(path), 2, len(addrs))
+		}
+		if _, ok := registry.Precompile(common.HexToAddress("0x81"), 4); ok {
+			t.Errorf("%s: unexpected precompile before activation block", filepath.Base(path))
+		}
+		if pc, _ := registry.Precompile(common.HexToAddress("0x81"), 5); pc.(*CorePrecompile).gasConfig.ActionGas != 100 {
+			t.Errorf("%s: expected gas config to be loaded", filepath.Base(path))
+		}
+	}
+
+	if err := newTestRegistryBuilder(t).LoadManifest(filepath.Join(dir, "manifest.yaml")); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,216 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/concrete-eth/archetype/arch"
+	"github.com/concrete-eth/archetype/kvstore"
+	"github.com/concrete-eth/archetype/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrActionFailed = errors.New("action failed in replay")
+	errDiverged     = errors.New("diverged")
+)
+
+// Divergence describes a storage slot whose replayed value differs from the onchain value.
+type Divergence struct {
+	BlockNumber uint64      `json:"blockNumber"`
+	Key         common.Hash `json:"key"` // Zero if the slot was never written during the replay
+	KeyHash     common.Hash `json:"keyHash"`
+	Expected    common.Hash `json:"expected"` // Onchain value
+	Actual      common.Hash `json:"actual"`   // Replayed value
+}
+
+func (d *Divergence) String() string {
+	return fmt.Sprintf("block %d slot %s (key hash %s): expected %s, got %s", d.BlockNumber, d.Key.Hex(), d.KeyHash.Hex(), d.Expected.Hex(), d.Actual.Hex())
+}
+
+// Replayer executes action batches against a fresh core the same way CorePrecompile does:
+// every action runs on a new core and its writes are discarded if it fails.
+type Replayer struct {
+	schemas arch.ArchSchemas
+	newCore func() arch.Core
+	kv      *kvstore.HashedMemoryKeyValueStore
+	keys    map[common.Hash]common.Hash // Key hash -> key of every slot written during the replay
+}
+
+// NewReplayer creates a new Replayer with empty storage.
+func NewReplayer(schemas arch.ArchSchemas, newCore func() arch.Core) *Replayer {
+	return &Replayer{
+		schemas: schemas,
+		newCore: newCore,
+		kv:      kvstore.NewHashedMemoryKeyValueStore(),
+		keys:    make(map[common.Hash]common.Hash),
+	}
+}
+
+// KV returns the replayed storage.
+func (r *Replayer) KV() *kvstore.HashedMemoryKeyValueStore {
+	return r.kv
+}
+
+func sortHashes(hashes []common.Hash) {
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i].Bytes(), hashes[j].Bytes()) < 0
+	})
+}
+
+// ApplyBatch executes the actions in the batch and returns the keys written, sorted.
+// Actions in a batch were executed successfully onchain, so an action failing in the replay is an error.
+func (r *Replayer) ApplyBatch(batch arch.ActionBatch) ([]common.Hash, error) {
+	blockKv := kvstore.NewStagedKeyValueStore(r.kv)
+	for ii, action := range batch.Actions {
+		skv := kvstore.NewStagedKeyValueStore(blockKv)
+		core := r.newCore()
+		core.SetKV(skv)
+		core.SetBlockNumber(batch.BlockNumber)
+		if c, ok := core.(arch.ISetExecutionContext); ok {
+			c.SetExecutionContext(batch.Context(ii))
+		}
+		if err := r.schemas.Actions.ExecuteAction(action, core); err != nil {
+			return nil, fmt.Errorf("%w: block %d, action %d: %v", ErrActionFailed, batch.BlockNumber, ii, err)
+		}
+		skv.Commit()
+	}
+	keys := blockKv.CommitWithJournal().Keys()
+	for _, key := range keys {
+		r.keys[crypto.Keccak256Hash(key.Bytes())] = key
+	}
+	sortHashes(keys)
+	return keys, nil
+}
+
+// Replay fetches and applies the action batches of every block from fromBlock to toBlock, inclusive,
+// emitted by the core contract at coreAddress. The replayed storage must hold the storage of the core
+// contract as of the block before fromBlock, i.e., fromBlock must be zero for a new Replayer.
+// The execution context of every action is fetched, so cores reading it are replayed faithfully.
+// If check is not nil, it is called after every block with the keys written in it. Replay stops and
+// returns the divergence at the first block check reports one for.
+func (r *Replayer) Replay(
+	ethcli rpc.EthCli,
+	coreAddress common.Address,
+	fromBlock, toBlock uint64,
+	check func(blockNumber uint64, keys []common.Hash) (*Divergence, error),
+) (*Divergence, error) {
+	var divergence *Divergence
+	err := rpc.FetchActionBatches(ethcli, r.schemas.Actions, coreAddress, fromBlock, toBlock, true, func(batch arch.ActionBatchWithLogs) error {
+		keys, err := r.ApplyBatch(batch.ActionBatch)
+		if err != nil {
+			return err
+		}
+		if check == nil {
+			return nil
+		}
+		if divergence, err = check(batch.BlockNumber, keys); err != nil {
+			return err
+		} else if divergence != nil {
+			return errDiverged
+		}
+		return nil
+	})
+	if err != nil && err != errDiverged {
+		return nil, err
+	}
+	return divergence, nil
+}
+
+// CompareWithStorage compares the replayed values of the given keys with the storage of the core contract
+// at coreAddress as of blockNumber, and returns the first diverging slot, if any.
+func (r *Replayer) CompareWithStorage(ethcli rpc.EthCli, coreAddress common.Address, blockNumber uint64, keys []common.Hash) (*Divergence, error) {
+	for _, key := range keys {
+		ctx, cancel := context.WithTimeout(context.Background(), rpc.StandardTimeout)
+		data, err := ethcli.StorageAt(ctx, coreAddress, key, new(big.Int).SetUint64(blockNumber))
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		expected := common.BytesToHash(data)
+		if actual := r.kv.Get(key); actual != expected {
+			return &Divergence{
+				BlockNumber: blockNumber,
+				Key:         key,
+				KeyHash:     crypto.Keccak256Hash(key.Bytes()),
+				Expected:    expected,
+				Actual:      actual,
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// CompareWithSnapshot compares the whole replayed storage with a compressed snapshot blob taken at
+// blockNumber, and returns the diverging slot with the lowest key hash, if any.
+func (r *Replayer) CompareWithSnapshot(blockNumber uint64, blobZip []byte) (*Divergence, error) {
+	snapshotKv, err := rpc.LoadSnapshotBlob(blobZip)
+	if err != nil {
+		return nil, err
+	}
+	diverging := make([]common.Hash, 0)
+	snapshotKv.ForEach(func(keyHash, value common.Hash) bool {
+		if r.kv.GetByKeyHash(keyHash) != value {
+			diverging = append(diverging, keyHash)
+		}
+		return true
+	})
+	r.kv.ForEach(func(keyHash, value common.Hash) bool {
+		// Slots missing from the snapshot hold the zero value
+		if !snapshotKv.HasByKeyHash(keyHash) && value != (common.Hash{}) {
+			diverging = append(diverging, keyHash)
+		}
+		return true
+	})
+	if len(diverging) == 0 {
+		return nil, nil
+	}
+	sortHashes(diverging)
+	keyHash := diverging[0]
+	return &Divergence{
+		BlockNumber: blockNumber,
+		Key:         r.keys[keyHash],
+		KeyHash:     keyHash,
+		Expected:    snapshotKv.GetByKeyHash(keyHash),
+		Actual:      r.kv.GetByKeyHash(keyHash),
+	}, nil
+}
+
+// VerifyWithStorage replays the blocks from genesis to toBlock, inclusive, on a fresh core and
+// compares every slot written in each block with the onchain storage of the core contract as of
+// that block. It returns the first diverging block and slot, or nil if the replay matches.
+func VerifyWithStorage(
+	ethcli rpc.EthCli,
+	schemas arch.ArchSchemas,
+	newCore func() arch.Core,
+	coreAddress common.Address,
+	toBlock uint64,
+) (*Divergence, error) {
+	r := NewReplayer(schemas, newCore)
+	return r.Replay(ethcli, coreAddress, 0, toBlock, func(blockNumber uint64, keys []common.Hash) (*Divergence, error) {
+		return r.CompareWithStorage(ethcli, coreAddress, blockNumber, keys)
+	})
+}
+
+// VerifyWithSnapshot replays the blocks from genesis to the snapshot block, inclusive, on a fresh
+// core and compares the resulting storage with a compressed snapshot blob of the core contract storage.
+// It returns the first diverging slot, or nil if the replay matches.
+func VerifyWithSnapshot(
+	ethcli rpc.EthCli,
+	schemas arch.ArchSchemas,
+	newCore func() arch.Core,
+	coreAddress common.Address,
+	snapshotBlock uint64,
+	blobZip []byte,
+) (*Divergence, error) {
+	r := NewReplayer(schemas, newCore)
+	if _, err := r.Replay(ethcli, coreAddress, 0, snapshotBlock, nil); err != nil {
+		return nil, err
+	}
+	return r.CompareWithSnapshot(snapshotBlock, blobZip)
+}
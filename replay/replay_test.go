@@ -0,0 +1,267 @@
+package replay
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/concrete-eth/archetype/arch"
+	"github.com/concrete-eth/archetype/precompile"
+	"github.com/concrete-eth/archetype/rpc"
+	"github.com/concrete-eth/archetype/simulated"
+	"github.com/concrete-eth/archetype/snapshot/utils"
+	"github.com/concrete-eth/archetype/testutils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/concrete"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	chainId       = big.NewInt(1337)
+	pcAddress     = common.HexToAddress("0x1234")
+	privateKeyHex = "b6caec81f24a057222a99f925671a845f5f27944e627e4097e5d7689b8981511"
+)
+
+// faultyCore adds one more than requested, diverging from the onchain core.
+type faultyCore struct {
+	testutils.Core
+}
+
+func (c *faultyCore) Add(action *testutils.ActionData_Add) error {
+	return c.Core.Add(&testutils.ActionData_Add{Summand: action.Summand + 1})
+}
+
+func newCore() arch.Core       { return &testutils.Core{} }
+func newFaultyCore() arch.Core { return &faultyCore{} }
+
+// newTestChain returns a simulated chain where blocks 2 and 3 include an action.
+func newTestChain(t *testing.T) *simulated.SimulatedBackend {
+	schemas := testutils.NewTestArchSchemas(t)
+
+	pc := precompile.NewCorePrecompile(schemas, newCore)
+	registry := concrete.NewRegistry()
+	registry.AddPrecompile(0, pcAddress, pc)
+
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alloc := types.GenesisAlloc{opts.From: {Balance: big.NewInt(1e18)}}
+	ethcli := simulated.NewSimulatedBackend(alloc, 1e8, registry)
+
+	sender := rpc.NewActionSender(ethcli, schemas.Actions, nil, pcAddress, opts.From, 0, opts.Signer)
+	ethcli.Commit()
+	for _, summand := range []int16{1, 2} {
+		if _, err := sender.SendAction(&testutils.ActionData_Add{Summand: summand}); err != nil {
+			t.Fatal(err)
+		}
+		ethcli.Commit()
+	}
+	ethcli.Commit()
+	return ethcli
+}
+
+func TestVerifyWithStorage(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		ethcli  = newTestChain(t)
+	)
+
+	divergence, err := VerifyWithStorage(ethcli, schemas, newCore, pcAddress, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if divergence != nil {
+		t.Fatalf("unexpected divergence: %v", divergence)
+	}
+
+	divergence, err = VerifyWithStorage(ethcli, schemas, newFaultyCore, pcAddress, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if divergence == nil {
+		t.Fatal("expected divergence")
+	}
+	if divergence.BlockNumber != 2 {
+		t.Errorf("expected divergence at block %v, got %v", 2, divergence.BlockNumber)
+	}
+	if divergence.Expected == divergence.Actual {
+		t.Errorf("unexpected divergence values: %v", divergence)
+	}
+}
+
+func TestVerifyWithSnapshot(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		ethcli  = newTestChain(t)
+	)
+
+	// Build a snapshot blob from the storage of a correct replay
+	r := NewReplayer(schemas, newCore)
+	if _, err := r.Replay(ethcli, pcAddress, 0, 4, nil); err != nil {
+		t.Fatal(err)
+	}
+	blob := make([]byte, 0)
+	r.KV().ForEach(func(keyHash, value common.Hash) bool {
+		blob = append(blob, keyHash.Bytes()...)
+		blob = append(blob, value.Bytes()...)
+		return true
+	})
+	blobZip, err := utils.Compress(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	divergence, err := VerifyWithSnapshot(ethcli, schemas, newCore, pcAddress, 4, blobZip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if divergence != nil {
+		t.Fatalf("unexpected divergence: %v", divergence)
+	}
+
+	divergence, err = VerifyWithSnapshot(ethcli, schemas, newFaultyCore, pcAddress, 4, blobZip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if divergence == nil {
+		t.Fatal("expected divergence")
+	}
+	if divergence.Key == (common.Hash{}) {
+		t.Error("expected key of replayed slot")
+	}
+}
+
+// actionData_AddV2 is the Add action of a second version of the test schemas that adds the summand
+// a number of times.
+type actionData_AddV2 struct {
+	Summand int16 `json:"summand"`
+	Times   uint8 `json:"times"`
+}
+
+const addV2ABIJson = `[
+	{"type":"function","name":"add","inputs":[{"name":"action","type":"tuple","components":[{"name":"summand","type":"int16"},{"name":"times","type":"uint8"}]}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"tick","inputs":[],"outputs":[],"stateMutability":"nonpayable"}
+]`
+
+type coreV2 struct {
+	testutils.Core
+}
+
+func (c *coreV2) Add(action *actionData_AddV2) error {
+	for ii := uint8(0); ii < action.Times; ii++ {
+		if err := c.Core.Add(&testutils.ActionData_Add{Summand: action.Summand}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newCoreV2() arch.Core { return &coreV2{} }
+
+const versionBumpBlock = 4
+
+// newTestSchemasV2 returns the test schemas with the second version of the actions.
+func newTestSchemasV2(t *testing.T) arch.ArchSchemas {
+	actions, err := arch.NewActionSchemasFromRaw(
+		addV2ABIJson,
+		`{"add":{"schema":{"summand":"int16","times":"uint8"}}}`,
+		map[string]reflect.Type{"Add": reflect.TypeOf(actionData_AddV2{})},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas := testutils.NewTestArchSchemas(t)
+	schemas.Actions = actions
+	return schemas
+}
+
+// newVersionedTestChain returns a simulated chain where blocks 2 and 3 include an action of the first
+// version of the test schemas, and blocks 4 and 5 include an action of the second version, which the
+// core precompile is upgraded to at block 4.
+func newVersionedTestChain(t *testing.T) *simulated.SimulatedBackend {
+	var (
+		schemas   = testutils.NewTestArchSchemas(t)
+		schemasV2 = newTestSchemasV2(t)
+	)
+
+	registry := concrete.NewRegistry()
+	registry.AddPrecompile(0, pcAddress, precompile.NewCorePrecompile(schemas, newCore))
+	registry.AddPrecompile(versionBumpBlock, pcAddress, precompile.NewCorePrecompile(schemasV2, newCoreV2))
+
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alloc := types.GenesisAlloc{opts.From: {Balance: big.NewInt(1e18)}}
+	ethcli := simulated.NewSimulatedBackend(alloc, 1e8, registry)
+
+	sender := rpc.NewActionSender(ethcli, schemas.Actions, nil, pcAddress, opts.From, 0, opts.Signer)
+	ethcli.Commit()
+	for _, summand := range []int16{1, 2} {
+		if _, err := sender.SendAction(&testutils.ActionData_Add{Summand: summand}); err != nil {
+			t.Fatal(err)
+		}
+		ethcli.Commit()
+	}
+	senderV2 := rpc.NewActionSender(ethcli, schemasV2.Actions, nil, pcAddress, opts.From, 2, opts.Signer)
+	for _, summand := range []int16{3, 4} {
+		if _, err := senderV2.SendAction(&actionData_AddV2{Summand: summand, Times: 2}); err != nil {
+			t.Fatal(err)
+		}
+		ethcli.Commit()
+	}
+	ethcli.Commit()
+	return ethcli
+}
+
+func TestVerifyAcrossVersions(t *testing.T) {
+	ethcli := newVersionedTestChain(t)
+
+	newSchemas := func(converter arch.ActionConverter) arch.ArchSchemas {
+		v1 := testutils.NewTestArchSchemas(t).Actions
+		v1.SetActionConverter(converter)
+		actions, err := arch.NewVersionedActionSchemas(map[uint64]arch.ActionSchemas{
+			0:                v1,
+			versionBumpBlock: newTestSchemasV2(t).Actions,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		schemas := testutils.NewTestArchSchemas(t)
+		schemas.Actions = actions
+		return schemas
+	}
+
+	// Actions of the first version are converted and replayed on the core of the second
+	schemas := newSchemas(func(action arch.Action) (arch.Action, error) {
+		if action, ok := action.(*testutils.ActionData_Add); ok {
+			return &actionData_AddV2{Summand: action.Summand, Times: 1}, nil
+		}
+		return action, nil
+	})
+	divergence, err := VerifyWithStorage(ethcli, schemas, newCoreV2, pcAddress, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if divergence != nil {
+		t.Fatalf("unexpected divergence: %v", divergence)
+	}
+
+	// Without a converter the core of the second version cannot execute actions of the first
+	schemas = newSchemas(nil)
+	if _, err := VerifyWithStorage(ethcli, schemas, newCoreV2, pcAddress, 6); !errors.Is(err, ErrActionFailed) {
+		t.Errorf("expected %v, got %v", ErrActionFailed, err)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/concrete-eth/archetype/params"
 	"github.com/concrete-eth/archetype/utils"
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/concrete/lib"
@@ -29,11 +30,23 @@ import (
 // )
 
 var (
-	StandardTimeout        = 5 * time.Second // Standard timeout for RPC requests
-	BlockQueryLimit uint64 = 256             // Maximum number of blocks to query in a single request
-	HeaderChanSize         = 4               // Size of the header channel
+	StandardTimeout             = 5 * time.Second // Standard timeout for RPC requests
+	BlockQueryLimit      uint64 = 256             // Maximum number of blocks to query in a single request
+	HeaderChanSize              = 4               // Size of the header channel
+	MaxTableReadsPerCall        = 256             // Maximum number of table reads batched in a single call
 )
 
+// multiActionABI is the ABI of the multi-action method of the entrypoint contract, which is not part of
+// the actions ABI.
+var multiActionABI = func() abi.ABI {
+	abiJson := fmt.Sprintf(`[{"type":"function","name":"%s","inputs":[{"name":"actionIds","type":"uint32[]"},{"name":"actionCount","type":"uint8[]"},{"name":"actionData","type":"bytes[]"}],"outputs":[],"stateMutability":"nonpayable"}]`, params.MultiActionMethodName)
+	ABI, err := abi.JSON(strings.NewReader(abiJson))
+	if err != nil {
+		panic(err)
+	}
+	return ABI
+}()
+
 func getBlockNumber(ethcli EthCli) (uint64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
 	defer cancel()
@@ -58,6 +71,103 @@ func getHeadHeader(ethcli EthCli) (*types.Header, error) {
 	return ethcli.HeaderByNumber(ctx, nil)
 }
 
+func getHeaderByNumber(ethcli EthCli, blockNumber uint64) (*types.Header, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
+	defer cancel()
+	return ethcli.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+}
+
+func getActionLogs(ethcli EthCli, coreAddress common.Address, fromBlock, toBlock uint64) ([]types.Log, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
+	defer cancel()
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{coreAddress},
+		Topics:    [][]common.Hash{{params.ActionExecutedEventID}},
+	}
+	return ethcli.FilterLogs(ctx, query)
+}
+
+func getHeaderByHash(ethcli EthCli, hash common.Hash) (*types.Header, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
+	defer cancel()
+	return ethcli.HeaderByHash(ctx, hash)
+}
+
+func getProxyAdmin(ethcli EthCli, proxyAddress common.Address, blockNumber uint64) (common.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
+	defer cancel()
+	data, err := ethcli.StorageAt(ctx, proxyAddress, params.ProxyAdminSlot, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.BytesToAddress(data), nil
+}
+
+func getTransactionByHash(ethcli EthCli, hash common.Hash) (*types.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
+	defer cancel()
+	tx, _, err := ethcli.TransactionByHash(ctx, hash)
+	return tx, err
+}
+
+func getGameOwner(ethcli EthCli, gameAddress common.Address) (common.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
+	defer cancel()
+	ret, err := ethcli.CallContract(ctx, ethereum.CallMsg{To: &gameAddress, Data: params.OwnerSelector}, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(ret) < 32 {
+		return common.Address{}, fmt.Errorf("invalid owner() return data: %x", ret)
+	}
+	return common.BytesToAddress(ret[:32]), nil
+}
+
+// getActionContexts returns the execution context of the actions logged in the given logs of a single
+// block, as seen by the core when they were executed.
+// The caller is the admin of the proxy of the core, i.e., the game contract, as the proxy only forwards
+// calls from its admin, and zero if the core is not behind a proxy. The origin is zero if the sender of
+// the transaction cannot be recovered, e.g., for deposit transactions.
+func getActionContexts(ethcli EthCli, coreAddress common.Address, logs []types.Log) ([]arch.ExecutionContext, error) {
+	if len(logs) == 0 {
+		return nil, nil
+	}
+	header, err := getHeaderByHash(ethcli, logs[0].BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := getProxyAdmin(ethcli, coreAddress, logs[0].BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		contexts = make([]arch.ExecutionContext, len(logs))
+		origins  = make(map[common.Hash]common.Address) // Tx hash -> origin
+	)
+	for ii, log := range logs {
+		origin, ok := origins[log.TxHash]
+		if !ok {
+			tx, err := getTransactionByHash(ethcli, log.TxHash)
+			if err != nil {
+				return nil, err
+			}
+			if sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+				origin = sender
+			}
+			origins[log.TxHash] = origin
+		}
+		contexts[ii] = arch.ExecutionContext{
+			Caller:         admin,
+			Origin:         origin,
+			BlockTimestamp: header.Time,
+			TxHash:         log.TxHash,
+		}
+	}
+	return contexts, nil
+}
+
 func getGasPrice(ethcli EthCli) (gasFeeCap, gasTipCap *big.Int, err error) {
 	// Start two goroutines to get the head header and suggested gas tip cap concurrently
 
@@ -101,6 +211,9 @@ func getGasPrice(ethcli EthCli) (gasFeeCap, gasTipCap *big.Int, err error) {
 }
 
 // ActionBatchSubscription is a subscription to action batches emitted by a core contract.
+// The subscription keeps the hashes of recently sent blocks and, when a chain reorganization orphans
+// any of them, re-sends the canonical batches with ReorgDepth set to the number of orphaned batches.
+// Reorgs deeper than the reorg depth limit fail the subscription with client.ErrReorgTooDeep.
 type ActionBatchSubscription struct {
 	ethcli               EthCli
 	actionSchemas        arch.ActionSchemas
@@ -111,16 +224,30 @@ type ActionBatchSubscription struct {
 	closeUnsubOnce       sync.Once
 	closeErrOnce         sync.Once
 	unsubscribed         bool
+	reorgDepthLimit      uint64                 // Maximum number of blocks that can be orphaned by a reorg
+	blockHashes          map[uint64]common.Hash // block number -> hash of recently sent blocks
+	pendingReorgDepth    uint64                 // Reorg depth to attach to the next batch sent
+	headBN               uint64                 // Number of the last known head block
+	startingBN           uint64                 // Number of the first block sent
+	fetchContexts        bool                   // Whether to fetch the execution context of every action
 }
 
 var _ ethereum.Subscription = (*ActionBatchSubscription)(nil)
 
 // SubscribeActionBatches subscribes to action batches emitted by the core contract at coreAddress.
+// Action logs are decoded with the version of the action schemas active at the block they were emitted at.
+// reorgDepthLimit is the maximum number of sent blocks that can be orphaned by a reorg, usually the
+// maximum rollback depth of the consumer, e.g., client.MaxRollbackDepth.
+// If fetchContexts is true, batches carry the execution context of every action, which costs a header,
+// a storage and a transaction request per block with actions, so it should only be enabled if the core
+// reads the execution context.
 func SubscribeActionBatches(
 	ethcli EthCli,
 	actionSchemas arch.ActionSchemas,
 	coreAddress common.Address,
 	startingBlockNumber uint64,
+	reorgDepthLimit uint64,
+	fetchContexts bool,
 	actionBatchesChan chan<- arch.ActionBatchWithLogs,
 ) *ActionBatchSubscription {
 	sub := &ActionBatchSubscription{
@@ -130,6 +257,10 @@ func SubscribeActionBatches(
 		actionBatchesOutChan: actionBatchesChan,
 		unsubChan:            make(chan struct{}),
 		errChan:              make(chan error, 1),
+		reorgDepthLimit:      reorgDepthLimit,
+		blockHashes:          make(map[uint64]common.Hash),
+		startingBN:           startingBlockNumber,
+		fetchContexts:        fetchContexts,
 	}
 	go sub.runSubscription(startingBlockNumber)
 	return sub
@@ -180,15 +311,7 @@ func (s *ActionBatchSubscription) runSubscription(startingBlock uint64) {
 }
 
 func (s *ActionBatchSubscription) getLogs(fromBlock, toBlock uint64) ([]types.Log, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
-	defer cancel()
-	query := ethereum.FilterQuery{
-		FromBlock: new(big.Int).SetUint64(fromBlock),
-		ToBlock:   new(big.Int).SetUint64(toBlock),
-		Addresses: []common.Address{s.coreAddress},
-		Topics:    [][]common.Hash{{params.ActionExecutedEventID}},
-	}
-	return s.ethcli.FilterLogs(ctx, query)
+	return getActionLogs(s.ethcli, s.coreAddress, fromBlock, toBlock)
 }
 
 // sync sends an action batch for every block from startingBlock to the head block.
@@ -215,6 +338,7 @@ func (s *ActionBatchSubscription) syncToHead(startingBlock uint64) (uint64, erro
 	if err != nil {
 		return startingBlock, err
 	}
+	s.headBN = headBN
 
 	for oldestUnsyncedBN < headBN {
 		if s.hasUnsubscribed() {
@@ -231,6 +355,7 @@ func (s *ActionBatchSubscription) syncToHead(startingBlock uint64) (uint64, erro
 				if err != nil {
 					return oldestUnsyncedBN, err
 				}
+				s.headBN = headBN
 				toBN = utils.Min(headBN, toBN)
 			}
 		}
@@ -260,6 +385,15 @@ func (s *ActionBatchSubscription) syncAtHead(startingBlock uint64) (uint64, erro
 	}
 	defer headersSub.Unsubscribe()
 
+	// Catch up with any block produced before the subscription was established
+	header, err := getHeadHeader(s.ethcli)
+	if err != nil {
+		return oldestUnsyncedBN, err
+	}
+	if oldestUnsyncedBN, err = s.syncToHeader(header, oldestUnsyncedBN); err != nil {
+		return oldestUnsyncedBN, err
+	}
+
 	for {
 		select {
 		case err := <-headersSub.Err():
@@ -270,22 +404,129 @@ func (s *ActionBatchSubscription) syncAtHead(startingBlock uint64) (uint64, erro
 			if s.hasUnsubscribed() {
 				return oldestUnsyncedBN, nil
 			}
-			if header.Number.Uint64() < oldestUnsyncedBN {
-				continue
-			}
-			// Fetch logs from oldestUnsyncedBN to head
-			logs, err := s.getLogs(oldestUnsyncedBN, header.Number.Uint64())
-			if err != nil {
-				return oldestUnsyncedBN, err
-			}
-			// Process logs
-			if oldestUnsyncedBN, err = s.processLogs(logs, oldestUnsyncedBN, header.Number.Uint64()); err != nil {
+			if oldestUnsyncedBN, err = s.syncToHeader(header, oldestUnsyncedBN); err != nil {
 				return oldestUnsyncedBN, err
 			}
 		}
 	}
 }
 
+// syncToHeader sends an action batch for every block from oldestUnsyncedBN to the given head header,
+// first unwinding any already sent block orphaned by a chain reorganization.
+func (s *ActionBatchSubscription) syncToHeader(header *types.Header, oldestUnsyncedBN uint64) (uint64, error) {
+	// Detect and handle chain reorganizations
+	oldestUnsyncedBN, err := s.checkReorg(header, oldestUnsyncedBN)
+	if err != nil {
+		return oldestUnsyncedBN, err
+	}
+	if header.Number.Uint64() < oldestUnsyncedBN {
+		return oldestUnsyncedBN, nil
+	}
+	s.headBN = header.Number.Uint64()
+	s.recordBlockHash(header.Number.Uint64(), header.Hash())
+	// Fetch logs from oldestUnsyncedBN to head
+	logs, err := s.getLogs(oldestUnsyncedBN, header.Number.Uint64())
+	if err != nil {
+		return oldestUnsyncedBN, err
+	}
+	// Process logs
+	return s.processLogs(logs, oldestUnsyncedBN, header.Number.Uint64())
+}
+
+// recordBlockHash stores the hash of a block, discarding hashes older than reorgDepthLimit blocks.
+func (s *ActionBatchSubscription) recordBlockHash(blockNumber uint64, hash common.Hash) {
+	if hash == (common.Hash{}) {
+		return
+	}
+	s.blockHashes[blockNumber] = hash
+	if blockNumber >= s.reorgDepthLimit {
+		delete(s.blockHashes, blockNumber-s.reorgDepthLimit)
+	}
+}
+
+// canonicalHash returns the hash of the canonical block at the given height, using the given head header
+// to avoid fetching the head and its parent.
+func (s *ActionBatchSubscription) canonicalHash(head *types.Header, blockNumber uint64) (common.Hash, error) {
+	if headBN := head.Number.Uint64(); blockNumber == headBN {
+		return head.Hash(), nil
+	} else if blockNumber+1 == headBN {
+		return head.ParentHash, nil
+	}
+	header, err := getHeaderByNumber(s.ethcli, blockNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
+// checkReorg compares the hashes of the already sent blocks with the canonical chain ending at head.
+// If any sent block was orphaned, it returns the number of the first orphaned block, which becomes the
+// new oldest unsynced block, and schedules the next sent batch to carry the reorg depth.
+// Blocks with unknown hashes are assumed to be canonical.
+func (s *ActionBatchSubscription) checkReorg(head *types.Header, oldestUnsyncedBN uint64) (uint64, error) {
+	if oldestUnsyncedBN == 0 {
+		// Nothing has been sent yet
+		return oldestUnsyncedBN, nil
+	}
+	var (
+		headBN     = head.Number.Uint64()
+		lastSentBN = oldestUnsyncedBN - 1
+		forkBN     = utils.Min(lastSentBN, headBN) + 1 // First orphaned block
+	)
+	if headBN <= lastSentBN {
+		if storedHash, ok := s.blockHashes[headBN]; !ok || storedHash == head.Hash() {
+			// Stale or unverifiable header
+			return oldestUnsyncedBN, nil
+		}
+	}
+	for forkBN > 0 {
+		bn := forkBN - 1
+		storedHash, ok := s.blockHashes[bn]
+		if !ok {
+			break
+		}
+		hash, err := s.canonicalHash(head, bn)
+		if err != nil {
+			return oldestUnsyncedBN, err
+		}
+		if hash == storedHash {
+			break
+		}
+		forkBN = bn
+	}
+	if forkBN > lastSentBN {
+		// All sent blocks are canonical
+		return oldestUnsyncedBN, nil
+	}
+	reorgDepth := oldestUnsyncedBN - forkBN
+	if s.pendingReorgDepth+reorgDepth > s.reorgDepthLimit {
+		return oldestUnsyncedBN, client.ErrReorgTooDeep
+	}
+	for bn := forkBN; bn <= lastSentBN; bn++ {
+		delete(s.blockHashes, bn)
+	}
+	s.pendingReorgDepth += reorgDepth
+	return forkBN, nil
+}
+
+// recordSentBlockHash records the hash of a block about to be sent, so reorgs orphaning it are detected
+// whether or not it has any logs. The hash is taken from the logs if any, to match the sent batch.
+func (s *ActionBatchSubscription) recordSentBlockHash(blockNumber uint64, logBatch []types.Log) error {
+	if len(logBatch) > 0 {
+		s.recordBlockHash(blockNumber, logBatch[0].BlockHash)
+		return nil
+	}
+	if _, ok := s.blockHashes[blockNumber]; ok {
+		return nil
+	}
+	header, err := getHeaderByNumber(s.ethcli, blockNumber)
+	if err != nil {
+		return err
+	}
+	s.recordBlockHash(blockNumber, header.Hash())
+	return nil
+}
+
 func (s *ActionBatchSubscription) processLogs(logs []types.Log, from, to uint64) (uint64, error) {
 	oldestUnsyncedBN := from
 	logBatch := make([]types.Log, 0)
@@ -312,6 +553,9 @@ func (s *ActionBatchSubscription) processLogs(logs []types.Log, from, to uint64)
 	return oldestUnsyncedBN, nil
 }
 
+// sendLogBatch sends the action batch of the given block, recording its hash if it is within the reorg
+// depth limit of the last known head block or is the first block sent, e.g., the checkpoint block a
+// client resumes from.
 func (s *ActionBatchSubscription) sendLogBatch(blockNumber uint64, logBatch []types.Log) error {
 	// Process logBatch into action batch and send
 	actions := make([]arch.Action, 0, len(logBatch))
@@ -323,11 +567,26 @@ func (s *ActionBatchSubscription) sendLogBatch(blockNumber uint64, logBatch []ty
 		actions = append(actions, action)
 	}
 	actionBatchWithLogs := arch.NewActionBatchWithLogs(blockNumber, actions, logBatch)
+	if s.fetchContexts {
+		contexts, err := getActionContexts(s.ethcli, s.coreAddress, logBatch)
+		if err != nil {
+			return err
+		}
+		actionBatchWithLogs.Contexts = contexts
+	}
+	if blockNumber == s.startingBN || blockNumber+s.reorgDepthLimit > s.headBN {
+		if err := s.recordSentBlockHash(blockNumber, logBatch); err != nil {
+			return err
+		}
+	}
+	actionBatchWithLogs.BlockHash = s.blockHashes[blockNumber]
+	actionBatchWithLogs.ReorgDepth = s.pendingReorgDepth
 	select {
 	case <-s.unsubChan:
 		s.unsubscribe()
 		return nil
 	case s.actionBatchesOutChan <- actionBatchWithLogs:
+		s.pendingReorgDepth = 0
 	}
 	return nil
 }
@@ -346,6 +605,57 @@ func (s *ActionBatchSubscription) Err() <-chan error {
 	return s.errChan
 }
 
+// FetchActionBatches calls fn with the action batch of every block from fromBlock to toBlock, inclusive,
+// emitted by the core contract at coreAddress.
+// Logs are fetched in ranges of up to BlockQueryLimit blocks. Iteration stops at the first error.
+// If fetchContexts is true, batches carry the execution context of every action. See SubscribeActionBatches.
+func FetchActionBatches(
+	ethcli EthCli,
+	actionSchemas arch.ActionSchemas,
+	coreAddress common.Address,
+	fromBlock, toBlock uint64,
+	fetchContexts bool,
+	fn func(batch arch.ActionBatchWithLogs) error,
+) error {
+	for rangeStart := fromBlock; rangeStart <= toBlock; rangeStart += BlockQueryLimit {
+		rangeEnd := utils.Min(rangeStart+BlockQueryLimit-1, toBlock)
+		logs, err := getActionLogs(ethcli, coreAddress, rangeStart, rangeEnd)
+		if err != nil {
+			return err
+		}
+		logIdx := 0
+		for bn := rangeStart; bn <= rangeEnd; bn++ {
+			actions := make([]arch.Action, 0)
+			logBatch := make([]types.Log, 0)
+			for ; logIdx < len(logs) && logs[logIdx].BlockNumber == bn; logIdx++ {
+				action, err := actionSchemas.LogToAction(logs[logIdx])
+				if err != nil {
+					return err
+				}
+				actions = append(actions, action)
+				logBatch = append(logBatch, logs[logIdx])
+			}
+			batch := arch.NewActionBatchWithLogs(bn, actions, logBatch)
+			if len(logBatch) > 0 {
+				batch.BlockHash = logBatch[0].BlockHash
+			}
+			if fetchContexts {
+				if batch.Contexts, err = getActionContexts(ethcli, coreAddress, logBatch); err != nil {
+					return err
+				}
+			}
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+		if rangeEnd == toBlock {
+			// Avoid overflowing rangeStart
+			break
+		}
+	}
+	return nil
+}
+
 // ActionSender sends actions to a core contract.
 type ActionSender struct {
 	ethcli          EthCli
@@ -389,7 +699,7 @@ func (a *ActionSender) packMultiActionCall(actions []arch.Action) ([]byte, error
 		actionData  = make([][]byte, 0, len(actions))
 	)
 	if len(actions) == 0 {
-		return a.actionSchemas.ABI().Pack(params.MultiActionMethodName, actionIds, actionCount, actionData)
+		return multiActionABI.Pack(params.MultiActionMethodName, actionIds, actionCount, actionData)
 	}
 
 	firstActionId, firstData, err := a.actionSchemas.EncodeAction(actions[0])
@@ -414,7 +724,29 @@ func (a *ActionSender) packMultiActionCall(actions []arch.Action) ([]byte, error
 		}
 	}
 
-	return a.actionSchemas.ABI().Pack(params.MultiActionMethodName, actionIds, actionCount, actionData)
+	return multiActionABI.Pack(params.MultiActionMethodName, actionIds, actionCount, actionData)
+}
+
+// estimateGas estimates the gas used by a transaction to the contract with the given data.
+func (a *ActionSender) estimateGas(data []byte) (uint64, error) {
+	// Use provisional gas price to estimate gas
+	msg := ethereum.CallMsg{
+		From:      a.from,
+		To:        &a.contractAddress,
+		Value:     common.Big0,
+		GasFeeCap: common.Big0,
+		GasTipCap: common.Big0,
+		Data:      data,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
+	defer cancel()
+	estimatedGas, err := a.gasEstimator.EstimateGas(ctx, msg)
+	if err != nil {
+		// Decode the custom error the action reverted with, if any
+		err = a.actionSchemas.DecodeRevertError(err)
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	return estimatedGas, nil
 }
 
 // sendData sends a transaction to the contract with the given data.
@@ -433,26 +765,11 @@ func (a *ActionSender) sendData(data []byte) (*types.Transaction, error) {
 		gasPriceChan <- [2]*big.Int{gasFeeCap, gasTipCap}
 	}()
 
-	// Use provisional gas price to estimate gas
-	gasEstGasFeeCap := common.Big0
-	gasEstTipCap := common.Big0
-
-	msg := ethereum.CallMsg{
-		From:      a.from,
-		To:        &a.contractAddress,
-		Value:     common.Big0,
-		GasFeeCap: gasEstGasFeeCap,
-		GasTipCap: gasEstTipCap,
-		Data:      data,
-	}
-
 	// Estimate gas concurrently
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
-		defer cancel()
-		estimatedGas, err := a.gasEstimator.EstimateGas(ctx, msg)
+		estimatedGas, err := a.estimateGas(data)
 		if err != nil {
-			errChan <- fmt.Errorf("failed to estimate gas: %w", err)
+			errChan <- err
 			return
 		}
 		estGasCostChan <- estimatedGas
@@ -481,9 +798,9 @@ func (a *ActionSender) sendData(data []byte) (*types.Transaction, error) {
 		GasFeeCap: gasFeeCap,
 		GasTipCap: gasTipCap,
 		Gas:       gasLimit,
-		To:        msg.To,
-		Value:     msg.Value,
-		Data:      msg.Data,
+		To:        &a.contractAddress,
+		Value:     common.Big0,
+		Data:      data,
 	}
 	tx, err := a.signAndSend(txData)
 
@@ -550,15 +867,30 @@ func (a *ActionSender) SendAction(action arch.Action) (*types.Transaction, error
 func (a *ActionSender) SendActions(actionBatch []arch.Action) (*types.Transaction, error) {
 	if len(actionBatch) == 0 {
 		return nil, nil
-	} else if len(actionBatch) == 1 {
-		return a.SendAction(actionBatch[0])
-	} else {
-		data, err := a.packMultiActionCall(actionBatch)
-		if err != nil {
-			return nil, err
-		}
-		return a.sendData(data)
 	}
+	data, err := a.PackActions(actionBatch)
+	if err != nil {
+		return nil, err
+	}
+	return a.sendData(data)
+}
+
+// PackActions returns the calldata of a transaction sending the given actions, i.e., the action
+// calldata if there is a single action and a call to the multi-action method otherwise.
+func (a *ActionSender) PackActions(actionBatch []arch.Action) ([]byte, error) {
+	if len(actionBatch) == 1 {
+		return a.actionSchemas.ActionToCalldata(actionBatch[0])
+	}
+	return a.packMultiActionCall(actionBatch)
+}
+
+// EstimateGas estimates the gas used by a transaction sending the given actions.
+func (a *ActionSender) EstimateGas(actionBatch []arch.Action) (uint64, error) {
+	data, err := a.PackActions(actionBatch)
+	if err != nil {
+		return 0, err
+	}
+	return a.estimateGas(data)
 }
 
 // StartSendingActions starts sending actions from the given channel.
@@ -624,11 +956,19 @@ func (a *ActionSender) StartSendingActions(
 	return errChan, cancel
 }
 
+// contractCallerAtHash is implemented by clients that can call a contract at a block given by hash,
+// e.g., ethclient.Client.
+type contractCallerAtHash interface {
+	CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error)
+}
+
 // TableGetter reads a table from the core contract.
 type TableGetter struct {
 	ethcli          EthCli
 	tableSchemas    arch.TableSchemas
 	contractAddress common.Address
+	blockNumber     *big.Int     // Nil for the latest block
+	blockHash       *common.Hash // Takes precedence over blockNumber if not nil
 }
 
 // NewTableReader creates a new TableGetter.
@@ -644,47 +984,163 @@ func NewTableReader(
 	}
 }
 
-// ReadTable reads a table from the contract.
-func (t *TableGetter) Read(tableName string, keys ...interface{}) (interface{}, error) {
+// TableRead is a read of the row of a table with the given keys.
+type TableRead struct {
+	Table string
+	Keys  []interface{}
+}
+
+// packRead packs the calldata of a table read.
+func (t *TableGetter) packRead(tableName string, keys ...interface{}) (arch.TableSchema, []byte, error) {
 	// Get table ID from table name
 	tableId, ok := t.tableSchemas.TableIdFromName(tableName)
 	if !ok {
-		return nil, errors.New("table name does not match any table")
+		return arch.TableSchema{}, nil, errors.New("table name does not match any table")
 	}
 
 	// Get table schema from table ID
 	schema := t.tableSchemas.GetTableSchema(tableId)
 	data, err := t.tableSchemas.ABI().Pack(schema.Method.Name, keys...)
+	if err != nil {
+		return arch.TableSchema{}, nil, err
+	}
+	return schema, data, nil
+}
+
+// unpackRow unpacks the result of a table read into the canonical row type.
+func unpackRow(schema arch.TableSchema, result []byte) (interface{}, error) {
+	row := reflect.New(schema.Type).Interface()
+	if codec, ok := row.(arch.ABICodec); ok {
+		// Decode generated row types without reflection
+		if err := codec.DecodeABI(result); err != nil {
+			return nil, err
+		}
+		return row, nil
+	}
+
+	// Unpack result
+	_ret, err := schema.Method.Outputs.Unpack(result)
 	if err != nil {
 		return nil, err
 	}
+	ret := _ret[0]
 
-	// Call contract
+	// Convert result to canonical type
+	if err := arch.ConvertStruct(row, ret); err != nil {
+		return nil, err
+	}
+
+	return row, nil
+}
+
+// AtBlock returns a copy of the table getter that reads the tables as of the given block number.
+func (t *TableGetter) AtBlock(blockNumber uint64) *TableGetter {
+	getter := *t
+	getter.blockNumber = new(big.Int).SetUint64(blockNumber)
+	getter.blockHash = nil
+	return &getter
+}
+
+// AtBlockHash returns a copy of the table getter that reads the tables as of the block with the given hash.
+// If the client cannot call contracts at a block hash, the block is resolved to its number.
+func (t *TableGetter) AtBlockHash(blockHash common.Hash) *TableGetter {
+	getter := *t
+	getter.blockNumber = nil
+	getter.blockHash = &blockHash
+	return &getter
+}
+
+// call calls the core contract as of the pinned block, if any.
+func (t *TableGetter) call(data []byte) ([]byte, error) {
 	msg := ethereum.CallMsg{
 		To:   &t.contractAddress,
 		Data: data,
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
 	defer cancel()
-	result, err := t.ethcli.CallContract(ctx, msg, nil)
+	if t.blockHash == nil {
+		return t.ethcli.CallContract(ctx, msg, t.blockNumber)
+	}
+	if caller, ok := t.ethcli.(contractCallerAtHash); ok {
+		return caller.CallContractAtHash(ctx, msg, *t.blockHash)
+	}
+	header, err := t.ethcli.HeaderByHash(ctx, *t.blockHash)
 	if err != nil {
 		return nil, err
 	}
+	return t.ethcli.CallContract(ctx, msg, header.Number)
+}
 
-	// Unpack result
-	_ret, err := schema.Method.Outputs.Unpack(result)
+// ReadTable reads a table from the contract.
+func (t *TableGetter) Read(tableName string, keys ...interface{}) (interface{}, error) {
+	schema, data, err := t.packRead(tableName, keys...)
 	if err != nil {
 		return nil, err
 	}
-	ret := _ret[0]
 
-	// Convert result to canonical type
-	row := reflect.New(schema.Type).Interface()
-	if err := arch.ConvertStruct(row, ret); err != nil {
+	// Call contract
+	result, err := t.call(data)
+	if err != nil {
 		return nil, err
 	}
 
-	return row, nil
+	return unpackRow(schema, result)
+}
+
+// ReadMany reads the rows of multiple tables and returns them in the same order as the reads.
+// Reads are batched into calls to the multi-read method of the core contract of up to
+// MaxTableReadsPerCall reads each. If the tables ABI does not declare the multi-read method, rows are
+// read with one call each.
+func (t *TableGetter) ReadMany(reads []TableRead) ([]interface{}, error) {
+	schemas := make([]arch.TableSchema, len(reads))
+	calls := make([][]byte, len(reads))
+	for ii, read := range reads {
+		var err error
+		if schemas[ii], calls[ii], err = t.packRead(read.Table, read.Keys...); err != nil {
+			return nil, fmt.Errorf("read %d: %w", ii, err)
+		}
+	}
+
+	rows := make([]interface{}, len(reads))
+	method, ok := t.tableSchemas.ABI().Methods[params.MultiTableReadMethodName]
+	if !ok {
+		for ii, call := range calls {
+			result, err := t.call(call)
+			if err != nil {
+				return nil, fmt.Errorf("read %d: %w", ii, err)
+			}
+			if rows[ii], err = unpackRow(schemas[ii], result); err != nil {
+				return nil, fmt.Errorf("read %d: %w", ii, err)
+			}
+		}
+		return rows, nil
+	}
+
+	for start := 0; start < len(calls); start += MaxTableReadsPerCall {
+		end := utils.Min(start+MaxTableReadsPerCall, len(calls))
+		data, err := t.tableSchemas.ABI().Pack(method.Name, calls[start:end])
+		if err != nil {
+			return nil, err
+		}
+		result, err := t.call(data)
+		if err != nil {
+			return nil, err
+		}
+		_ret, err := method.Outputs.Unpack(result)
+		if err != nil {
+			return nil, err
+		}
+		results, ok := _ret[0].([][]byte)
+		if !ok || len(results) != end-start {
+			return nil, errors.New("invalid multi-read result")
+		}
+		for ii, result := range results {
+			if rows[start+ii], err = unpackRow(schemas[start+ii], result); err != nil {
+				return nil, fmt.Errorf("read %d: %w", start+ii, err)
+			}
+		}
+	}
+	return rows, nil
 }
 
 type TxMonitor struct {
@@ -1040,6 +1496,12 @@ type IO struct {
 	schemas             arch.ArchSchemas
 	blockTime           time.Duration
 	startingBlockNumber uint64
+	localCtx            arch.ExecutionContext // Context of the actions sent through the IO
+	owner               common.Address        // Owner of the game, zero if it could not be read
+
+	subscribe     func(startingBlockNumber uint64) // Subscribes to the action batches of the core
+	subscribeOnce sync.Once
+	fetchContexts bool
 
 	_txUpdateHook func(*ActionTxUpdate)
 }
@@ -1066,6 +1528,7 @@ func NewIO(
 
 	io := &IO{
 		cancelFns:           make([]func(), 0),
+		localCtx:            arch.ExecutionContext{Caller: gameAddress, Origin: auth.From},
 		actionBatchOutChan:  actionBatchChanDampened,
 		actionInChan:        actionChan,
 		schemas:             schemas,
@@ -1077,14 +1540,21 @@ func NewIO(
 		auth.Nonce = new(big.Int).SetUint64(0)
 	}
 
+	// Clients reject actions restricted to the owner if the owner cannot be read
+	if owner, err := getGameOwner(ethcli, gameAddress); err == nil {
+		io.owner = owner
+	}
+
 	txm := NewTxMonitor(ethcli, retryTxData, retryTxHashes)
 	io.sender = NewActionSender(ethcli, schemas.Actions, nil, gameAddress, auth.From, auth.Nonce.Uint64(), auth.Signer)
 	errChan, cancel := io.sender.StartSendingActions(actionChan, txUpdateChanW, retryTxData, retryTxHashes)
 	io.errChan = errChan
 	io.registerCancelFn(cancel)
 
-	sub := SubscribeActionBatches(ethcli, schemas.Actions, coreAddress, startingBlockNumber, actionBatchWithLogsChan)
-	io.registerCancelFn(sub.unsubscribe)
+	io.subscribe = func(startingBlockNumber uint64) {
+		sub := SubscribeActionBatches(ethcli, schemas.Actions, coreAddress, startingBlockNumber, client.MaxRollbackDepth, io.fetchContexts, actionBatchWithLogsChan)
+		io.registerCancelFn(sub.unsubscribe)
+	}
 	DampenLatency(actionBatchWithLogsChan, actionBatchWithLogsChanDampened, blockTime, dampenDelay)
 
 	go func() {
@@ -1127,11 +1597,21 @@ func (io *IO) SetTxUpdateHook(fn func(*ActionTxUpdate)) {
 	io._txUpdateHook = fn
 }
 
+// SetFetchExecutionContexts sets whether the action batches received carry the execution context of
+// every action. It must be enabled if the core reads the execution context, and called before the
+// subscription is started by ActionBatchOutChan or NewClient.
+func (io *IO) SetFetchExecutionContexts(fetch bool) {
+	io.fetchContexts = fetch
+}
+
 func (io *IO) RegisterCancelFn(fn func()) {
 	io.registerCancelFn(fn)
 }
 
+// ActionBatchOutChan returns the channel action batches are sent to, starting the subscription from the
+// starting block number if no client was created with NewClient.
 func (io *IO) ActionBatchOutChan() <-chan arch.ActionBatch {
+	io.startSubscription(io.startingBlockNumber)
 	return io.actionBatchOutChan
 }
 
@@ -1151,12 +1631,34 @@ func (io *IO) ErrChan() <-chan error {
 	return io.errChan
 }
 
+// startSubscription subscribes to the action batches of the core from the given block, once.
+func (io *IO) startSubscription(startingBlockNumber uint64) {
+	io.subscribeOnce.Do(func() {
+		io.subscribe(startingBlockNumber)
+	})
+}
+
 // Create a new client.Client using IO for sending and receiving transactions.
+// If kv records a checkpoint, the client resumes from it and action batches are fetched from the
+// checkpoint block, which is checked against the canonical chain; blocks before it are not fetched again.
+// Action batches are only fetched for the first client created.
+// Actions simulated by the client run as sent by the IO account through the game contract, and actions
+// the IO account is not authorized to execute are rejected.
 func (io *IO) NewClient(
 	kv lib.KeyValueStore,
 	core arch.Core,
 ) *client.Client {
-	return client.New(io.schemas, core, kv, io.actionBatchOutChan, io.actionInChan, io.blockTime, io.startingBlockNumber)
+	if resumeBN := client.ResumeBlockNumber(kv, io.startingBlockNumber); resumeBN != io.startingBlockNumber {
+		// Fetch the checkpoint block again so the client can verify it was not orphaned
+		io.startSubscription(resumeBN - 1)
+	} else {
+		io.startSubscription(io.startingBlockNumber)
+	}
+	c := client.New(io.schemas, core, kv, io.actionBatchOutChan, io.actionInChan, io.blockTime, io.startingBlockNumber)
+	c.SetExecutionContext(io.localCtx)
+	c.SetOwner(io.owner)
+	c.SetAccount(io.sender.from)
+	return c
 }
 
 func NewEthClient(rpcUrl string) (ethcli *ethclient.Client, chainId *big.Int, err error) {
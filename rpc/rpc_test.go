@@ -3,20 +3,24 @@ package rpc
 import (
 	"context"
 	"errors"
+	"math"
 	"math/big"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/concrete-eth/archetype/arch"
+	"github.com/concrete-eth/archetype/client"
 	"github.com/concrete-eth/archetype/precompile"
 	"github.com/concrete-eth/archetype/simulated"
 	"github.com/concrete-eth/archetype/testutils"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/concrete"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	ethparams "github.com/ethereum/go-ethereum/params"
 )
 
 var (
@@ -92,6 +96,42 @@ func TestSendAction(t *testing.T) {
 	}
 }
 
+func TestSendActionEvents(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		ethcli  = newTestSimulatedBackend(t)
+	)
+
+	from, signerFn := newTestSignerFn(t)
+	sender := NewActionSender(ethcli, schemas.Actions, nil, pcAddress, from, 0, signerFn)
+	tx, err := sender.SendAction(&testutils.ActionData_Add{Summand: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethcli.Commit()
+
+	receipt, err := ethcli.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipt.Logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(receipt.Logs))
+	}
+
+	// The custom events are logged after the action
+	if schemas.Events.IsEventLog(*receipt.Logs[0]) {
+		t.Fatal("expected action log first")
+	}
+	event, err := schemas.Events.LogToEvent(*receipt.Logs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expEvent := &testutils.Event_CounterUpdated{Previous: 0, Value: 2}
+	if !reflect.DeepEqual(event, expEvent) {
+		t.Fatalf("expected %v, got %v", expEvent, event)
+	}
+}
+
 func TestSendBadAction(t *testing.T) {
 	var (
 		schemas = testutils.NewTestArchSchemas(t)
@@ -113,9 +153,184 @@ func TestSendBadAction(t *testing.T) {
 	}
 }
 
+func TestPackActionsAndEstimateGas(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		ethcli  = newTestSimulatedBackend(t)
+	)
+
+	from, signerFn := newTestSignerFn(t)
+	sender := NewActionSender(ethcli, schemas.Actions, nil, pcAddress, from, 0, signerFn)
+
+	// A single action is packed as the action calldata
+	action := &testutils.ActionData_Add{Summand: 1}
+	data, err := sender.PackActions([]arch.Action{action})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expData, err := schemas.Actions.ActionToCalldata(action)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(data, expData) {
+		t.Fatalf("expected %x, got %x", expData, data)
+	}
+
+	gas, err := sender.EstimateGas([]arch.Action{action})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gas < ethparams.TxGas {
+		t.Fatalf("expected at least %d gas, got %d", ethparams.TxGas, gas)
+	}
+
+	// Multiple actions are packed as a call to the entrypoint multi-action method
+	data, err = sender.PackActions([]arch.Action{action, action, &testutils.ActionData_Add{Summand: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, err := multiActionABI.MethodById(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actionCount := args[1].([]uint8); !reflect.DeepEqual(actionCount, []uint8{3}) {
+		t.Fatalf("expected action count %v, got %v", []uint8{3}, actionCount)
+	}
+	if actionData := args[2].([][]byte); len(actionData) != 3 {
+		t.Fatalf("expected %d actions, got %d", 3, len(actionData))
+	}
+}
+
+func TestSendActionCustomError(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		ethcli  = newTestSimulatedBackend(t)
+	)
+
+	from, signerFn := newTestSignerFn(t)
+	sender := NewActionSender(ethcli, schemas.Actions, nil, pcAddress, from, 0, signerFn)
+	if _, err := sender.SendAction(&testutils.ActionData_Add{Summand: math.MaxInt16}); err != nil {
+		t.Fatal(err)
+	}
+	ethcli.Commit()
+
+	// Overflowing the counter reverts with a custom error decoded by the sender
+	_, err := sender.SendAction(&testutils.ActionData_Add{Summand: 1})
+	var overflowErr *testutils.ActionError_CounterOverflow
+	if !errors.As(err, &overflowErr) {
+		t.Fatalf("expected %T, got %v", overflowErr, err)
+	}
+	expErr := &testutils.ActionError_CounterOverflow{Counter: math.MaxInt16, Summand: 1}
+	if !reflect.DeepEqual(overflowErr, expErr) {
+		t.Fatalf("expected %v, got %v", expErr, overflowErr)
+	}
+
+	// The simulated backend decodes the custom error of a failed call
+	ethcli.AddRevertDecoder(&schemas.Actions)
+	calldata, err := schemas.Actions.ActionToCalldata(&testutils.ActionData_Add{Summand: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ethcli.CallContract(context.Background(), ethereum.CallMsg{From: from, To: &pcAddress, Data: calldata}, nil)
+	if !errors.As(err, &overflowErr) {
+		t.Fatalf("expected %T, got %v", overflowErr, err)
+	}
+}
+
+func TestTableGetterReadMany(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		ethcli  = newTestSimulatedBackend(t)
+	)
+
+	from, signerFn := newTestSignerFn(t)
+	sender := NewActionSender(ethcli, schemas.Actions, nil, pcAddress, from, 0, signerFn)
+	if _, err := sender.SendAction(&testutils.ActionData_Add{Summand: 3}); err != nil {
+		t.Fatal(err)
+	}
+	ethcli.Commit()
+
+	// Split the reads into two calls
+	defer func(max int) { MaxTableReadsPerCall = max }(MaxTableReadsPerCall)
+	MaxTableReadsPerCall = 2
+
+	tableGetter := NewTableReader(ethcli, schemas.Tables, pcAddress)
+	reads := []TableRead{{Table: "Counter"}, {Table: "Counter"}, {Table: "Counter"}}
+	rows, err := tableGetter.ReadMany(reads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(reads) {
+		t.Fatalf("expected %v rows, got %v", len(reads), len(rows))
+	}
+	for ii, row := range rows {
+		counter, ok := row.(*testutils.RowData_Counter)
+		if !ok {
+			t.Fatalf("row %d: expected counter row, got %T", ii, row)
+		}
+		if counter.GetValue() != 3 {
+			t.Errorf("row %d: expected %v, got %v", ii, 3, counter.GetValue())
+		}
+	}
+
+	if _, err := tableGetter.ReadMany([]TableRead{{Table: "Counter"}, {Table: "Missing"}}); err == nil {
+		t.Error("expected error for unknown table")
+	}
+}
+
+func TestTableGetterAtBlock(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		ethcli  = newTestSimulatedBackend(t)
+	)
+
+	from, signerFn := newTestSignerFn(t)
+	sender := NewActionSender(ethcli, schemas.Actions, nil, pcAddress, from, 0, signerFn)
+	blockHashes := make([]common.Hash, 0)
+	for _, summand := range []int16{3, 2} {
+		if _, err := sender.SendAction(&testutils.ActionData_Add{Summand: summand}); err != nil {
+			t.Fatal(err)
+		}
+		blockHashes = append(blockHashes, ethcli.Commit())
+	}
+	firstBlock, err := ethcli.HeaderByHash(context.Background(), blockHashes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tableGetter := NewTableReader(ethcli, schemas.Tables, pcAddress)
+	for _, data := range []struct {
+		getter   *TableGetter
+		expValue int16
+	}{
+		{tableGetter, 5},
+		{tableGetter.AtBlock(firstBlock.Number.Uint64()), 3},
+		{tableGetter.AtBlock(firstBlock.Number.Uint64() + 1), 5},
+		{tableGetter.AtBlockHash(blockHashes[0]), 3},
+		{tableGetter.AtBlockHash(blockHashes[1]), 5},
+	} {
+		row, err := data.getter.Read("Counter")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value := row.(*testutils.RowData_Counter).GetValue(); value != data.expValue {
+			t.Errorf("expected %v, got %v", data.expValue, value)
+		}
+	}
+}
+
 func waitForActionBatch(t *testing.T, actionBatchesChan <-chan arch.ActionBatchWithLogs) arch.ActionBatch {
+	return waitForActionBatchWithTimeout(t, actionBatchesChan, 10*time.Millisecond)
+}
+
+func waitForActionBatchWithTimeout(t *testing.T, actionBatchesChan <-chan arch.ActionBatchWithLogs, timeout time.Duration) arch.ActionBatch {
+	t.Helper()
 	select {
-	case <-time.After(10 * time.Millisecond):
+	case <-time.After(timeout):
 		t.Fatal("timeout")
 		return arch.ActionBatch{}
 	case actionBatchIn := <-actionBatchesChan:
@@ -131,7 +346,7 @@ func TestSubscribeToActionBatches(t *testing.T) {
 
 	// Subscribe to action batches
 	actionBatchesChan := make(chan arch.ActionBatchWithLogs, 1)
-	sub := SubscribeActionBatches(ethcli, schemas.Actions, pcAddress, 0, actionBatchesChan)
+	sub := SubscribeActionBatches(ethcli, schemas.Actions, pcAddress, 0, client.MaxRollbackDepth, true, actionBatchesChan)
 	defer sub.Unsubscribe()
 
 	// Commit and empty block
@@ -153,12 +368,13 @@ func TestSubscribeToActionBatches(t *testing.T) {
 
 	// Send an action
 	action := &testutils.ActionData_Add{}
-	if _, err := sender.SendAction(action); err != nil {
+	tx, err := sender.SendAction(action)
+	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Wait for the action batch
-	ethcli.Commit()
+	blockHash := ethcli.Commit()
 	batch = waitForActionBatch(t, actionBatchesChan)
 
 	// Check the action batch
@@ -168,6 +384,16 @@ func TestSubscribeToActionBatches(t *testing.T) {
 	if !reflect.DeepEqual(batch.Actions[0], action) {
 		t.Fatalf("expected action, got %v", batch.Actions[0])
 	}
+
+	// The core is not behind a proxy, so its caller is unknown
+	header, err := ethcli.HeaderByHash(context.Background(), blockHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expCtx := arch.ExecutionContext{Origin: from, BlockTimestamp: header.Time, TxHash: tx.Hash()}
+	if ctx := batch.Context(0); ctx != expCtx {
+		t.Fatalf("expected context %+v, got %+v", expCtx, ctx)
+	}
 }
 
 var errBadEthcli = errors.New("bad ethcli")
@@ -188,7 +414,7 @@ func TestSubscribeToActionBatchesError(t *testing.T) {
 
 	// Subscribe to action batches
 	actionBatchesChan := make(chan arch.ActionBatchWithLogs, 1)
-	sub := SubscribeActionBatches(ethcli, schemas.Actions, pcAddress, 0, actionBatchesChan)
+	sub := SubscribeActionBatches(ethcli, schemas.Actions, pcAddress, 0, client.MaxRollbackDepth, false, actionBatchesChan)
 	defer sub.Unsubscribe()
 
 	timeout := time.After(10 * time.Millisecond)
@@ -201,3 +427,97 @@ func TestSubscribeToActionBatchesError(t *testing.T) {
 		}
 	}
 }
+
+func TestSubscribeToActionBatchesReorg(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		ethcli  = newTestSimulatedBackend(t)
+		timeout = 100 * time.Millisecond // Reorgs take longer to process
+	)
+
+	// Subscribe to action batches
+	actionBatchesChan := make(chan arch.ActionBatchWithLogs, 4)
+	sub := SubscribeActionBatches(ethcli, schemas.Actions, pcAddress, 0, client.MaxRollbackDepth, false, actionBatchesChan)
+	defer sub.Unsubscribe()
+
+	// Block 1
+	forkParentHash := ethcli.Commit()
+	waitForActionBatchWithTimeout(t, actionBatchesChan, timeout) // Block 0
+	waitForActionBatchWithTimeout(t, actionBatchesChan, timeout) // Block 1
+
+	// Block 2, including an action
+	from, signerFn := newTestSignerFn(t)
+	sender := NewActionSender(ethcli, schemas.Actions, nil, pcAddress, from, 0, signerFn)
+	if _, err := sender.SendAction(&testutils.ActionData_Add{}); err != nil {
+		t.Fatal(err)
+	}
+	ethcli.Commit()
+	if batch := waitForActionBatchWithTimeout(t, actionBatchesChan, timeout); batch.Len() != 1 {
+		t.Fatalf("expected 1 action, got %d", batch.Len())
+	}
+
+	// Replace block 2 with an empty block and extend the side chain to make it canonical
+	if err := ethcli.Fork(context.Background(), forkParentHash); err != nil {
+		t.Fatal(err)
+	}
+	ethcli.Commit()
+	ethcli.Commit()
+
+	// The canonical block 2 must be sent again, signaling the orphaned batch
+	batch := waitForActionBatchWithTimeout(t, actionBatchesChan, timeout)
+	if batch.BlockNumber != 2 {
+		t.Fatalf("expected block number 2, got %d", batch.BlockNumber)
+	}
+	if batch.ReorgDepth != 1 {
+		t.Fatalf("expected reorg depth 1, got %d", batch.ReorgDepth)
+	}
+	if batch.Len() != 0 {
+		t.Fatalf("expected 0 actions, got %d", batch.Len())
+	}
+	if batch = waitForActionBatchWithTimeout(t, actionBatchesChan, timeout); batch.BlockNumber != 3 || batch.ReorgDepth != 0 {
+		t.Fatalf("expected block 3 without reorg, got block %d with reorg depth %d", batch.BlockNumber, batch.ReorgDepth)
+	}
+}
+
+func TestSubscribeToActionBatchesReorgEmptyBlocks(t *testing.T) {
+	var (
+		schemas = testutils.NewTestArchSchemas(t)
+		ethcli  = newTestSimulatedBackend(t)
+		timeout = 100 * time.Millisecond // Reorgs take longer to process
+	)
+
+	// Subscribe to action batches
+	actionBatchesChan := make(chan arch.ActionBatchWithLogs, 4)
+	sub := SubscribeActionBatches(ethcli, schemas.Actions, pcAddress, 0, client.MaxRollbackDepth, false, actionBatchesChan)
+	defer sub.Unsubscribe()
+
+	// Blocks 1 and 2, both empty
+	forkParentHash := ethcli.Commit()
+	ethcli.Commit()
+	for bn := uint64(0); bn <= 2; bn++ {
+		if batch := waitForActionBatchWithTimeout(t, actionBatchesChan, timeout); batch.BlockNumber != bn {
+			t.Fatalf("expected block number %d, got %d", bn, batch.BlockNumber)
+		}
+	}
+
+	// Replace block 2 with a block including an action and extend the side chain to make it canonical
+	if err := ethcli.Fork(context.Background(), forkParentHash); err != nil {
+		t.Fatal(err)
+	}
+	from, signerFn := newTestSignerFn(t)
+	sender := NewActionSender(ethcli, schemas.Actions, nil, pcAddress, from, 0, signerFn)
+	if _, err := sender.SendAction(&testutils.ActionData_Add{}); err != nil {
+		t.Fatal(err)
+	}
+	ethcli.Commit()
+	ethcli.Commit()
+
+	// The orphaned block had no logs, but the reorg must still be detected
+	batch := waitForActionBatchWithTimeout(t, actionBatchesChan, timeout)
+	if batch.BlockNumber != 2 || batch.ReorgDepth != 1 {
+		t.Fatalf("expected block 2 with reorg depth 1, got block %d with reorg depth %d", batch.BlockNumber, batch.ReorgDepth)
+	}
+	if batch.Len() != 1 {
+		t.Fatalf("expected 1 action, got %d", batch.Len())
+	}
+}
@@ -0,0 +1,130 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/concrete-eth/archetype/arch"
+	"github.com/concrete-eth/archetype/kvstore"
+	snapshot_types "github.com/concrete-eth/archetype/snapshot/types"
+	"github.com/concrete-eth/archetype/snapshot/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	SnapshotNamespace = "arch" // RPC namespace of the snapshot reader API
+)
+
+const snapshotSlotDataSize = 2 * common.HashLength // Size of a slot key hash and value pair in a snapshot blob
+
+var (
+	ErrSnapshotNotDone = errors.New("snapshot not done")
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+)
+
+// RpcCaller is the subset of the go-ethereum RPC client used to call the snapshot reader API.
+type RpcCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+func snapshotMethodName(name string) string {
+	return SnapshotNamespace + "_" + name
+}
+
+func getLastSnapshotMetadata(rpcCli RpcCaller, address common.Address) (snapshot_types.SnapshotMetadataWithStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
+	defer cancel()
+	var metadata snapshot_types.SnapshotMetadataWithStatus
+	err := rpcCli.CallContext(ctx, &metadata, snapshotMethodName("last"), address)
+	return metadata, err
+}
+
+func getSnapshot(rpcCli RpcCaller, address common.Address, blockHash common.Hash) (snapshot_types.SnapshotResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), StandardTimeout)
+	defer cancel()
+	var response snapshot_types.SnapshotResponse
+	err := rpcCli.CallContext(ctx, &response, snapshotMethodName("get"), address, blockHash)
+	return response, err
+}
+
+// LoadSnapshotBlob loads a compressed snapshot storage blob into a new key-value store.
+func LoadSnapshotBlob(blobZip []byte) (*kvstore.HashedMemoryKeyValueStore, error) {
+	blob, err := utils.Decompress(blobZip)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob)%snapshotSlotDataSize != 0 {
+		return nil, ErrInvalidSnapshot
+	}
+	kv := kvstore.NewHashedMemoryKeyValueStore()
+	it := utils.BlobToStorageIt(blob)
+	defer it.Release()
+	for it.Next() {
+		value, err := utils.DecodeSnapshotSlot(it.Slot())
+		if err != nil {
+			return nil, err
+		}
+		kv.SetByKeyHash(it.Hash(), value)
+	}
+	return kv, it.Error()
+}
+
+// GetLastSnapshot fetches the latest completed snapshot of the storage of the contract at address
+// served through the snapshot reader API.
+func GetLastSnapshot(rpcCli RpcCaller, address common.Address) (snapshot_types.SnapshotResponse, error) {
+	metadata, err := getLastSnapshotMetadata(rpcCli, address)
+	if err != nil {
+		return snapshot_types.SnapshotResponse{}, err
+	}
+	response, err := getSnapshot(rpcCli, address, metadata.BlockHash)
+	if err != nil {
+		return snapshot_types.SnapshotResponse{}, err
+	}
+	if response.Status != snapshot_types.SnapshotStatus_Done {
+		return snapshot_types.SnapshotResponse{}, fmt.Errorf("%w: status %s %s", ErrSnapshotNotDone, response.Status, response.Error)
+	}
+	if response.BlockNumber == nil || !response.BlockNumber.IsUint64() {
+		return snapshot_types.SnapshotResponse{}, ErrInvalidSnapshot
+	}
+	return response, nil
+}
+
+// BootstrapFromSnapshot loads the latest snapshot of the storage of the core contract at coreAddress
+// served through the snapshot reader API into a new key-value store.
+// Returns the key-value store and the number of the block the snapshot was taken at. Clients and
+// action batch subscriptions using the store must start from the following block.
+func BootstrapFromSnapshot(rpcCli RpcCaller, coreAddress common.Address) (*kvstore.HashedMemoryKeyValueStore, uint64, error) {
+	response, err := GetLastSnapshot(rpcCli, coreAddress)
+	if err != nil {
+		return nil, 0, err
+	}
+	kv, err := LoadSnapshotBlob(response.Storage)
+	if err != nil {
+		return nil, 0, err
+	}
+	return kv, response.BlockNumber.Uint64(), nil
+}
+
+// NewIOFromSnapshot bootstraps a key-value store from the latest snapshot of the core contract storage
+// and creates a new IO that subscribes to action batches from the block following the snapshot.
+// Clients created with IO.NewClient and the returned store resume from the snapshot instead of
+// replaying every action batch.
+func NewIOFromSnapshot(
+	ethcli EthCli,
+	rpcCli RpcCaller,
+	blockTime time.Duration,
+	schemas arch.ArchSchemas,
+	auth *bind.TransactOpts,
+	gameAddress, coreAddress common.Address,
+	dampenDelay time.Duration,
+) (*IO, *kvstore.HashedMemoryKeyValueStore, error) {
+	kv, blockNumber, err := BootstrapFromSnapshot(rpcCli, coreAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	io := NewIO(ethcli, blockTime, schemas, auth, gameAddress, coreAddress, blockNumber+1, dampenDelay)
+	return io, kv, nil
+}
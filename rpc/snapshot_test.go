@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	snapshot_types "github.com/concrete-eth/archetype/snapshot/types"
+	"github.com/concrete-eth/archetype/snapshot/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+type testSnapshotReader struct {
+	response snapshot_types.SnapshotResponse
+}
+
+func (r *testSnapshotReader) Get(address common.Address, blockHash common.Hash) (snapshot_types.SnapshotResponse, error) {
+	if address != r.response.Address || blockHash != r.response.BlockHash {
+		return snapshot_types.SnapshotResponse{}, errors.New("snapshot not found")
+	}
+	return r.response, nil
+}
+
+func (r *testSnapshotReader) Last(address common.Address) (snapshot_types.SnapshotMetadataWithStatus, error) {
+	if address != r.response.Address {
+		return snapshot_types.SnapshotMetadataWithStatus{}, errors.New("snapshot not found")
+	}
+	return r.response.SnapshotMetadataWithStatus, nil
+}
+
+func newTestSnapshotRpcClient(t *testing.T, response snapshot_types.SnapshotResponse) *gethrpc.Client {
+	server := gethrpc.NewServer()
+	if err := server.RegisterName(SnapshotNamespace, &testSnapshotReader{response: response}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	rpcCli := gethrpc.DialInProc(server)
+	t.Cleanup(rpcCli.Close)
+	return rpcCli
+}
+
+func TestBootstrapFromSnapshot(t *testing.T) {
+	var (
+		key   = common.BytesToHash([]byte("foo"))
+		value = common.BytesToHash([]byte("bar"))
+	)
+
+	blob := append(crypto.Keccak256Hash(key.Bytes()).Bytes(), value.Bytes()...)
+	blobZip, err := utils.Compress(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := snapshot_types.SnapshotResponse{
+		SnapshotMetadataWithStatus: snapshot_types.SnapshotMetadataWithStatus{
+			SnapshotMetadata: snapshot_types.SnapshotMetadata{
+				Address:     pcAddress,
+				BlockHash:   common.BytesToHash([]byte("block")),
+				BlockNumber: big.NewInt(10),
+			},
+			Status: snapshot_types.SnapshotStatus_Done,
+		},
+		Storage: blobZip,
+	}
+
+	kv, blockNumber, err := BootstrapFromSnapshot(newTestSnapshotRpcClient(t, response), pcAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blockNumber != 10 {
+		t.Errorf("expected block number %v, got %v", 10, blockNumber)
+	}
+	if kv.Get(key) != value {
+		t.Errorf("expected %v, got %v", value, kv.Get(key))
+	}
+
+	// Snapshots that are not done cannot be loaded
+	response.Status = snapshot_types.SnapshotStatus_Pending
+	if _, _, err := BootstrapFromSnapshot(newTestSnapshotRpcClient(t, response), pcAddress); !errors.Is(err, ErrSnapshotNotDone) {
+		t.Errorf("expected %v, got %v", ErrSnapshotNotDone, err)
+	}
+}
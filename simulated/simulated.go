@@ -47,7 +47,6 @@ import (
 )
 
 var (
-	errBlockNumberUnsupported = errors.New("simulatedBackend cannot access blocks other than the latest block")
 	// errBlockHashUnsupported    = errors.New("simulatedBackend cannot access blocks by hash other than the latest block")
 	errBlockDoesNotExist       = errors.New("block does not exist in blockchain")
 	errTransactionDoesNotExist = errors.New("transaction does not exist")
@@ -69,6 +68,14 @@ type SimulatedBackend struct {
 
 	events       *filters.EventSystem  // for filtering log events live
 	filterSystem *filters.FilterSystem // for filtering database logs
+
+	revertDecoders []RevertDecoder // Decoders of custom errors in revert data
+}
+
+// RevertDecoder decodes revert data into a typed error, e.g., arch.ActionSchemas decoding the
+// custom errors of a core.
+type RevertDecoder interface {
+	DecodeError(data []byte) (error, bool)
 }
 
 // NewSimulatedBackendWithDatabase creates a new binding backend based on the given database
@@ -148,6 +155,30 @@ func (b *SimulatedBackend) rollback(parent *types.Block) {
 	b.pendingState, _ = state.New(b.pendingBlock.Root(), b.blockchain.StateCache(), nil)
 }
 
+// Fork creates a side-chain that can be used to simulate reorgs.
+//
+// This function should be called with the ancestor block where the new side
+// chain should be started. Transactions (old and new) can then be applied on
+// top and Commit-ed.
+//
+// Note, the side-chain will only become canonical (and trigger the events) when
+// it becomes longer. Until then CallContract will still operate on the current
+// canonical chain.
+func (b *SimulatedBackend) Fork(ctx context.Context, parent common.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pendingBlock.Transactions()) != 0 {
+		return errors.New("pending block dirty")
+	}
+	block, err := b.blockByHash(ctx, parent)
+	if err != nil {
+		return err
+	}
+	b.rollback(block)
+	return nil
+}
+
 // stateByBlockNumber retrieves a state by a given blocknumber.
 func (b *SimulatedBackend) stateByBlockNumber(ctx context.Context, blockNumber *big.Int) (*state.StateDB, error) {
 	if blockNumber == nil || blockNumber.Cmp(b.blockchain.CurrentBlock().Number) == 0 {
@@ -397,13 +428,28 @@ func (b *SimulatedBackend) PendingCodeAt(ctx context.Context, contract common.Ad
 	return b.pendingState.GetCode(contract), nil
 }
 
+// AddRevertDecoder adds a decoder of the custom errors in the revert data of failed calls and
+// gas estimations. Errors decoded by it are wrapped by the returned revert errors.
+func (b *SimulatedBackend) AddRevertDecoder(decoder RevertDecoder) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.revertDecoders = append(b.revertDecoders, decoder)
+}
+
 // newRevertError creates a revertError instance with the provided revert data.
-func newRevertError(revert []byte) *revertError {
+func (b *SimulatedBackend) newRevertError(revert []byte) *revertError {
 	err := vm.ErrExecutionReverted
 
-	reason, errUnpack := abi.UnpackRevert(revert)
-	if errUnpack == nil {
+	if reason, errUnpack := abi.UnpackRevert(revert); errUnpack == nil {
 		err = fmt.Errorf("%w: %v", vm.ErrExecutionReverted, reason)
+	} else {
+		for _, decoder := range b.revertDecoders {
+			if typedErr, ok := decoder.DecodeError(revert); ok {
+				err = fmt.Errorf("%w: %w", vm.ErrExecutionReverted, typedErr)
+				break
+			}
+		}
 	}
 	return &revertError{
 		error:  err,
@@ -429,30 +475,56 @@ func (e *revertError) ErrorData() interface{} {
 	return e.reason
 }
 
+// Unwrap returns the underlying error, wrapping the decoded custom error if any.
+func (e *revertError) Unwrap() error {
+	return e.error
+}
+
 // CallContract executes a contract call.
 func (b *SimulatedBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if blockNumber != nil && blockNumber.Cmp(b.blockchain.CurrentBlock().Number) != 0 {
-		return nil, errBlockNumberUnsupported
+	if blockNumber == nil || blockNumber.Cmp(b.blockchain.CurrentBlock().Number) == 0 {
+		return b.callContractAtHead(ctx, call)
+	}
+	block, err := b.blockByNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, err
 	}
-	return b.callContractAtHead(ctx, call)
+	return b.callContractAt(ctx, call, block.Header())
+}
+
+// CallContractAtHash executes a contract call against the state of the block with the given hash.
+func (b *SimulatedBackend) CallContractAtHash(ctx context.Context, call ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	header, err := b.headerByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return b.callContractAt(ctx, call, header)
 }
 
 // callContractAtHead executes a contract call against the latest block state.
 func (b *SimulatedBackend) callContractAtHead(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
-	stateDB, err := b.blockchain.State()
+	return b.callContractAt(ctx, call, b.blockchain.CurrentBlock())
+}
+
+// callContractAt executes a contract call against the state of the given block.
+func (b *SimulatedBackend) callContractAt(ctx context.Context, call ethereum.CallMsg, header *types.Header) ([]byte, error) {
+	stateDB, err := b.blockchain.StateAt(header.Root)
 	if err != nil {
 		return nil, err
 	}
-	res, err := b.callContract(ctx, call, b.blockchain.CurrentBlock(), stateDB)
+	res, err := b.callContract(ctx, call, header, stateDB)
 	if err != nil {
 		return nil, err
 	}
 	// If the result contains a revert reason, try to unpack and return it.
 	if rev := res.Revert(); len(rev) > 0 {
-		return nil, newRevertError(rev)
+		return nil, b.newRevertError(rev)
 	}
 	return res.Return(), res.Err
 }
@@ -554,7 +626,11 @@ func (b *SimulatedBackend) EstimateGas(ctx context.Context, call ethereum.CallMs
 		gasCap = b.pendingBlock.GasLimit()
 	}
 
-	hi, _, err := gasestimator.Estimate(ctx, msg, opts, gasCap)
+	hi, ret, err := gasestimator.Estimate(ctx, msg, opts, gasCap)
+	if err != nil && len(ret) > 0 {
+		// If the call reverted, return the revert reason
+		return 0, b.newRevertError(ret)
+	}
 	return hi, err
 }
 
@@ -4,6 +4,7 @@ pragma solidity >=0.8.0;
 import {ERC1967Utils} from "openzeppelin/proxy/ERC1967/ERC1967Utils.sol";
 
 import {ERC1967PrecompileProxy} from "./utils/ERC1967PrecompileProxy.sol";
+import {ERC1967PrecompileUtils} from "./utils/ERC1967PrecompileUtils.sol";
 import {CallUtils} from "./utils/CallUtils.sol";
 
 contract ArchProxy is ERC1967PrecompileProxy {
@@ -15,6 +16,17 @@ contract ArchProxy is ERC1967PrecompileProxy {
         ERC1967Utils.changeAdmin(_admin);
     }
 
+    function upgradeToAndCall(
+        address newLogic,
+        bytes memory data
+    ) external payable {
+        require(
+            msg.sender == ERC1967Utils.getAdmin(),
+            "ArchProxy: caller is not the admin"
+        );
+        ERC1967PrecompileUtils.upgradeToAndCall(newLogic, data);
+    }
+
     fallback() external payable virtual override {
         if (
             msg.sender == address(this) || msg.sender == ERC1967Utils.getAdmin()
@@ -2,10 +2,12 @@
 pragma solidity >=0.8.0;
 
 import {CallUtils} from "./utils/CallUtils.sol";
+import {ArchProxy} from "./ArchProxy.sol";
 import {console2} from "forge-std/Test.sol";
 
 contract ArchProxyAdmin {
     address public proxy;
+    address public owner;
 
     function _setProxy(address addr) internal {
         require(addr != address(0), "ArchProxyAdmin: invalid proxy address");
@@ -16,6 +18,19 @@ contract ArchProxyAdmin {
         proxy = addr;
     }
 
+    function _setOwner(address addr) internal {
+        owner = addr;
+    }
+
+    function upgradeProxy(address newLogic, bytes memory data) external {
+        require(
+            msg.sender == owner,
+            "ArchProxyAdmin: caller is not the owner"
+        );
+        require(address(proxy) != address(0), "ArchProxyAdmin: proxy not set");
+        ArchProxy(payable(proxy)).upgradeToAndCall(newLogic, data);
+    }
+
     function _fallback() internal view {
         require(address(proxy) != address(0), "ArchProxyAdmin: proxy not set");
         CallUtils.forwardStaticcall(address(proxy));
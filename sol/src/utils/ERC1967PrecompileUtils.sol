@@ -1,7 +1,6 @@
 // SPDX-License-Identifier: MIT
 pragma solidity >=0.8.0;
 
-import {Address} from "openzeppelin/utils/Address.sol";
 import {StorageSlot} from "openzeppelin/utils/StorageSlot.sol";
 import {ERC1967Utils} from "openzeppelin/proxy/ERC1967/ERC1967Utils.sol";
 
@@ -20,12 +19,23 @@ library ERC1967PrecompileUtils {
         emit ERC1967Utils.Upgraded(newImplementation);
 
         if (data.length > 0) {
-            Address.functionDelegateCall(newImplementation, data);
+            _delegatecall(newImplementation, data);
         } else {
             _checkNonPayable();
         }
     }
 
+    // Address.functionDelegateCall reverts if the target has no code, which is always the
+    // case for precompiles, so the call is made directly, as in Proxy._delegate.
+    function _delegatecall(address target, bytes memory data) private {
+        (bool success, bytes memory returndata) = target.delegatecall(data);
+        if (!success) {
+            assembly {
+                revert(add(returndata, 0x20), mload(returndata))
+            }
+        }
+    }
+
     function _checkNonPayable() private {
         if (msg.value > 0) {
             revert ERC1967Utils.ERC1967NonPayable();
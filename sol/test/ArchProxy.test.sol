@@ -2,6 +2,7 @@
 pragma solidity >=0.8.0;
 
 import {Test, console2} from "forge-std/Test.sol";
+import {ERC1967Utils} from "openzeppelin/proxy/ERC1967/ERC1967Utils.sol";
 import {ArchProxy} from "../src/ArchProxy.sol";
 
 contract TestLogic {
@@ -11,6 +12,16 @@ contract TestLogic {
     }
 }
 
+contract TestLogicV2 is TestLogic {
+    function doubleValue() public {
+        value *= 2;
+    }
+
+    function failMigration() public pure {
+        revert("TestLogicV2: migration failed");
+    }
+}
+
 contract ArchProxyTest is Test {
     TestLogic internal logic;
     ArchProxy internal proxy;
@@ -56,4 +67,53 @@ contract ArchProxyTest is Test {
         vm.prank(address(0));
         TestLogic(address(proxy)).setValue(42);
     }
+
+    function testUpgrade() public {
+        TestLogic(address(proxy)).setValue(21);
+        TestLogicV2 logicV2 = new TestLogicV2();
+        proxy.upgradeToAndCall(
+            address(logicV2),
+            abi.encodeCall(TestLogicV2.doubleValue, ())
+        );
+
+        // Storage is kept across upgrades
+        assertEq(TestLogic(address(proxy)).value(), 42, "upgrade call failed");
+        TestLogicV2(address(proxy)).doubleValue();
+        assertEq(TestLogic(address(proxy)).value(), 84, "upgraded call failed");
+    }
+
+    function testUpgradeToCodelessLogicWithData() public {
+        // Precompiles have no code, so the migration call must not require it
+        address precompile = address(0x80);
+        proxy.upgradeToAndCall(
+            precompile,
+            abi.encodeCall(TestLogicV2.doubleValue, ())
+        );
+        assertEq(
+            address(
+                uint160(
+                    uint256(
+                        vm.load(address(proxy), ERC1967Utils.IMPLEMENTATION_SLOT)
+                    )
+                )
+            ),
+            precompile,
+            "implementation not upgraded"
+        );
+    }
+
+    function testUpgradeBubblesMigrationRevert() public {
+        TestLogicV2 logicV2 = new TestLogicV2();
+        vm.expectRevert("TestLogicV2: migration failed");
+        proxy.upgradeToAndCall(
+            address(logicV2),
+            abi.encodeCall(TestLogicV2.failMigration, ())
+        );
+    }
+
+    function testFailUpgradeFromNonAdmin() public {
+        TestLogicV2 logicV2 = new TestLogicV2();
+        vm.prank(address(0));
+        proxy.upgradeToAndCall(address(logicV2), "");
+    }
 }
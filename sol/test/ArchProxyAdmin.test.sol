@@ -12,11 +12,21 @@ contract TestLogic {
     }
 }
 
+contract TestLogicV2 is TestLogic {
+    function doubleValue() public {
+        value *= 2;
+    }
+}
+
 contract TestAdmin is ArchProxyAdmin {
     function setProxy(address addr) public {
         _setProxy(addr);
     }
 
+    function setOwner(address addr) public {
+        _setOwner(addr);
+    }
+
     function setValue(uint256 _value) public {
         TestLogic(address(proxy)).setValue(_value);
     }
@@ -32,6 +42,7 @@ contract ArchProxyAdminTest is Test {
         logic = new TestLogic();
         proxy = new ArchProxy(address(admin), address(logic), "");
         admin.setProxy(address(proxy));
+        admin.setOwner(address(this));
     }
 
     function testProxyAdmin() public {
@@ -57,4 +68,24 @@ contract ArchProxyAdminTest is Test {
             "logic should not be affected by proxy write"
         );
     }
+
+    function testUpgradeProxy() public {
+        admin.setValue(21);
+        TestLogicV2 logicV2 = new TestLogicV2();
+        admin.upgradeProxy(
+            address(logicV2),
+            abi.encodeCall(TestLogicV2.doubleValue, ())
+        );
+        assertEq(
+            TestLogic(address(proxy)).value(),
+            42,
+            "upgrade call through the admin failed"
+        );
+    }
+
+    function testFailUpgradeProxyFromNonOwner() public {
+        TestLogicV2 logicV2 = new TestLogicV2();
+        vm.prank(address(0));
+        admin.upgradeProxy(address(logicV2), "");
+    }
 }
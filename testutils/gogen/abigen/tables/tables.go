@@ -36,7 +36,7 @@ type RowDataCounter struct {
 
 // ContractMetaData contains all meta data concerning the Contract contract.
 var ContractMetaData = &bind.MetaData{
-	ABI: "[{\"type\":\"function\",\"name\":\"getCounterRow\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structRowData_Counter\",\"components\":[{\"name\":\"value\",\"type\":\"int16\",\"internalType\":\"int16\"}]}],\"stateMutability\":\"view\"}]",
+	ABI: "[{\"type\":\"function\",\"name\":\"getCounterRow\",\"inputs\":[],\"outputs\":[{\"name\":\"\",\"type\":\"tuple\",\"internalType\":\"structRowData_Counter\",\"components\":[{\"name\":\"value\",\"type\":\"int16\",\"internalType\":\"int16\"}]}],\"stateMutability\":\"view\"},{\"type\":\"function\",\"name\":\"readMultipleTables\",\"inputs\":[{\"name\":\"calls\",\"type\":\"bytes[]\",\"internalType\":\"bytes[]\"}],\"outputs\":[{\"name\":\"\",\"type\":\"bytes[]\",\"internalType\":\"bytes[]\"}],\"stateMutability\":\"view\"}]",
 }
 
 // ContractABI is the input ABI used to generate the binding from.
@@ -215,3 +215,34 @@ func (_Contract *ContractSession) GetCounterRow() (RowDataCounter, error) {
 func (_Contract *ContractCallerSession) GetCounterRow() (RowDataCounter, error) {
 	return _Contract.Contract.GetCounterRow(&_Contract.CallOpts)
 }
+
+// ReadMultipleTables is a free data retrieval call binding the contract method 0x988f7366.
+//
+// Solidity: function readMultipleTables(bytes[] calls) view returns(bytes[])
+func (_Contract *ContractCaller) ReadMultipleTables(opts *bind.CallOpts, calls [][]byte) ([][]byte, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "readMultipleTables", calls)
+
+	if err != nil {
+		return *new([][]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([][]byte)).(*[][]byte)
+
+	return out0, err
+
+}
+
+// ReadMultipleTables is a free data retrieval call binding the contract method 0x988f7366.
+//
+// Solidity: function readMultipleTables(bytes[] calls) view returns(bytes[])
+func (_Contract *ContractSession) ReadMultipleTables(calls [][]byte) ([][]byte, error) {
+	return _Contract.Contract.ReadMultipleTables(&_Contract.CallOpts, calls)
+}
+
+// ReadMultipleTables is a free data retrieval call binding the contract method 0x988f7366.
+//
+// Solidity: function readMultipleTables(bytes[] calls) view returns(bytes[])
+func (_Contract *ContractCallerSession) ReadMultipleTables(calls [][]byte) ([][]byte, error) {
+	return _Contract.Contract.ReadMultipleTables(&_Contract.CallOpts, calls)
+}
@@ -12,7 +12,15 @@ import {ArchProxyAdmin} from "arch/ArchProxyAdmin.sol";
 import {ArchProxy} from "arch/ArchProxy.sol";
 
 abstract contract Arch is Entrypoint, ArchProxyAdmin, Initializable {
-    function initialize(address _logic) public initializer {
+    constructor() {
+        // Set the owner on deployment so initialize cannot be front-run
+        _setOwner(msg.sender);
+    }
+
+    function initialize(
+        address _logic,
+        bytes memory data
+    ) public onlyOwner initializer {
         address proxyAddress = address(
             new ArchProxy(address(this), _logic, "")
         );
@@ -20,12 +28,16 @@ abstract contract Arch is Entrypoint, ArchProxyAdmin, Initializable {
         _initialize(data);
     }
 
-    function _initialize() internal virtual;
+    function _initialize(bytes memory data) internal virtual;
 
     uint256 public lastTickBlockNumber;
 
-    function tick() public override {
+    function tick() public virtual override {
         require(block.number > lastTickBlockNumber, "already ticked");
         ICore(proxy).tick();
     }
+
+    function _checkOwner() internal view override {
+        require(msg.sender == owner, "Arch: caller is not the owner");
+    }
 }
@@ -7,6 +7,11 @@ pragma solidity >=0.8.0;
 import "./IActions.sol";
 
 abstract contract Entrypoint is IActions {
+    modifier onlyOwner() {
+        _checkOwner();
+        _;
+    }
+
     function executeMultipleActions(
         uint32[] memory actionIds,
         uint8[] memory actionCount,
@@ -37,6 +42,8 @@ abstract contract Entrypoint is IActions {
         }
     }
 
+    function _checkOwner() internal view virtual;
+
     function tick() public virtual;
     
     function add(ActionData_Add memory action) public virtual;
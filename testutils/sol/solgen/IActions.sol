@@ -12,6 +12,8 @@ struct ActionData_Add {
 interface IActions {
     event ActionExecuted(bytes4 actionId, bytes data);
 
+    error CounterOverflow(int16 counter, int16 summand);
+
     function tick() external;
 
 
@@ -6,5 +6,6 @@ pragma solidity >=0.8.0;
 
 import "./IActions.sol";
 import "./ITables.sol";
+import "./IEvents.sol";
 
-interface ICore is IActions, ITables {}
+interface ICore is IActions, ITables, IEvents {}
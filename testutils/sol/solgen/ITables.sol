@@ -11,4 +11,7 @@ struct RowData_Counter {
 
 interface ITables {
     function getCounterRow() external view returns (RowData_Counter memory);
+    function readMultipleTables(
+        bytes[] calldata calls
+    ) external view returns (bytes[] memory);
 }
@@ -10,8 +10,10 @@ import (
 )
 
 type (
-	ActionData_Add  = archmod.ActionData_Add
-	RowData_Counter = archmod.RowData_Counter
+	ActionData_Add              = archmod.ActionData_Add
+	ActionError_CounterOverflow = archmod.ActionError_CounterOverflow
+	Event_CounterUpdated        = archmod.Event_CounterUpdated
+	RowData_Counter             = archmod.RowData_Counter
 )
 
 func safeAddInt16(a, b int16) (int16, bool) {
@@ -42,8 +44,13 @@ func (c *Core) GetCounter() int16 {
 
 func (c *Core) add(summand int16) error {
 	counter := c.GetCounter()
-	if res, ok := safeAddInt16(counter, summand); ok {
-		c.SetCounter(res)
+	res, ok := safeAddInt16(counter, summand)
+	if !ok {
+		return &archmod.ActionError_CounterOverflow{Counter: counter, Summand: summand}
+	}
+	c.SetCounter(res)
+	if res != counter {
+		c.EmitEvent(&archmod.Event_CounterUpdated{Previous: counter, Value: res})
 	}
 	return nil
 }
@@ -71,6 +78,7 @@ func NewTestArchSchemas(t *testing.T) arch.ArchSchemas {
 	return arch.ArchSchemas{
 		Actions: archmod.ActionSchemas,
 		Tables:  archmod.TableSchemas,
+		Events:  archmod.EventSchemas,
 	}
 }
 